@@ -6,13 +6,17 @@ import (
 	"github.com/adrianliechti/wingman/pkg/auth"
 	"github.com/adrianliechti/wingman/pkg/extractor"
 	"github.com/adrianliechti/wingman/pkg/guard"
+	"github.com/adrianliechti/wingman/pkg/index"
 	"github.com/adrianliechti/wingman/pkg/mcp"
+	"github.com/adrianliechti/wingman/pkg/pipeline"
 	"github.com/adrianliechti/wingman/pkg/policy"
 	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/researcher"
+	"github.com/adrianliechti/wingman/pkg/router"
 	"github.com/adrianliechti/wingman/pkg/scraper"
 	"github.com/adrianliechti/wingman/pkg/searcher"
 	"github.com/adrianliechti/wingman/pkg/segmenter"
+	"github.com/adrianliechti/wingman/pkg/store"
 	"github.com/adrianliechti/wingman/pkg/summarizer"
 	"github.com/adrianliechti/wingman/pkg/tool"
 	"github.com/adrianliechti/wingman/pkg/translator"
@@ -26,6 +30,8 @@ type Config struct {
 	Policy      policy.Provider
 	Authorizers []auth.Provider
 
+	Store store.Provider
+
 	models map[string]provider.Model
 
 	completer   map[string]provider.Completer
@@ -42,6 +48,13 @@ type Config struct {
 
 	guard map[string]guard.Provider
 
+	index map[string]index.Provider
+
+	pipelines     map[string]*pipeline.Pipeline
+	pipelineIndex map[string]string
+
+	routers map[string]*router.Completer
+
 	scraper    map[string]scraper.Provider
 	searcher   map[string]searcher.Provider
 	researcher map[string]researcher.Provider
@@ -49,10 +62,25 @@ type Config struct {
 	tools  map[string]tool.Provider
 	agents map[string]provider.Completer
 
+	hostedTools       map[string]*HostedTools
+	hostedToolConfigs map[string]hostedToolsConfig
+
+	embedderRefs []*embedderRef
+
 	mcps map[string]mcp.Provider
+
+	state       *State
+	stateValues map[string]any
 }
 
 func Parse(path string) (*Config, error) {
+	return ParseState(path, nil)
+}
+
+// ParseState parses the config file, taking over the stateful subsystems
+// kept in state; see State. Pass the result to State.Retain once it is in
+// use.
+func ParseState(path string, state *State) (*Config, error) {
 	file, err := parseFile(path)
 
 	if err != nil {
@@ -61,69 +89,103 @@ func Parse(path string) (*Config, error) {
 
 	c := &Config{
 		Address: ":8080",
+
+		state: state,
 	}
 
-	if err := c.registerAuthorizer(file); err != nil {
+	if err := c.parse(file); err != nil {
+		if state != nil {
+			state.discard(c)
+		}
+
 		return nil, err
 	}
 
+	return c, nil
+}
+
+func (c *Config) parse(file *configFile) error {
+	if err := c.registerAuthorizer(file); err != nil {
+		return err
+	}
+
 	if err := c.registerPolicies(file); err != nil {
-		return nil, err
+		return err
+	}
+
+	if err := c.registerStore(file); err != nil {
+		return err
+	}
+
+	if err := c.registerGuards(file); err != nil {
+		return err
 	}
 
 	if err := c.registerProviders(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerRouters(file); err != nil {
-		return nil, err
+		return err
+	}
+
+	if err := c.registerIndexes(file); err != nil {
+		return err
 	}
 
 	if err := c.registerExtractors(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerScrapers(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerSegmenters(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerSummarizers(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerSearchers(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerTranslators(file); err != nil {
-		return nil, err
+		return err
 	}
 
-	if err := c.registerGuards(file); err != nil {
-		return nil, err
+	if err := c.registerResearchers(file); err != nil {
+		return err
 	}
 
-	if err := c.registerResearchers(file); err != nil {
-		return nil, err
+	if err := c.registerHostedTools(); err != nil {
+		return err
+	}
+
+	if err := c.registerPipelines(file); err != nil {
+		return err
 	}
 
 	if err := c.registerTools(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerAgents(file); err != nil {
-		return nil, err
+		return err
 	}
 
 	if err := c.registerMCP(file); err != nil {
-		return nil, err
+		return err
 	}
 
-	return c, nil
+	if err := c.registerLimits(file); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 type configFile struct {
@@ -131,6 +193,10 @@ type configFile struct {
 
 	Policy *policyConfig `yaml:"policy"`
 
+	Store *storeConfig `yaml:"store"`
+
+	Limits *limitsConfig `yaml:"limits"`
+
 	Providers []providerConfig `yaml:"providers"`
 
 	Extractors  yaml.Node `yaml:"extractors"`
@@ -140,6 +206,9 @@ type configFile struct {
 
 	Guards yaml.Node `yaml:"guards"`
 
+	Indexes   yaml.Node `yaml:"indexes"`
+	Pipelines yaml.Node `yaml:"pipelines"`
+
 	Scrapers    yaml.Node `yaml:"scrapers"`
 	Searchers   yaml.Node `yaml:"searchers"`
 	Researchers yaml.Node `yaml:"researchers"`
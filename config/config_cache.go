@@ -0,0 +1,100 @@
+package config
+
+import (
+	"cmp"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/adapter/cache"
+)
+
+// cacheConfig enables the response cache on a model.
+type cacheConfig struct {
+	// TTL is how long a cached completion is served (e.g. "1h"). Defaults to 1h
+	TTL string `yaml:"ttl"`
+
+	// Capacity caps the number of cached completions. Defaults to 1000
+	Capacity int `yaml:"capacity"`
+
+	// Embedder is the model id of an embedder enabling semantic matches of
+	// the last user turn. Omit for exact matches only.
+	Embedder string `yaml:"embedder"`
+
+	// Threshold is the minimum cosine similarity of a semantic match.
+	// Defaults to 0.95
+	Threshold float32 `yaml:"threshold"`
+}
+
+func (cfg *Config) cacheCompleter(id string, config *cacheConfig, completer provider.Completer) (provider.Completer, error) {
+	if config == nil {
+		return completer, nil
+	}
+
+	var options []cache.Option
+
+	if config.TTL != "" {
+		ttl, err := parseTimeout("cache ttl", config.TTL)
+
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, cache.WithTTL(ttl))
+	}
+
+	// cached answers are handed over on reload
+	store, err := cfg.stateful("cache", id, *config, func(previous any) (any, error) {
+		if previous != nil {
+			return previous, nil
+		}
+
+		return cache.NewStore(cmp.Or(config.Capacity, 1000)), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(options, cache.WithStore(store.(*cache.Store)))
+
+	if config.Embedder != "" {
+		threshold := config.Threshold
+
+		if threshold <= 0 {
+			threshold = 0.95
+		}
+
+		ref := &embedderRef{id: config.Embedder}
+		cfg.embedderRefs = append(cfg.embedderRefs, ref)
+
+		options = append(options, cache.WithEmbedder(ref, threshold))
+	}
+
+	return cache.FromCompleter(completer, options...), nil
+}
+
+// embedderRef is an embedder bound once all providers are registered, so a
+// model may reference an embedder declared further down in the providers
+// list.
+type embedderRef struct {
+	id string
+
+	provider.Embedder
+}
+
+// resolveEmbedderRefs binds the embedder references of the providers and
+// fails the config on an unknown id.
+func (cfg *Config) resolveEmbedderRefs() error {
+	for _, ref := range cfg.embedderRefs {
+		embedder, err := cfg.Embedder(ref.id)
+
+		if err != nil {
+			return err
+		}
+
+		ref.Embedder = embedder
+	}
+
+	cfg.embedderRefs = nil
+
+	return nil
+}
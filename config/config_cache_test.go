@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCacheEmbedderMustExist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	data := `
+providers:
+  - type: openai
+    token: test
+    models:
+      gpt-5:
+        cache:
+          embedder: missing
+`
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Parse(path); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected unknown embedder to fail the config, got %v", err)
+	}
+}
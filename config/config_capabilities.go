@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tokens"
+)
+
+type capabilitiesConfig struct {
+	ContextWindow   int `yaml:"context_window"`
+	MaxOutputTokens int `yaml:"max_output_tokens"`
+
+	Input  []provider.Modality `yaml:"input"`
+	Output []provider.Modality `yaml:"output"`
+
+	Tools            *bool `yaml:"tools"`
+	Reasoning        *bool `yaml:"reasoning"`
+	StructuredOutput *bool `yaml:"structured_output"`
+	Choices          *bool `yaml:"choices"`
+
+	DeprecationDate string `yaml:"deprecation_date"`
+}
+
+// modelCapabilities returns the capabilities of a model: the configured ones
+// on top of the defaults of its provider and type.
+func modelCapabilities(providerType string, m modelConfig) (provider.ModelCapabilities, error) {
+	result := defaultCapabilities(providerType, m.Type, m.ID)
+
+	// output guardrails check one answer at a time
+	if m.Guardrails != nil && m.Guardrails.Output {
+		result.Choices = new(false)
+	}
+
+	c := m.Capabilities
+
+	if c == nil {
+		return result, nil
+	}
+
+	if c.ContextWindow > 0 {
+		result.ContextWindow = c.ContextWindow
+	}
+
+	if c.MaxOutputTokens > 0 {
+		result.MaxOutputTokens = c.MaxOutputTokens
+	}
+
+	if len(c.Input) > 0 {
+		result.InputModalities = c.Input
+	}
+
+	if len(c.Output) > 0 {
+		result.OutputModalities = c.Output
+	}
+
+	if c.Tools != nil {
+		result.Tools = c.Tools
+	}
+
+	if c.Reasoning != nil {
+		result.Reasoning = c.Reasoning
+	}
+
+	if c.StructuredOutput != nil {
+		result.StructuredOutput = c.StructuredOutput
+	}
+
+	if c.Choices != nil && (m.Guardrails == nil || !m.Guardrails.Output) {
+		result.Choices = c.Choices
+	}
+
+	if c.DeprecationDate != "" {
+		date, err := time.Parse(time.DateOnly, c.DeprecationDate)
+
+		if err != nil {
+			return result, fmt.Errorf("invalid deprecation_date for model %s: %w", m.ID, err)
+		}
+
+		result.DeprecationDate = &date
+	}
+
+	return result, nil
+}
+
+// defaultCapabilities covers what a provider's API accepts for all of its
+// models. Limits vary per model and are left to the config.
+func defaultCapabilities(providerType string, modelType ModelType, id string) provider.ModelCapabilities {
+	switch modelType {
+	case ModelTypeEmbedder, ModelTypeReranker:
+		return provider.ModelCapabilities{
+			InputModalities: []provider.Modality{provider.ModalityText},
+		}
+
+	case ModelTypeRenderer:
+		return provider.ModelCapabilities{
+			InputModalities:  []provider.Modality{provider.ModalityText},
+			OutputModalities: []provider.Modality{provider.ModalityImage},
+		}
+
+	case ModelTypeSynthesizer:
+		return provider.ModelCapabilities{
+			InputModalities:  []provider.Modality{provider.ModalityText},
+			OutputModalities: []provider.Modality{provider.ModalityAudio},
+		}
+
+	case ModelTypeTranscriber:
+		return provider.ModelCapabilities{
+			InputModalities:  []provider.Modality{provider.ModalityAudio},
+			OutputModalities: []provider.Modality{provider.ModalityText},
+		}
+	}
+
+	result := provider.ModelCapabilities{
+		OutputModalities: []provider.Modality{provider.ModalityText},
+	}
+
+	name := strings.ToLower(id)
+
+	switch providerType {
+	case "openai":
+		result.InputModalities = []provider.Modality{provider.ModalityText, provider.ModalityImage, provider.ModalityAudio, provider.ModalityFile}
+
+	case "anthropic":
+		result.InputModalities = []provider.Modality{provider.ModalityText, provider.ModalityImage, provider.ModalityFile}
+
+	case "gemini", "google":
+		result.InputModalities = []provider.Modality{provider.ModalityText, provider.ModalityImage, provider.ModalityAudio, provider.ModalityVideo, provider.ModalityFile}
+
+	case "bedrock":
+		if strings.Contains(name, "claude") {
+			result.InputModalities = []provider.Modality{provider.ModalityText, provider.ModalityImage, provider.ModalityFile}
+		}
+	}
+
+	switch providerType {
+	case "openai", "anthropic", "gemini", "google", "bedrock", "mistral", "xai":
+		result.Tools = new(true)
+	}
+
+	switch providerType {
+	case "openai", "anthropic", "gemini", "google", "xai":
+		result.StructuredOutput = new(true)
+	}
+
+	// n is a Chat Completions parameter, the Responses API behind the
+	// openai type has no equivalent
+	if providerType == "openai-compatible" {
+		result.Choices = new(true)
+	}
+
+	for _, family := range []string{"o1", "o3", "o4", "gpt-5", "claude-3-7", "claude-opus-4", "claude-sonnet-4", "claude-haiku-4", "gemini-2.5", "gemini-3", "deepseek-r1", "magistral", "qwq", "grok-4"} {
+		if strings.Contains(name, family) {
+			result.Reasoning = new(true)
+			break
+		}
+	}
+
+	return result
+}
+
+// contextWindowMargin is the share of the context window, in percent, an
+// input estimate may exceed it by. Estimates are approximate, so only an
+// input clearly too long for the model is rejected.
+const contextWindowMargin = 10
+
+// CheckCapabilities rejects a completion request that the capabilities of
+// the model rule out, before it is sent upstream. Models without known
+// capabilities accept everything.
+func (cfg *Config) CheckCapabilities(model string, messages []provider.Message, options *provider.CompleteOptions) error {
+	m, err := cfg.Model(model)
+
+	if err != nil {
+		return nil
+	}
+
+	c := m.Capabilities
+
+	if options == nil {
+		options = new(provider.CompleteOptions)
+	}
+
+	if len(c.InputModalities) > 0 {
+		for _, modality := range inputModalities(messages) {
+			if !slices.Contains(c.InputModalities, modality) {
+				return fmt.Errorf("model %s does not support %s input", model, modality)
+			}
+		}
+	}
+
+	if c.Tools != nil && !*c.Tools && len(options.Tools) > 0 {
+		return fmt.Errorf("model %s does not support tools", model)
+	}
+
+	if c.Reasoning != nil && !*c.Reasoning && requestsReasoning(options.ReasoningOptions) {
+		return fmt.Errorf("model %s does not support reasoning", model)
+	}
+
+	if c.StructuredOutput != nil && !*c.StructuredOutput && options.Schema != nil {
+		return fmt.Errorf("model %s does not support structured output", model)
+	}
+
+	if c.MaxOutputTokens > 0 && options.MaxTokens != nil && *options.MaxTokens > c.MaxOutputTokens {
+		return fmt.Errorf("max output tokens of %d exceed the limit of model %s (%d)", *options.MaxTokens, model, c.MaxOutputTokens)
+	}
+
+	if c.ContextWindow > 0 {
+		n := tokens.Estimate(model, tokens.Input{
+			Messages: messages,
+			Tools:    options.Tools,
+		})
+
+		if n > c.ContextWindow+c.ContextWindow*contextWindowMargin/100 {
+			return fmt.Errorf("input of about %d tokens exceeds the context window of model %s (%d)", n, model, c.ContextWindow)
+		}
+	}
+
+	return nil
+}
+
+// requestsReasoning reports whether the options ask the model to reason, as
+// opposed to disabling reasoning or only shaping its output, like asking for
+// signatures, which clients send regardless of the model.
+func requestsReasoning(o *provider.ReasoningOptions) bool {
+	if o == nil || o.Type == provider.ReasoningTypeDisabled {
+		return false
+	}
+
+	return o.Type == provider.ReasoningTypeAdaptive || o.Effort != ""
+}
+
+func inputModalities(messages []provider.Message) []provider.Modality {
+	var result []provider.Modality
+
+	add := func(f *provider.File) {
+		if f == nil {
+			return
+		}
+
+		if m := provider.ModalityOf(f.ContentType); !slices.Contains(result, m) {
+			result = append(result, m)
+		}
+	}
+
+	for _, m := range messages {
+		for _, c := range m.Content {
+			add(c.File)
+
+			if c.ToolResult != nil {
+				for _, part := range c.ToolResult.Parts {
+					add(part.File)
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tokens"
+)
+
+func TestModelCapabilities(t *testing.T) {
+	c, err := modelCapabilities("openai", modelConfig{
+		ID: "gpt-5",
+
+		Capabilities: &capabilitiesConfig{
+			ContextWindow: 400000,
+			Input:         []provider.Modality{provider.ModalityText},
+			Tools:         new(false),
+
+			DeprecationDate: "2027-01-31",
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.ContextWindow != 400000 || !slices.Equal(c.InputModalities, []provider.Modality{provider.ModalityText}) {
+		t.Errorf("configured limits not applied: %+v", c)
+	}
+
+	if c.Tools == nil || *c.Tools || c.Reasoning == nil || !*c.Reasoning || c.StructuredOutput == nil || !*c.StructuredOutput {
+		t.Errorf("unexpected feature flags: %+v", c)
+	}
+
+	if c.DeprecationDate == nil || c.DeprecationDate.Format("2006-01-02") != "2027-01-31" {
+		t.Errorf("deprecation date = %v", c.DeprecationDate)
+	}
+
+	if _, err := modelCapabilities("openai", modelConfig{ID: "gpt-5", Capabilities: &capabilitiesConfig{DeprecationDate: "soon"}}); err == nil {
+		t.Error("expected error for invalid deprecation date")
+	}
+
+	if c.Choices != nil {
+		t.Errorf("expected no native choices for the responses api, got %v", *c.Choices)
+	}
+
+	if c, _ := modelCapabilities("openai-compatible", modelConfig{ID: "gpt-5"}); c.Choices == nil || !*c.Choices {
+		t.Errorf("expected native choices for chat completions, got %v", c.Choices)
+	}
+
+	guarded, err := modelCapabilities("openai-compatible", modelConfig{
+		ID: "gpt-5",
+
+		Guardrails:   &guardrailConfig{Output: true},
+		Capabilities: &capabilitiesConfig{Choices: new(true)},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if guarded.Choices == nil || *guarded.Choices {
+		t.Errorf("expected output guardrails to disable native choices, got %v", guarded.Choices)
+	}
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	cfg := &Config{}
+
+	cfg.DescribeModel(provider.Model{
+		ID: "small",
+
+		Capabilities: provider.ModelCapabilities{
+			ContextWindow:   10,
+			MaxOutputTokens: 100,
+
+			InputModalities: []provider.Modality{provider.ModalityText},
+
+			Tools:     new(false),
+			Reasoning: new(false),
+		},
+	})
+
+	image := provider.Message{
+		Role: provider.MessageRoleUser,
+
+		Content: []provider.Content{
+			provider.FileContent(&provider.File{Content: []byte("png"), ContentType: "image/png"}),
+		},
+	}
+
+	long := provider.UserMessage(strings.Repeat("lorem ipsum ", 100))
+
+	tests := []struct {
+		name     string
+		model    string
+		messages []provider.Message
+		options  *provider.CompleteOptions
+		want     string
+	}{
+		{
+			name:     "supported",
+			model:    "small",
+			messages: []provider.Message{provider.UserMessage("hi")},
+		},
+		{
+			name:     "unknown model",
+			model:    "other",
+			messages: []provider.Message{image, long},
+		},
+		{
+			name:     "image input",
+			model:    "small",
+			messages: []provider.Message{image},
+			want:     "does not support image input",
+		},
+		{
+			name:     "tools",
+			model:    "small",
+			messages: []provider.Message{provider.UserMessage("hi")},
+			options:  &provider.CompleteOptions{Tools: []provider.Tool{{Name: "lookup"}}},
+			want:     "does not support tools",
+		},
+		{
+			name:     "reasoning",
+			model:    "small",
+			messages: []provider.Message{provider.UserMessage("hi")},
+			options:  &provider.CompleteOptions{ReasoningOptions: &provider.ReasoningOptions{Effort: provider.EffortHigh}},
+			want:     "does not support reasoning",
+		},
+		{
+			name:     "reasoning disabled",
+			model:    "small",
+			messages: []provider.Message{provider.UserMessage("hi")},
+			options:  &provider.CompleteOptions{ReasoningOptions: &provider.ReasoningOptions{Type: provider.ReasoningTypeDisabled, IncludeSignature: true}},
+		},
+		{
+			name:     "max tokens",
+			model:    "small",
+			messages: []provider.Message{provider.UserMessage("hi")},
+			options:  &provider.CompleteOptions{MaxTokens: new(1000)},
+			want:     "exceed the limit",
+		},
+		{
+			name:     "context window",
+			model:    "small",
+			messages: []provider.Message{long},
+			want:     "exceeds the context window",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.CheckCapabilities(tt.model, tt.messages, tt.options)
+
+			if tt.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCapabilitiesContextMargin(t *testing.T) {
+	messages := []provider.Message{provider.UserMessage(strings.Repeat("lorem ipsum ", 100))}
+
+	n := tokens.Estimate("tight", tokens.Input{Messages: messages})
+
+	cfg := &Config{}
+
+	cfg.DescribeModel(provider.Model{
+		ID: "tight",
+
+		Capabilities: provider.ModelCapabilities{
+			ContextWindow: n - n/20,
+		},
+	})
+
+	if err := cfg.CheckCapabilities("tight", messages, nil); err != nil {
+		t.Fatalf("estimate within the margin rejected: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/provider/anthropic"
 	"github.com/adrianliechti/wingman/pkg/provider/bedrock"
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
 	"github.com/adrianliechti/wingman/pkg/provider/google"
 	"github.com/adrianliechti/wingman/pkg/provider/openai"
 	"github.com/adrianliechti/wingman/pkg/provider/xai"
@@ -50,6 +51,9 @@ func createCompleter(cfg providerConfig, model modelContext) (provider.Completer
 	case "bedrock":
 		return bedrockCompleter(cfg, model)
 
+	case "custom":
+		return customCompleter(cfg, model)
+
 	case "gemini", "google":
 		return googleCompleter(cfg, model)
 
@@ -124,6 +128,12 @@ func bedrockCompleter(cfg providerConfig, model modelContext) (provider.Complete
 	return bedrock.NewCompleter(model.ID, options...)
 }
 
+func customCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
+	var options []custom.Option
+
+	return custom.NewCompleter(cfg.URL, model.ID, options...)
+}
+
 func googleCompleter(cfg providerConfig, model modelContext) (provider.Completer, error) {
 	var options []google.Option
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/custom"
 	"github.com/adrianliechti/wingman/pkg/provider/google"
 	"github.com/adrianliechti/wingman/pkg/provider/openai"
 )
@@ -35,6 +36,9 @@ func (cfg *Config) Embedder(id string) (provider.Embedder, error) {
 
 func createEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
 	switch strings.ToLower(cfg.Type) {
+	case "custom":
+		return customEmbedder(cfg, model)
+
 	case "gemini", "google":
 		return googleEmbedder(cfg, model)
 
@@ -72,6 +76,12 @@ func createEmbedder(cfg providerConfig, model modelContext) (provider.Embedder,
 	}
 }
 
+func customEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
+	var options []custom.Option
+
+	return custom.NewEmbedder(cfg.URL, model.ID, options...)
+}
+
 func googleEmbedder(cfg providerConfig, model modelContext) (provider.Embedder, error) {
 	var options []google.Option
 
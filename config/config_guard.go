@@ -10,6 +10,8 @@ import (
 	"github.com/adrianliechti/wingman/pkg/guard/multi"
 	"github.com/adrianliechti/wingman/pkg/guard/openai"
 	"github.com/adrianliechti/wingman/pkg/otel"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/adapter/guardrail"
 )
 
 func (cfg *Config) RegisterGuard(id string, p guard.Provider) {
@@ -101,6 +103,46 @@ func (cfg *Config) registerGuards(f *configFile) error {
 	return nil
 }
 
+// guardrailConfig applies guards inline to a model's or router's completion
+// traffic. Guard names a configured guard; empty uses all of them.
+type guardrailConfig struct {
+	Guard string `yaml:"guard"`
+
+	// Input checks the latest input turn (default true), Output the
+	// generated answer (default false).
+	Input  *bool `yaml:"input"`
+	Output bool  `yaml:"output"`
+
+	// Action is one of block (default), redact or annotate.
+	Action string `yaml:"action"`
+}
+
+func (cfg *Config) guardrailCompleter(config *guardrailConfig, completer provider.Completer) (provider.Completer, error) {
+	if config == nil {
+		return completer, nil
+	}
+
+	g, err := cfg.Guard(config.Guard)
+
+	if err != nil {
+		return nil, err
+	}
+
+	options := []guardrail.Option{
+		guardrail.WithOutput(config.Output),
+	}
+
+	if config.Input != nil {
+		options = append(options, guardrail.WithInput(*config.Input))
+	}
+
+	if config.Action != "" {
+		options = append(options, guardrail.WithAction(guardrail.Action(strings.ToLower(config.Action))))
+	}
+
+	return guardrail.FromCompleter(completer, g, options...)
+}
+
 func createGuard(cfg guardConfig, context guardContext) (guard.Provider, error) {
 	switch strings.ToLower(cfg.Type) {
 	case "openai":
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/scraper"
+	"github.com/adrianliechti/wingman/pkg/searcher"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+// HostedTools are the tools the gateway runs itself for a model when a
+// Responses request asks for one of the hosted tool types. file_search needs
+// no binding: its vector stores are the configured indexes.
+type HostedTools struct {
+	WebSearch *HostedWebSearch
+
+	CodeInterpreter tool.Provider
+	ImageGeneration provider.Renderer
+}
+
+// HostedWebSearch backs the web_search tool. The scraper is optional and
+// lets the model open the pages it found.
+type HostedWebSearch struct {
+	Searcher searcher.Provider
+	Scraper  scraper.Provider
+}
+
+func (cfg *Config) RegisterHostedTools(model string, tools *HostedTools) {
+	if cfg.hostedTools == nil {
+		cfg.hostedTools = make(map[string]*HostedTools)
+	}
+
+	cfg.hostedTools[model] = tools
+}
+
+// HostedTools returns the hosted tools bound to a model, or nil.
+func (cfg *Config) HostedTools(model string) *HostedTools {
+	if cfg.hostedTools == nil {
+		return nil
+	}
+
+	return cfg.hostedTools[model]
+}
+
+type hostedToolsConfig struct {
+	WebSearch *hostedWebSearchConfig `yaml:"web_search"`
+
+	CodeInterpreter *hostedCodeInterpreterConfig `yaml:"code_interpreter"`
+	ImageGeneration *hostedImageGenerationConfig `yaml:"image_generation"`
+}
+
+type hostedWebSearchConfig struct {
+	Searcher string `yaml:"searcher"`
+	Scraper  string `yaml:"scraper"`
+}
+
+type hostedCodeInterpreterConfig struct {
+	Python  string `yaml:"python"`
+	Timeout string `yaml:"timeout"`
+	Memory  int    `yaml:"memory"`
+	Network bool   `yaml:"network"`
+}
+
+type hostedImageGenerationConfig struct {
+	Renderer string `yaml:"renderer"`
+}
+
+// registerHostedTools resolves the hosted tools of the models once the
+// providers they reference are registered.
+func (cfg *Config) registerHostedTools() error {
+	for id, c := range cfg.hostedToolConfigs {
+		tools := &HostedTools{}
+
+		if c.WebSearch != nil {
+			searcher, err := cfg.Searcher(c.WebSearch.Searcher)
+
+			if err != nil {
+				return err
+			}
+
+			tools.WebSearch = &HostedWebSearch{
+				Searcher: searcher,
+			}
+
+			if c.WebSearch.Scraper != "" {
+				scraper, err := cfg.Scraper(c.WebSearch.Scraper)
+
+				if err != nil {
+					return err
+				}
+
+				tools.WebSearch.Scraper = scraper
+			}
+		}
+
+		if c.CodeInterpreter != nil {
+			interpreter, err := interpreterTool(toolConfig{
+				Python:  c.CodeInterpreter.Python,
+				Timeout: c.CodeInterpreter.Timeout,
+				Memory:  c.CodeInterpreter.Memory,
+				Network: c.CodeInterpreter.Network,
+			}, toolContext{})
+
+			if err != nil {
+				return err
+			}
+
+			tools.CodeInterpreter = interpreter
+		}
+
+		if c.ImageGeneration != nil {
+			renderer, err := cfg.Renderer(c.ImageGeneration.Renderer)
+
+			if err != nil {
+				return err
+			}
+
+			tools.ImageGeneration = renderer
+		}
+
+		cfg.RegisterHostedTools(id, tools)
+	}
+
+	return nil
+}
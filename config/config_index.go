@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/index/file"
+	"github.com/adrianliechti/wingman/pkg/index/memory"
+	"github.com/adrianliechti/wingman/pkg/otel"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+func (cfg *Config) RegisterIndex(id string, p index.Provider) {
+	if cfg.index == nil {
+		cfg.index = make(map[string]index.Provider)
+	}
+
+	cfg.index[id] = p
+}
+
+func (cfg *Config) Index(id string) (index.Provider, error) {
+	if cfg.index != nil {
+		if i, ok := cfg.index[id]; ok {
+			return i, nil
+		}
+	}
+
+	return nil, errors.New("index not found: " + id)
+}
+
+func (cfg *Config) Indexes() []string {
+	var result []string
+
+	for id := range cfg.index {
+		result = append(result, id)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+type indexConfig struct {
+	Type string `yaml:"type"`
+
+	// Path is the snapshot file used by the "file" index.
+	Path string `yaml:"path"`
+
+	// Embedder is the model id of the embedder used for documents and queries.
+	Embedder string `yaml:"embedder"`
+
+	// Limit is the default number of query results. Defaults to 10
+	Limit int `yaml:"limit"`
+}
+
+type indexContext struct {
+	Embedder provider.Embedder
+}
+
+func (cfg *Config) registerIndexes(f *configFile) error {
+	var configs map[string]indexConfig
+
+	if err := decodeStrict(&f.Indexes, &configs); err != nil {
+		return err
+	}
+
+	for _, node := range f.Indexes.Content {
+		id := node.Value
+
+		config, ok := configs[node.Value]
+
+		if !ok {
+			continue
+		}
+
+		context := indexContext{}
+
+		embedder, err := cfg.Embedder(config.Embedder)
+
+		if err != nil {
+			return err
+		}
+
+		context.Embedder = embedder
+
+		index, err := cfg.carryIndex(id, config, context)
+
+		if err != nil {
+			return err
+		}
+
+		if _, ok := index.(otel.Index); !ok {
+			index = otel.NewIndex(config.Type, id, index)
+		}
+
+		cfg.RegisterIndex(id, index)
+	}
+
+	return nil
+}
+
+// carryIndex creates the index. A memory index takes over the documents of
+// its predecessor with their embeddings, so a reload neither loses nor
+// re-embeds them; file indexes reload their snapshot anyway.
+func (cfg *Config) carryIndex(id string, config indexConfig, c indexContext) (index.Provider, error) {
+	if !strings.EqualFold(config.Type, "memory") {
+		return createIndex(config, c)
+	}
+
+	value, err := cfg.stateful("index", id, config, func(previous any) (any, error) {
+		i, err := createIndex(config, c)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if previous, ok := previous.(index.Provider); ok {
+			documents, err := previous.List(context.Background(), nil)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if len(documents) > 0 {
+				if _, err := i.Index(context.Background(), documents...); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return i, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(index.Provider), nil
+}
+
+func createIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "memory":
+		return memoryIndex(cfg, context)
+
+	case "file":
+		return fileIndex(cfg, context)
+
+	default:
+		return nil, errors.New("invalid index type: " + cfg.Type)
+	}
+}
+
+func indexOptions(cfg indexConfig) []memory.Option {
+	var options []memory.Option
+
+	if cfg.Limit > 0 {
+		options = append(options, memory.WithLimit(cfg.Limit))
+	}
+
+	return options
+}
+
+func memoryIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
+	return memory.New(context.Embedder, indexOptions(cfg)...)
+}
+
+func fileIndex(cfg indexConfig, context indexContext) (index.Provider, error) {
+	return file.New(cfg.Path, context.Embedder, indexOptions(cfg)...)
+}
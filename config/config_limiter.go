@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/limiter"
+	"github.com/adrianliechti/wingman/pkg/limiter/custom"
+	"github.com/adrianliechti/wingman/pkg/limiter/memory"
+	"github.com/adrianliechti/wingman/pkg/provider/adapter/ratelimit"
+)
+
+type limitsConfig struct {
+	// Type selects where counters live: memory (default, per replica) or
+	// custom (a shared gRPC service).
+	Type string `yaml:"type"`
+
+	URL string `yaml:"url"`
+
+	Rules []limitRuleConfig `yaml:"rules"`
+}
+
+type limitRuleConfig struct {
+	Users  []string `yaml:"users"`
+	Groups []string `yaml:"groups"`
+	Models []string `yaml:"models"`
+
+	Requests    int64 `yaml:"requests"`
+	Tokens      int64 `yaml:"tokens"`
+	DailyTokens int64 `yaml:"daily_tokens"`
+}
+
+// registerLimits wraps the completers and agents clients can address. It
+// runs last, so routers and agents keep calling their members directly and
+// usage is only counted against the model a client asked for.
+func (cfg *Config) registerLimits(f *configFile) error {
+	if f.Limits == nil || len(f.Limits.Rules) == 0 {
+		return nil
+	}
+
+	// counters are handed over on reload; the rules are not part of the
+	// key, so changing them keeps the usage counted so far
+	key := limitsConfig{Type: f.Limits.Type, URL: f.Limits.URL}
+
+	value, err := cfg.stateful("limiter", "", key, func(previous any) (any, error) {
+		if previous != nil {
+			return previous, nil
+		}
+
+		return createLimiter(*f.Limits)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	l := value.(limiter.Provider)
+
+	var rules []limiter.Rule
+
+	for _, r := range f.Limits.Rules {
+		rules = append(rules, limiter.Rule{
+			Users:  r.Users,
+			Groups: r.Groups,
+			Models: r.Models,
+
+			Requests:    r.Requests,
+			Tokens:      r.Tokens,
+			DailyTokens: r.DailyTokens,
+		})
+	}
+
+	for id, c := range cfg.completer {
+		cfg.completer[id] = ratelimit.FromCompleter(id, c, l, rules)
+	}
+
+	for id, a := range cfg.agents {
+		cfg.agents[id] = ratelimit.FromCompleter(id, a, l, rules)
+	}
+
+	return nil
+}
+
+func createLimiter(cfg limitsConfig) (limiter.Provider, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "", "memory":
+		return memory.New(), nil
+
+	case "custom":
+		return custom.New(cfg.URL)
+
+	default:
+		return nil, errors.New("invalid limiter type: " + cfg.Type)
+	}
+}
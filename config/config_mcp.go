@@ -3,13 +3,20 @@ package config
 import (
 	"errors"
 	"maps"
+	"net/url"
+	"path"
 	"slices"
 	"sort"
 	"strings"
 
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index"
 	"github.com/adrianliechti/wingman/pkg/mcp"
+	"github.com/adrianliechti/wingman/pkg/mcp/gateway"
 	"github.com/adrianliechti/wingman/pkg/mcp/proxy"
 	"github.com/adrianliechti/wingman/pkg/mcp/server"
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/template"
 	"github.com/adrianliechti/wingman/pkg/tool"
 )
 
@@ -50,8 +57,25 @@ type mcpConfig struct {
 
 	URL string `yaml:"url"`
 
+	// Command launches a local stdio MCP server, bridged to streamable HTTP
+	Command     string            `yaml:"command"`
+	Args        []string          `yaml:"args"`
+	Env         map[string]string `yaml:"env"`
+	Concurrency int               `yaml:"concurrency"`
+
 	Tools []string `yaml:"tools"`
 
+	// MCPs lists the MCPs a gateway aggregates
+	MCPs []string `yaml:"mcps"`
+
+	// Indexes and Resources are published as MCP resources; Extractor
+	// converts binary resources to text
+	Indexes   []string            `yaml:"indexes"`
+	Extractor string              `yaml:"extractor"`
+	Resources []mcpResourceConfig `yaml:"resources"`
+
+	Prompts map[string]mcpPromptConfig `yaml:"prompts"`
+
 	Vars  map[string]string `yaml:"vars"`
 	Auth  *authConfig       `yaml:"auth"`
 	Proxy *proxyConfig      `yaml:"proxy"`
@@ -59,8 +83,40 @@ type mcpConfig struct {
 	Instructions string `yaml:"instructions"`
 }
 
+type mcpResourceConfig struct {
+	URI string `yaml:"uri"`
+
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	MIMEType string `yaml:"mime_type"`
+}
+
+type mcpPromptConfig struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+
+	Arguments []mcpPromptArgumentConfig `yaml:"arguments"`
+
+	Template string `yaml:"template"`
+}
+
+type mcpPromptArgumentConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	Required bool `yaml:"required"`
+}
+
 type mcpContext struct {
 	Tools map[string]tool.Provider
+
+	Indexes   map[string]index.Provider
+	Extractor extractor.Provider
+
+	// Members holds the tools of the MCPs a gateway aggregates
+	Members map[string][]tool.Provider
+	Policy  policy.Provider
 }
 
 func (cfg *Config) registerMCP(f *configFile) error {
@@ -70,6 +126,9 @@ func (cfg *Config) registerMCP(f *configFile) error {
 		return err
 	}
 
+	// Gateways are registered last as they build on the other MCPs
+	var gateways []string
+
 	for _, node := range f.MCPs.Content {
 		id := node.Value
 
@@ -79,21 +138,57 @@ func (cfg *Config) registerMCP(f *configFile) error {
 			continue
 		}
 
+		if isGatewayMCP(config) {
+			gateways = append(gateways, id)
+			continue
+		}
+
+		context, err := cfg.mcpContext(config)
+
+		if err != nil {
+			return err
+		}
+
+		mcp, err := cfg.newMCP(id, config, context)
+
+		if err != nil {
+			return err
+		}
+
+		cfg.RegisterMCP(id, mcp)
+	}
+
+	for _, id := range gateways {
+		config := configs[id]
+
 		context := mcpContext{
-			Tools: make(map[string]tool.Provider),
+			Members: make(map[string][]tool.Provider),
+			Policy:  cfg.Policy,
 		}
 
-		for _, t := range config.Tools {
-			tool, err := cfg.Tool(t)
+		for _, m := range config.MCPs {
+			member, ok := configs[m]
+
+			if !ok || isGatewayMCP(member) {
+				return errors.New("mcp not found: " + m)
+			}
+
+			memberContext, err := cfg.mcpContext(member)
+
+			if err != nil {
+				return err
+			}
+
+			tools, err := mcpTools(member, memberContext)
 
 			if err != nil {
 				return err
 			}
 
-			context.Tools[t] = tool
+			context.Members[m] = tools
 		}
 
-		mcp, err := createMCP(config, context)
+		mcp, err := cfg.newMCP(id, config, context)
 
 		if err != nil {
 			return err
@@ -105,12 +200,75 @@ func (cfg *Config) registerMCP(f *configFile) error {
 	return nil
 }
 
+// newMCP creates the MCP id. It is built anew on every load, as it serves
+// the tools of this config, and handed over through the state, which closes
+// the MCP of the previous config, stopping its refresh, once this one is
+// retained.
+func (cfg *Config) newMCP(id string, config mcpConfig, context mcpContext) (mcp.Provider, error) {
+	value, err := cfg.stateful("mcp", id, config, func(any) (any, error) {
+		return createMCP(config, context)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value.(mcp.Provider), nil
+}
+
+func (cfg *Config) mcpContext(config mcpConfig) (mcpContext, error) {
+	context := mcpContext{
+		Tools:   make(map[string]tool.Provider),
+		Indexes: make(map[string]index.Provider),
+	}
+
+	for _, t := range config.Tools {
+		tool, err := cfg.Tool(t)
+
+		if err != nil {
+			return context, err
+		}
+
+		context.Tools[t] = tool
+	}
+
+	for _, i := range config.Indexes {
+		index, err := cfg.Index(i)
+
+		if err != nil {
+			return context, err
+		}
+
+		context.Indexes[i] = index
+	}
+
+	if config.Extractor != "" {
+		extractor, err := cfg.Extractor(config.Extractor)
+
+		if err != nil {
+			return context, err
+		}
+
+		context.Extractor = extractor
+	}
+
+	return context, nil
+}
+
+func isGatewayMCP(cfg mcpConfig) bool {
+	return strings.ToLower(cfg.Type) == "gateway"
+}
+
 func createMCP(cfg mcpConfig, context mcpContext) (mcp.Provider, error) {
 	switch strings.ToLower(cfg.Type) {
 	case "server":
 		return serverMCP(cfg, context)
 	case "proxy":
 		return proxyMCP(cfg, context)
+	case "stdio", "command":
+		return stdioMCP(cfg, context)
+	case "gateway":
+		return gatewayMCP(cfg, context)
 	default:
 		return nil, errors.New("invalid mcp type: " + cfg.Type)
 	}
@@ -119,10 +277,70 @@ func createMCP(cfg mcpConfig, context mcpContext) (mcp.Provider, error) {
 func serverMCP(cfg mcpConfig, context mcpContext) (mcp.Provider, error) {
 	tools := slices.Collect(maps.Values(context.Tools))
 
-	return server.New(cfg.Name, cfg.Instructions, tools)
+	var options []server.Option
+
+	for id, index := range context.Indexes {
+		options = append(options, server.WithIndex(id, index))
+	}
+
+	if context.Extractor != nil {
+		options = append(options, server.WithExtractor(context.Extractor))
+	}
+
+	for _, r := range cfg.Resources {
+		u, err := url.Parse(r.URI)
+
+		if err != nil || u.Scheme == "" {
+			return nil, errors.New("invalid mcp resource uri: " + r.URI)
+		}
+
+		options = append(options, server.WithResources(server.Resource{
+			URI: r.URI,
+
+			Name:        r.Name,
+			Description: r.Description,
+
+			MIMEType: r.MIMEType,
+		}))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Prompts)) {
+		p := cfg.Prompts[name]
+
+		tmpl, err := template.NewTemplate(p.Template)
+
+		if err != nil {
+			return nil, err
+		}
+
+		prompt := server.Prompt{
+			Name:        name,
+			Title:       p.Title,
+			Description: p.Description,
+
+			Template: tmpl,
+		}
+
+		for _, a := range p.Arguments {
+			prompt.Arguments = append(prompt.Arguments, server.PromptArgument{
+				Name:        a.Name,
+				Description: a.Description,
+
+				Required: a.Required,
+			})
+		}
+
+		options = append(options, server.WithPrompts(prompt))
+	}
+
+	return server.New(cfg.Name, cfg.Instructions, tools, options...)
 }
 
 func proxyMCP(cfg mcpConfig, context mcpContext) (mcp.Provider, error) {
+	if cfg.Command != "" {
+		return stdioMCP(cfg, context)
+	}
+
 	exchanger, err := createClientAuth(cfg.Auth)
 
 	if err != nil {
@@ -131,3 +349,46 @@ func proxyMCP(cfg mcpConfig, context mcpContext) (mcp.Provider, error) {
 
 	return proxy.New(cfg.URL, cfg.Vars, exchanger)
 }
+
+// stdioMCP serves the tools of a local stdio MCP server over streamable HTTP.
+func stdioMCP(cfg mcpConfig, context mcpContext) (mcp.Provider, error) {
+	client, err := createMCPClient("", "", cfg.Command, cfg.Args, cfg.Env, cfg.Concurrency, nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	name := cfg.Name
+
+	if name == "" {
+		name = path.Base(cfg.Command)
+	}
+
+	return server.New(name, cfg.Instructions, []tool.Provider{client})
+}
+
+// gatewayMCP serves the tools of several MCPs as one, checking each call
+// against the policy.
+func gatewayMCP(cfg mcpConfig, context mcpContext) (mcp.Provider, error) {
+	return gateway.New(cfg.Name, cfg.Instructions, context.Members, context.Policy)
+}
+
+// mcpTools connects to an MCP as a client, so a gateway can call its tools.
+func mcpTools(cfg mcpConfig, context mcpContext) ([]tool.Provider, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "server":
+		return slices.Collect(maps.Values(context.Tools)), nil
+
+	case "proxy", "stdio", "command":
+		client, err := createMCPClient(cfg.URL, "", cfg.Command, cfg.Args, cfg.Env, cfg.Concurrency, cfg.Vars, cfg.Auth)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []tool.Provider{client}, nil
+
+	default:
+		return nil, errors.New("invalid mcp type: " + cfg.Type)
+	}
+}
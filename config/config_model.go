@@ -23,6 +23,15 @@ func (cfg *Config) RegisterModel(id string) {
 	}
 }
 
+// DescribeModel sets the name, description and capabilities of a model.
+func (cfg *Config) DescribeModel(model provider.Model) {
+	if cfg.models == nil {
+		cfg.models = make(map[string]provider.Model)
+	}
+
+	cfg.models[model.ID] = model
+}
+
 func (cfg *Config) Models() []provider.Model {
 	var result []provider.Model
 
@@ -66,6 +75,14 @@ type modelConfig struct {
 	Description string `yaml:"description"`
 
 	MaxRetries *int `yaml:"max_retries"`
+
+	Pricing      *pricingConfig      `yaml:"pricing"`
+	Capabilities *capabilitiesConfig `yaml:"capabilities"`
+
+	Cache      *cacheConfig     `yaml:"cache"`
+	Guardrails *guardrailConfig `yaml:"guardrails"`
+
+	HostedTools *hostedToolsConfig `yaml:"hosted_tools"`
 }
 
 type modelContext struct {
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/adrianliechti/wingman/pkg/pipeline"
+)
+
+func (cfg *Config) RegisterPipeline(id string, p *pipeline.Pipeline) {
+	if cfg.pipelines == nil {
+		cfg.pipelines = make(map[string]*pipeline.Pipeline)
+	}
+
+	cfg.pipelines[id] = p
+}
+
+func (cfg *Config) Pipeline(id string) (*pipeline.Pipeline, error) {
+	if cfg.pipelines != nil {
+		if p, ok := cfg.pipelines[id]; ok {
+			return p, nil
+		}
+	}
+
+	return nil, errors.New("pipeline not found: " + id)
+}
+
+func (cfg *Config) Pipelines() []string {
+	var result []string
+
+	for id := range cfg.pipelines {
+		result = append(result, id)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// PipelineIndex returns the id of the index the pipeline ingests into.
+func (cfg *Config) PipelineIndex(id string) string {
+	return cfg.pipelineIndex[id]
+}
+
+type pipelineConfig struct {
+	// Index is the id of the index the chunks are upserted into
+	Index string `yaml:"index"`
+
+	// Extractor defaults to trying all configured extractors in order
+	Extractor string `yaml:"extractor"`
+
+	// Segmenter defaults to the built-in text segmenter
+	Segmenter string `yaml:"segmenter"`
+
+	// Scraper, if set, fetches URLs instead of downloading them directly
+	Scraper string `yaml:"scraper"`
+
+	SegmentLength  int  `yaml:"segment_length"`
+	SegmentOverlap *int `yaml:"segment_overlap"`
+}
+
+func (cfg *Config) registerPipelines(f *configFile) error {
+	var configs map[string]pipelineConfig
+
+	if err := decodeStrict(&f.Pipelines, &configs); err != nil {
+		return err
+	}
+
+	for _, node := range f.Pipelines.Content {
+		id := node.Value
+
+		config, ok := configs[node.Value]
+
+		if !ok {
+			continue
+		}
+
+		index, err := cfg.Index(config.Index)
+
+		if err != nil {
+			return err
+		}
+
+		extractor, err := cfg.Extractor(config.Extractor)
+
+		if err != nil {
+			return err
+		}
+
+		segmenter, err := cfg.Segmenter(config.Segmenter)
+
+		if err != nil {
+			return err
+		}
+
+		var options []pipeline.Option
+
+		if config.Scraper != "" {
+			scraper, err := cfg.Scraper(config.Scraper)
+
+			if err != nil {
+				return err
+			}
+
+			options = append(options, pipeline.WithScraper(scraper))
+		}
+
+		if config.SegmentLength > 0 {
+			options = append(options, pipeline.WithSegmentLength(config.SegmentLength))
+		}
+
+		if config.SegmentOverlap != nil {
+			options = append(options, pipeline.WithSegmentOverlap(*config.SegmentOverlap))
+		}
+
+		p, err := pipeline.New(index, extractor, segmenter, options...)
+
+		if err != nil {
+			return err
+		}
+
+		cfg.RegisterPipeline(id, p)
+
+		if cfg.pipelineIndex == nil {
+			cfg.pipelineIndex = make(map[string]string)
+		}
+
+		cfg.pipelineIndex[id] = config.Index
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"github.com/adrianliechti/wingman/pkg/otel"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+// pricingConfig sets a model's rates per million tokens, used to report the
+// cost of every request.
+type pricingConfig struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+
+	// CacheRead and CacheWrite price cached prompt tokens. Default to the
+	// input rate
+	CacheRead  *float64 `yaml:"cache_read"`
+	CacheWrite *float64 `yaml:"cache_write"`
+
+	// Reasoning prices reasoning tokens. Defaults to the output rate
+	Reasoning *float64 `yaml:"reasoning"`
+}
+
+func (c *pricingConfig) otelOptions() []otel.Option {
+	if c == nil {
+		return nil
+	}
+
+	pricing := &provider.Pricing{
+		Input:  c.Input,
+		Output: c.Output,
+
+		CacheRead:  c.Input,
+		CacheWrite: c.Input,
+
+		Reasoning: c.Output,
+	}
+
+	if c.CacheRead != nil {
+		pricing.CacheRead = *c.CacheRead
+	}
+
+	if c.CacheWrite != nil {
+		pricing.CacheWrite = *c.CacheWrite
+	}
+
+	if c.Reasoning != nil {
+		pricing.Reasoning = *c.Reasoning
+	}
+
+	return []otel.Option{otel.WithPricing(pricing)}
+}
@@ -97,12 +97,33 @@ func (cfg *Config) registerProviders(f *configFile) error {
 				}
 
 				if _, ok := completer.(otel.Completer); !ok {
-					completer = otel.NewCompleter(p.Type, id, completer)
+					completer = otel.NewCompleter(p.Type, id, completer, m.Pricing.otelOptions()...)
 				}
 
-				cfg.RegisterCompleter(id, completer)
 				cfg.RegisterReranker(id, reranker.FromCompleter(id, completer))
 
+				completer, err = cfg.cacheCompleter(id, m.Cache, completer)
+
+				if err != nil {
+					return err
+				}
+
+				completer, err = cfg.guardrailCompleter(m.Guardrails, completer)
+
+				if err != nil {
+					return err
+				}
+
+				cfg.RegisterCompleter(id, completer)
+
+				if m.HostedTools != nil {
+					if cfg.hostedToolConfigs == nil {
+						cfg.hostedToolConfigs = make(map[string]hostedToolsConfig)
+					}
+
+					cfg.hostedToolConfigs[id] = *m.HostedTools
+				}
+
 			case ModelTypeEmbedder:
 				embedder, err := createEmbedder(p, context)
 
@@ -111,7 +132,7 @@ func (cfg *Config) registerProviders(f *configFile) error {
 				}
 
 				if _, ok := embedder.(otel.Embedder); !ok {
-					embedder = otel.NewEmbedder(p.Type, id, embedder)
+					embedder = otel.NewEmbedder(p.Type, id, embedder, m.Pricing.otelOptions()...)
 				}
 
 				cfg.RegisterEmbedder(id, embedder)
@@ -176,6 +197,21 @@ func (cfg *Config) registerProviders(f *configFile) error {
 			default:
 				return errors.New("invalid model type: " + id)
 			}
+
+			capabilities, err := modelCapabilities(p.Type, m)
+
+			if err != nil {
+				return err
+			}
+
+			cfg.DescribeModel(provider.Model{
+				ID: id,
+
+				Name:        m.Name,
+				Description: m.Description,
+
+				Capabilities: capabilities,
+			})
 		}
 	}
 
@@ -183,7 +219,7 @@ func (cfg *Config) registerProviders(f *configFile) error {
 		cfg.reranker[""] = firstReranker
 	}
 
-	return nil
+	return cfg.resolveEmbedderRefs()
 }
 
 type providerConfig struct {
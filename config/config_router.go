@@ -1,8 +1,10 @@
 package config
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,6 +17,37 @@ import (
 	"github.com/adrianliechti/wingman/pkg/router/roundrobin"
 )
 
+func (cfg *Config) RegisterRouter(id string, r *router.Completer) {
+	if cfg.routers == nil {
+		cfg.routers = make(map[string]*router.Completer)
+	}
+
+	cfg.routers[id] = r
+}
+
+// Routers returns the ids of the load-balancing routers, sorted.
+func (cfg *Config) Routers() []string {
+	var ids []string
+
+	for id := range cfg.routers {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+func (cfg *Config) Router(id string) (*router.Completer, error) {
+	if cfg.routers != nil {
+		if r, ok := cfg.routers[id]; ok {
+			return r, nil
+		}
+	}
+
+	return nil, errors.New("router not found: " + id)
+}
+
 type routerConfig struct {
 	Type string `yaml:"type"`
 
@@ -59,24 +92,29 @@ type routerConfig struct {
 	// "model" elsewhere). The classifier uses it as the optional LLM-as-judge
 	// tier; omit to keep it off (the default).
 	Completer string `yaml:"completer"`
+
+	// Guardrails applies guards to the traffic routed through this router.
+	Guardrails *guardrailConfig `yaml:"guardrails"`
 }
 
 // routerCandidateConfig describes one classifier candidate. Model is a completer
 // model id (resolved via cfg.Completer, like "model" elsewhere). Cost is only a
-// tie-breaker among candidates that already clear the difficulty bar.
+// tie-breaker among candidates that already clear the difficulty bar. Vision
+// and MaxContext default to the capabilities of the model.
 type routerCandidateConfig struct {
 	Model string `yaml:"model"`
 	Card  string `yaml:"card"`
 
 	Cost          float64 `yaml:"cost"`
 	MaxDifficulty int     `yaml:"max_difficulty"`
-	Vision        bool    `yaml:"vision"`
+	Vision        *bool   `yaml:"vision"`
 	MaxContext    int     `yaml:"max_context"`
 
 	Examples []string `yaml:"examples"`
 }
 
 type routerContext struct {
+	Names      []string
 	Completers []provider.Completer
 	Fallback   provider.Completer
 }
@@ -110,6 +148,7 @@ func (cfg *Config) registerRouters(f *configFile) error {
 				return err
 			}
 
+			context.Names = append(context.Names, m)
 			context.Completers = append(context.Completers, completer)
 		}
 
@@ -129,10 +168,20 @@ func (cfg *Config) registerRouters(f *configFile) error {
 			return err
 		}
 
+		if r, ok := completer.(*router.Completer); ok {
+			cfg.RegisterRouter(id, r)
+		}
+
 		if config.ReasoningSignatures != nil && !*config.ReasoningSignatures {
 			completer = signatures.FromCompleter(completer)
 		}
 
+		completer, err = cfg.guardrailCompleter(config.Guardrails, completer)
+
+		if err != nil {
+			return err
+		}
+
 		cfg.RegisterCompleter(id, otel.NewCompleterSpan("router "+id, completer))
 	}
 
@@ -158,6 +207,12 @@ func (cfg *Config) registerRouters(f *configFile) error {
 			completer = signatures.FromCompleter(completer)
 		}
 
+		completer, err = cfg.guardrailCompleter(config.Guardrails, completer)
+
+		if err != nil {
+			return err
+		}
+
 		cfg.RegisterCompleter(id, otel.NewCompleterSpan("router "+id, completer))
 	}
 
@@ -185,6 +240,18 @@ func (cfg *Config) createClassifier(config routerConfig) (provider.Completer, er
 			return nil, err
 		}
 
+		var capabilities provider.ModelCapabilities
+
+		if m, err := cfg.Model(cc.Model); err == nil {
+			capabilities = m.Capabilities
+		}
+
+		vision := slices.Contains(capabilities.InputModalities, provider.ModalityImage)
+
+		if cc.Vision != nil {
+			vision = *cc.Vision
+		}
+
 		candidates = append(candidates, classifier.Candidate{
 			Completer: completer,
 
@@ -193,8 +260,8 @@ func (cfg *Config) createClassifier(config routerConfig) (provider.Completer, er
 
 			Cost:          cc.Cost,
 			MaxDifficulty: cc.MaxDifficulty,
-			Vision:        cc.Vision,
-			MaxContext:    cc.MaxContext,
+			Vision:        vision,
+			MaxContext:    cmp.Or(cc.MaxContext, capabilities.ContextWindow),
 
 			Examples: cc.Examples,
 		})
@@ -257,7 +324,9 @@ func createRouter(cfg routerConfig, context routerContext) (provider.Completer,
 }
 
 func routerOptions(cfg routerConfig, context routerContext) ([]router.Option, error) {
-	var options []router.Option
+	options := []router.Option{
+		router.WithNames(context.Names...),
+	}
 
 	if context.Fallback != nil {
 		options = append(options, router.WithFallback(context.Fallback))
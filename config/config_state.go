@@ -0,0 +1,101 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// State keeps the stateful subsystems of a configuration across reloads: the
+// store, the limiter, memory indexes and response caches. A Config parsed
+// with the State of its predecessor takes over every subsystem whose
+// configuration did not change, so a reload keeps stored responses, files
+// and batches, rate limit counters, indexed documents and cached answers.
+// Subsystems whose configuration changed start empty. Subsystems rebuilt on
+// every load, like MCP servers, are closed once the next config is retained.
+type State struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+func NewState() *State {
+	return &State{
+		values: make(map[string]any),
+	}
+}
+
+// Retain makes the subsystems of cfg the ones handed to the next config.
+// Subsystems cfg no longer uses are closed if they hold resources.
+func (s *State) Retain(cfg *Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string]any, len(cfg.stateValues))
+
+	for key, value := range cfg.stateValues {
+		values[key] = value
+	}
+
+	for key, value := range s.values {
+		if values[key] != value {
+			closeState(value)
+		}
+	}
+
+	s.values = values
+}
+
+// discard closes the subsystems a config created that are not kept by the
+// state, e.g. after the config failed to load.
+func (s *State) discard(cfg *Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, value := range cfg.stateValues {
+		if s.values[key] != value {
+			closeState(value)
+		}
+	}
+}
+
+// stateful returns the subsystem identified by kind, id and its config.
+// create receives the subsystem the previous config used, if any, and
+// returns the one to use from now on: usually the previous one itself, or a
+// new one seeded from it.
+func (cfg *Config) stateful(kind, id string, config any, create func(previous any) (any, error)) (any, error) {
+	data, err := json.Marshal(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key := kind + "/" + id + "/" + string(data)
+
+	var previous any
+
+	if cfg.state != nil {
+		cfg.state.mu.Lock()
+		previous = cfg.state.values[key]
+		cfg.state.mu.Unlock()
+	}
+
+	value, err := create(previous)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.stateValues == nil {
+		cfg.stateValues = make(map[string]any)
+	}
+
+	cfg.stateValues[key] = value
+
+	return value, nil
+}
+
+func closeState(value any) {
+	if c, ok := value.(io.Closer); ok {
+		c.Close()
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+)
+
+func TestStateCarriesMemoryIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	data := `
+providers:
+  - type: openai
+    token: test
+    models:
+      text-embedding-3-small:
+        type: embedder
+
+indexes:
+  docs:
+    type: memory
+    embedder: text-embedding-3-small
+`
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state := NewState()
+
+	first, err := ParseState(path, state)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state.Retain(first)
+
+	i, _ := first.Index("docs")
+
+	// documents with an embedding are stored without calling the embedder
+	if _, err := i.Index(context.Background(), index.Document{ID: "a", Content: "hello", Embedding: []float32{1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := ParseState(path, state)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state.Retain(second)
+
+	i, _ = second.Index("docs")
+
+	documents, err := i.List(context.Background(), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(documents) != 1 || documents[0].ID != "a" {
+		t.Fatalf("expected the indexed document to survive the reload, got %+v", documents)
+	}
+}
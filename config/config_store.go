@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/store"
+	"github.com/adrianliechti/wingman/pkg/store/file"
+	"github.com/adrianliechti/wingman/pkg/store/memory"
+)
+
+type storeConfig struct {
+	Type string `yaml:"type"`
+
+	// Path is the directory used by the "file" store.
+	Path string `yaml:"path"`
+
+	// Limit caps the number of responses kept by the "memory" store.
+	// Defaults to 1000
+	Limit *int `yaml:"limit"`
+}
+
+func (cfg *Config) registerStore(f *configFile) error {
+	if f.Store == nil {
+		return nil
+	}
+
+	// the store is handed over on reload, so stored state survives it
+	provider, err := cfg.stateful("store", "", *f.Store, func(previous any) (any, error) {
+		if previous != nil {
+			return previous, nil
+		}
+
+		return createStore(*f.Store)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	cfg.Store = provider.(store.Provider)
+
+	return nil
+}
+
+func createStore(cfg storeConfig) (store.Provider, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "memory":
+		return memoryStore(cfg)
+
+	case "file":
+		return fileStore(cfg)
+
+	default:
+		return nil, errors.New("invalid store type: " + cfg.Type)
+	}
+}
+
+func memoryStore(cfg storeConfig) (store.Provider, error) {
+	var options []memory.Option
+
+	if cfg.Limit != nil {
+		options = append(options, memory.WithLimit(*cfg.Limit))
+	}
+
+	return memory.New(options...), nil
+}
+
+func fileStore(cfg storeConfig) (store.Provider, error) {
+	return file.New(cfg.Path)
+}
@@ -6,13 +6,20 @@ import (
 
 	"github.com/adrianliechti/wingman/pkg/tool"
 	"github.com/adrianliechti/wingman/pkg/tool/custom"
+	"github.com/adrianliechti/wingman/pkg/tool/extract"
+	"github.com/adrianliechti/wingman/pkg/tool/interpreter"
 	"github.com/adrianliechti/wingman/pkg/tool/mcp"
+	"github.com/adrianliechti/wingman/pkg/tool/render"
 	"github.com/adrianliechti/wingman/pkg/tool/research"
+	"github.com/adrianliechti/wingman/pkg/tool/retrieve"
 	"github.com/adrianliechti/wingman/pkg/tool/scrape"
 	"github.com/adrianliechti/wingman/pkg/tool/search"
+	"github.com/adrianliechti/wingman/pkg/tool/speak"
 	"github.com/adrianliechti/wingman/pkg/tool/translate"
 
 	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/mcp/stdio"
 	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/researcher"
 	"github.com/adrianliechti/wingman/pkg/scraper"
@@ -57,21 +64,59 @@ type toolConfig struct {
 
 	URL string `yaml:"url"`
 
+	// Transport forces the MCP transport of a url: "sse" or "streamable"
+	Transport string `yaml:"transport"`
+
+	// Command launches a local stdio MCP server instead of connecting to url
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+
+	// Concurrency limits the calls in flight to a stdio MCP server. Defaults to 4
+	Concurrency int `yaml:"concurrency"`
+
 	Vars  map[string]string `yaml:"vars"`
 	Auth  *authConfig       `yaml:"auth"`
 	Proxy *proxyConfig      `yaml:"proxy"`
 
 	Model string `yaml:"model"`
 
+	// Description overrides the tool description shown to the model
+	Description string `yaml:"description"`
+
+	Index    string `yaml:"index"`
+	Reranker string `yaml:"reranker"`
+
+	// Limit is the default number of results of search and retrieve tools
+	Limit int `yaml:"limit"`
+
 	Extractor  string `yaml:"extractor"`
 	Translator string `yaml:"translator"`
 
 	Scraper    string `yaml:"scraper"`
 	Searcher   string `yaml:"searcher"`
 	Researcher string `yaml:"researcher"`
+
+	// Voice is the speak tool's default voice
+	Voice string `yaml:"voice"`
+
+	// Python is the interpreter tool's Python executable. Defaults to python3
+	Python string `yaml:"python"`
+
+	// Timeout bounds an interpreter run (e.g. "30s"). Defaults to 60s
+	Timeout string `yaml:"timeout"`
+
+	// Memory caps an interpreter run's memory in MiB. Defaults to 512
+	Memory int `yaml:"memory"`
+
+	// Network lets interpreter runs access the network
+	Network bool `yaml:"network"`
 }
 
 type toolContext struct {
+	Index    index.Provider
+	Reranker provider.Reranker
+
 	Extractor  extractor.Provider
 	Translator translator.Provider
 
@@ -101,6 +146,14 @@ func (cfg *Config) registerTools(f *configFile) error {
 
 		context := toolContext{}
 
+		if p, err := cfg.Index(config.Index); err == nil {
+			context.Index = p
+		}
+
+		if p, err := cfg.Reranker(config.Reranker); err == nil {
+			context.Reranker = p
+		}
+
 		if p, err := cfg.Extractor(config.Extractor); err == nil {
 			context.Extractor = p
 		}
@@ -157,12 +210,27 @@ func createTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
 	case "research":
 		return researcherTool(cfg, context)
 
+	case "retrieve", "index":
+		return retrieveTool(cfg, context)
+
 	case "translator":
 		return translatorTool(cfg, context)
 
+	case "extract", "extractor":
+		return extractTool(cfg, context)
+
+	case "render", "renderer":
+		return renderTool(cfg, context)
+
+	case "speak", "synthesizer":
+		return speakTool(cfg, context)
+
 	case "mcp":
 		return mcpTool(cfg, context)
 
+	case "interpreter":
+		return interpreterTool(cfg, context)
+
 	case "custom":
 		return customTool(cfg, context)
 
@@ -180,6 +248,10 @@ func scraperTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
 func searcherTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
 	var options []search.Option
 
+	if cfg.Limit > 0 {
+		options = append(options, search.WithLimit(cfg.Limit))
+	}
+
 	return search.New(context.Searcher, options...)
 }
 
@@ -189,20 +261,120 @@ func researcherTool(cfg toolConfig, context toolContext) (tool.Provider, error)
 	return research.New(context.Researcher, options...)
 }
 
+func retrieveTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
+	var options []retrieve.Option
+
+	if context.Reranker != nil {
+		options = append(options, retrieve.WithReranker(context.Reranker))
+	}
+
+	if cfg.Limit > 0 {
+		options = append(options, retrieve.WithLimit(cfg.Limit))
+	}
+
+	if cfg.Description != "" {
+		options = append(options, retrieve.WithDescription(cfg.Description))
+	}
+
+	return retrieve.New(context.Index, options...)
+}
+
 func translatorTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
 	var options []translate.Option
 
 	return translate.New(context.Translator, options...)
 }
 
+func extractTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
+	var options []extract.Option
+
+	if context.Scraper != nil {
+		options = append(options, extract.WithScraper(context.Scraper))
+	}
+
+	return extract.New(context.Extractor, options...)
+}
+
+func renderTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
+	var options []render.Option
+
+	return render.New(context.Renderer, options...)
+}
+
+func speakTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
+	var options []speak.Option
+
+	if cfg.Voice != "" {
+		options = append(options, speak.WithVoice(cfg.Voice))
+	}
+
+	return speak.New(context.Synthesizer, options...)
+}
+
 func mcpTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
-	exchanger, err := createClientAuth(cfg.Auth)
+	return createMCPClient(cfg.URL, cfg.Transport, cfg.Command, cfg.Args, cfg.Env, cfg.Concurrency, cfg.Vars, cfg.Auth)
+}
+
+// createMCPClient connects to a remote MCP server by url or launches a
+// local one by command.
+func createMCPClient(url, transport, command string, args []string, env map[string]string, concurrency int, vars map[string]string, auth *authConfig) (*mcp.Client, error) {
+	if command != "" {
+		process := stdio.Shared(stdio.Command{
+			Command: command,
+			Args:    args,
+			Env:     env,
+		}, concurrency)
+
+		return mcp.NewCommand(process)
+	}
+
+	var options []mcp.Option
+
+	switch strings.ToLower(transport) {
+	case "", "streamable", "http":
+	case "sse":
+		options = append(options, mcp.WithSSE())
+	default:
+		return nil, errors.New("invalid mcp transport: " + transport)
+	}
+
+	exchanger, err := createClientAuth(auth)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return mcp.New(cfg.URL, cfg.Vars, exchanger)
+	return mcp.New(url, vars, exchanger, options...)
+}
+
+func interpreterTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
+	var options []interpreter.Option
+
+	if cfg.Python != "" {
+		options = append(options, interpreter.WithPython(cfg.Python))
+	}
+
+	if cfg.Timeout != "" {
+		timeout, err := parseTimeout("timeout", cfg.Timeout)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if timeout > 0 {
+			options = append(options, interpreter.WithTimeout(timeout))
+		}
+	}
+
+	if cfg.Memory > 0 {
+		options = append(options, interpreter.WithMemory(int64(cfg.Memory)<<20))
+	}
+
+	if cfg.Network {
+		options = append(options, interpreter.WithNetwork(true))
+	}
+
+	return interpreter.New(options...)
 }
 
 func customTool(cfg toolConfig, context toolContext) (tool.Provider, error) {
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/adrianliechti/wingman/config"
 	"github.com/adrianliechti/wingman/server"
 
 	"github.com/adrianliechti/wingman/pkg/otel"
@@ -15,17 +20,17 @@ func main() {
 	addressFlag := flag.String("address", "", "server address")
 	configFlag := flag.String("config", "config.yaml", "configuration path")
 
-	flag.Parse()
+	shutdownDelayFlag := flag.Duration("shutdown-delay", 0, "wait before refusing new connections on shutdown")
+	shutdownTimeoutFlag := flag.Duration("shutdown-timeout", 5*time.Minute, "maximum time to drain in-flight requests on shutdown")
 
-	cfg, err := config.Parse(*configFlag)
+	metricsPathFlag := flag.String("metrics-path", "", "serve Prometheus metrics on this path (e.g. /metrics)")
+	metricsPortFlag := flag.Int("metrics-port", 0, "serve Prometheus metrics on a separate port")
 
-	if err != nil {
-		panic(err)
-	}
+	flag.Parse()
 
-	cfg.Address = fmt.Sprintf("%s:%d", *addressFlag, *portFlag)
+	otel.EnablePrometheus = *metricsPathFlag != "" || *metricsPortFlag != 0
 
-	s, err := server.New(cfg)
+	s, err := server.NewReloader(*configFlag, fmt.Sprintf("%s:%d", *addressFlag, *portFlag))
 
 	if err != nil {
 		panic(err)
@@ -35,7 +40,40 @@ func main() {
 		panic(err)
 	}
 
-	if err := s.ListenAndServe(); err != nil {
+	if otel.EnablePrometheus {
+		path := *metricsPathFlag
+
+		if path == "" {
+			path = "/metrics"
+		}
+
+		if *metricsPortFlag != 0 {
+			mux := http.NewServeMux()
+			mux.Handle(path, otel.MetricsHandler())
+
+			go func() {
+				if err := http.ListenAndServe(fmt.Sprintf("%s:%d", *addressFlag, *metricsPortFlag), mux); err != nil {
+					panic(err)
+				}
+			}()
+		} else {
+			s.HandleMetrics(path, otel.MetricsHandler())
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go s.Watch(ctx, 5*time.Second)
+
+	if err := s.ListenAndServe(ctx, *shutdownDelayFlag, *shutdownTimeoutFlag); err != nil {
 		panic(err)
 	}
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := otel.Shutdown(flushCtx); err != nil {
+		fmt.Fprintln(os.Stderr, "telemetry shutdown:", err)
+	}
 }
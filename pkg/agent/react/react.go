@@ -181,11 +181,21 @@ func (c *Agent) Complete(ctx context.Context, messages []provider.Message, optio
 			Tools:       slices.Collect(maps.Values(inputTools)),
 			ToolOptions: inputToolOptions,
 
-			OutputOptions:    opts.OutputOptions,
-			ReasoningOptions: opts.ReasoningOptions,
+			OutputOptions:     opts.OutputOptions,
+			ReasoningOptions:  opts.ReasoningOptions,
+			CompactionOptions: opts.CompactionOptions,
 
 			MaxTokens:   opts.MaxTokens,
 			Temperature: opts.Temperature,
+			TopP:        opts.TopP,
+
+			Seed: opts.Seed,
+
+			PresencePenalty:  opts.PresencePenalty,
+			FrequencyPenalty: opts.FrequencyPenalty,
+
+			LogitBias:       opts.LogitBias,
+			LogprobsOptions: opts.LogprobsOptions,
 
 			Schema: opts.Schema,
 		}
@@ -210,7 +220,8 @@ func (c *Agent) Complete(ctx context.Context, messages []provider.Message, optio
 					ID:    accID,
 					Model: c.model,
 
-					Usage: completion.Usage,
+					Status: completion.Status,
+					Usage:  completion.Usage,
 				}
 
 				if completion.Message != nil {
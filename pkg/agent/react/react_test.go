@@ -337,6 +337,39 @@ func TestComplete_WithOptions(t *testing.T) {
 		require.NotNil(t, opts.MaxTokens)
 		require.Equal(t, 100, *opts.MaxTokens)
 	})
+
+	t.Run("sampling and logprobs options propagate", func(t *testing.T) {
+		completer := &mockCompleter{
+			responses: [][]provider.Completion{
+				{{Message: &provider.Message{Role: provider.MessageRoleAssistant}}},
+			},
+		}
+
+		chain, err := New("test-model", WithCompleter(completer))
+		require.NoError(t, err)
+
+		callOpts := &provider.CompleteOptions{
+			TopP: new(float32(0.9)),
+			Seed: new(7),
+
+			PresencePenalty:  new(float32(0.5)),
+			FrequencyPenalty: new(float32(0.25)),
+
+			LogitBias:       map[string]int{"50256": -100},
+			LogprobsOptions: &provider.LogprobsOptions{TopLogprobs: 2},
+		}
+
+		_, err = collectCompletions(chain.Complete(context.Background(), nil, callOpts))
+		require.NoError(t, err)
+
+		opts := completer.capturedOptions[0]
+		require.Equal(t, callOpts.TopP, opts.TopP)
+		require.Equal(t, callOpts.Seed, opts.Seed)
+		require.Equal(t, callOpts.PresencePenalty, opts.PresencePenalty)
+		require.Equal(t, callOpts.FrequencyPenalty, opts.FrequencyPenalty)
+		require.Equal(t, callOpts.LogitBias, opts.LogitBias)
+		require.Equal(t, callOpts.LogprobsOptions, opts.LogprobsOptions)
+	})
 }
 
 // =============================================================================
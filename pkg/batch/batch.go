@@ -0,0 +1,140 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultConcurrency bounds the requests of one batch in flight at a time.
+const DefaultConcurrency = 8
+
+// Request is one entry of a batch: an API call replayed against the
+// gateway's own handlers, so it passes the same routing, policy and
+// telemetry as live traffic.
+type Request struct {
+	ID string
+
+	Method string
+	Path   string
+
+	Body json.RawMessage
+}
+
+type Response struct {
+	StatusCode int
+
+	Body []byte
+}
+
+// Run executes requests against handler with at most concurrency of them in
+// flight and reports each result through done, which may be called
+// concurrently. The request context (carrying the caller's identity) is
+// passed on to every request. Requests not yet started when ctx is done are
+// reported with ctx's error.
+func Run(ctx context.Context, handler http.Handler, requests []Request, concurrency int, done func(index int, response *Response, err error)) {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+
+	for i, r := range requests {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				done(i, nil, err)
+				return nil
+			}
+
+			response, err := Do(ctx, handler, r)
+			done(i, response, err)
+
+			return nil
+		})
+	}
+
+	g.Wait()
+}
+
+// WithoutStream removes the streaming options from a request body, forcing
+// a buffered response: batch results are collected as a whole.
+func WithoutStream(body json.RawMessage) json.RawMessage {
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+
+	if _, ok := fields["stream"]; !ok {
+		return body
+	}
+
+	delete(fields, "stream")
+	delete(fields, "stream_options")
+
+	data, err := json.Marshal(fields)
+
+	if err != nil {
+		return body
+	}
+
+	return data
+}
+
+// Do executes a single request against handler.
+func Do(ctx context.Context, handler http.Handler, r Request) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.Path, bytes.NewReader(r.Body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	w := &responseRecorder{
+		header: make(http.Header),
+	}
+
+	handler.ServeHTTP(w, req)
+
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+
+	return &Response{
+		StatusCode: w.code,
+		Body:       w.body.Bytes(),
+	}, nil
+}
+
+type responseRecorder struct {
+	header http.Header
+
+	code int
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Header() http.Header {
+	return w.header
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+
+	return w.body.Write(data)
+}
+
+func (w *responseRecorder) Flush() {
+}
@@ -0,0 +1,191 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/index/memory"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ index.Provider = (*Index)(nil)
+var _ index.DocumentLister = (*Index)(nil)
+
+// Index is an in-memory index that snapshots its documents, embeddings
+// included, to a single JSON file after every change. It is meant for small
+// corpora that fit comfortably in memory.
+type Index struct {
+	mu sync.Mutex
+
+	path  string
+	index *memory.Index
+}
+
+type document struct {
+	ID string `json:"id"`
+
+	Title   string `json:"title,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Content string `json:"content"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	Embedding []float32 `json:"embedding"`
+}
+
+func New(path string, embedder provider.Embedder, options ...memory.Option) (*Index, error) {
+	if path == "" {
+		return nil, errors.New("invalid index path")
+	}
+
+	m, err := memory.New(embedder, options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Index{
+		path:  path,
+		index: m,
+	}
+
+	if err := i.load(); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+func (i *Index) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
+	return i.index.List(ctx, options)
+}
+
+func (i *Index) ListDocuments(ctx context.Context) ([]index.Document, error) {
+	return i.index.ListDocuments(ctx)
+}
+
+func (i *Index) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	return i.index.Query(ctx, query, options)
+}
+
+func (i *Index) Index(ctx context.Context, documents ...index.Document) ([]string, error) {
+	ids, err := i.index.Index(ctx, documents...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.save(ctx); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (i *Index) Delete(ctx context.Context, ids ...string) error {
+	if err := i.index.Delete(ctx, ids...); err != nil {
+		return err
+	}
+
+	return i.save(ctx)
+}
+
+func (i *Index) load() error {
+	data, err := os.ReadFile(i.path)
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	var records []document
+
+	if err := json.Unmarshal(data, &records); err != nil {
+		return err
+	}
+
+	documents := make([]index.Document, 0, len(records))
+
+	for _, r := range records {
+		documents = append(documents, index.Document{
+			ID: r.ID,
+
+			Title:   r.Title,
+			Source:  r.Source,
+			Content: r.Content,
+
+			Metadata: r.Metadata,
+
+			Embedding: r.Embedding,
+		})
+	}
+
+	_, err = i.index.Index(context.Background(), documents...)
+	return err
+}
+
+func (i *Index) save(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	documents, err := i.index.List(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	records := make([]document, 0, len(documents))
+
+	for _, d := range documents {
+		records = append(records, document{
+			ID: d.ID,
+
+			Title:   d.Title,
+			Source:  d.Source,
+			Content: d.Content,
+
+			Metadata: d.Metadata,
+
+			Embedding: d.Embedding,
+		})
+	}
+
+	data, err := json.Marshal(records)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(i.path), 0755); err != nil {
+		return err
+	}
+
+	// write to a temp file first so a crash never leaves a truncated snapshot
+	temp, err := os.CreateTemp(filepath.Dir(i.path), ".tmp-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), i.path)
+}
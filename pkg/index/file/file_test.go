@@ -0,0 +1,64 @@
+package file
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type countingEmbedder struct {
+	calls int
+}
+
+func (e *countingEmbedder) Embed(_ context.Context, texts []string, _ *provider.EmbedOptions) (*provider.Embedding, error) {
+	e.calls++
+
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, []float32{1, 0})
+	}
+
+	return result, nil
+}
+
+func TestIndexSurvivesReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	ctx := context.Background()
+
+	embedder := &countingEmbedder{}
+
+	i, err := New(path, embedder)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := i.Index(ctx, index.Document{ID: "a", Content: "hello", Metadata: map[string]string{"k": "v"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := New(path, embedder)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documents, err := reloaded.List(ctx, &index.ListOptions{Filters: map[string]string{"k": "v"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(documents) != 1 || documents[0].Content != "hello" || len(documents[0].Embedding) != 2 {
+		t.Fatalf("unexpected documents after reload: %+v", documents)
+	}
+
+	// stored embeddings are reused rather than recomputed on load
+	if embedder.calls != 1 {
+		t.Fatalf("expected 1 embed call, got %d", embedder.calls)
+	}
+}
@@ -0,0 +1,115 @@
+package index
+
+import (
+	"context"
+)
+
+type Provider interface {
+	List(ctx context.Context, options *ListOptions) ([]Document, error)
+
+	// Index upserts the documents and returns their ids in order, with ids
+	// assigned to documents that had none.
+	Index(ctx context.Context, documents ...Document) ([]string, error)
+	Delete(ctx context.Context, ids ...string) error
+
+	Query(ctx context.Context, query string, options *QueryOptions) ([]Result, error)
+}
+
+// DocumentLister is implemented by indexes that keep track of the documents
+// their chunks belong to. ListDocuments returns one entry per document key,
+// see Document.Key, with the title, source and metadata of its first chunk
+// and no content, without reading every chunk.
+type DocumentLister interface {
+	ListDocuments(ctx context.Context) ([]Document, error)
+}
+
+// ListDocuments lists the documents of an index, see DocumentLister.
+// Indexes that do not keep track of their documents are listed chunk by
+// chunk.
+func ListDocuments(ctx context.Context, p Provider) ([]Document, error) {
+	if l, ok := p.(DocumentLister); ok {
+		return l.ListDocuments(ctx)
+	}
+
+	chunks, err := p.List(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+
+	var result []Document
+
+	for _, d := range chunks {
+		key := d.Key()
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		result = append(result, Document{
+			ID: key,
+
+			Title:  d.Title,
+			Source: d.Source,
+
+			Metadata: d.Metadata,
+		})
+	}
+
+	return result, nil
+}
+
+type Document struct {
+	ID string
+
+	Title   string
+	Source  string
+	Content string
+
+	Metadata map[string]string
+
+	Embedding []float32
+}
+
+type Result struct {
+	Document
+
+	Score float32
+}
+
+type ListOptions struct {
+	Filters map[string]string
+}
+
+type QueryOptions struct {
+	Limit *int
+
+	// Filters restricts results to documents whose metadata holds every
+	// listed key with exactly the given value.
+	Filters map[string]string
+}
+
+// Key returns the document a chunk belongs to: the document metadata set by
+// ingestion pipelines, or the chunk's own id.
+func (d Document) Key() string {
+	if key := d.Metadata["document"]; key != "" {
+		return key
+	}
+
+	return d.ID
+}
+
+// Matches reports whether the document metadata satisfies every filter.
+func (d Document) Matches(filters map[string]string) bool {
+	for key, value := range filters {
+		if val, ok := d.Metadata[key]; !ok || val != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,19 @@
+package memory
+
+type Option func(*Index)
+
+// WithLimit sets the default number of query results.
+func WithLimit(limit int) Option {
+	return func(i *Index) {
+		i.limit = limit
+	}
+}
+
+// WithBatchSize sets how many documents are embedded per embedder call.
+func WithBatchSize(size int) Option {
+	return func(i *Index) {
+		if size > 0 {
+			i.batchSize = size
+		}
+	}
+}
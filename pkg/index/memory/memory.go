@@ -0,0 +1,270 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/google/uuid"
+)
+
+var _ index.Provider = (*Index)(nil)
+var _ index.DocumentLister = (*Index)(nil)
+
+type Index struct {
+	mu sync.RWMutex
+
+	embedder provider.Embedder
+
+	limit     int
+	batchSize int
+
+	order     []string
+	documents map[string]index.Document
+
+	// keys counts the chunks of every document key, in order of appearance
+	keys     map[string]*documentEntry
+	keyOrder []string
+}
+
+type documentEntry struct {
+	document index.Document
+	chunks   int
+}
+
+func New(embedder provider.Embedder, options ...Option) (*Index, error) {
+	if embedder == nil {
+		return nil, errors.New("index requires an embedder")
+	}
+
+	i := &Index{
+		embedder: embedder,
+
+		limit:     10,
+		batchSize: 64,
+
+		documents: make(map[string]index.Document),
+
+		keys: make(map[string]*documentEntry),
+	}
+
+	for _, option := range options {
+		option(i)
+	}
+
+	return i, nil
+}
+
+func (i *Index) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
+	if options == nil {
+		options = new(index.ListOptions)
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	result := make([]index.Document, 0)
+
+	for _, id := range i.order {
+		d := i.documents[id]
+
+		if !d.Matches(options.Filters) {
+			continue
+		}
+
+		result = append(result, d)
+	}
+
+	return result, nil
+}
+
+func (i *Index) Index(ctx context.Context, documents ...index.Document) ([]string, error) {
+	documents = slices.Clone(documents)
+
+	var pending []int
+
+	for n := range documents {
+		if documents[n].ID == "" {
+			documents[n].ID = uuid.NewString()
+		}
+
+		if len(documents[n].Embedding) == 0 {
+			pending = append(pending, n)
+		}
+	}
+
+	// embed outside the lock; a slow embedder must not block concurrent queries
+	for batch := range slices.Chunk(pending, i.batchSize) {
+		texts := make([]string, 0, len(batch))
+
+		for _, n := range batch {
+			texts = append(texts, documents[n].Content)
+		}
+
+		embedding, err := i.embedder.Embed(ctx, texts, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(embedding.Embeddings) != len(batch) {
+			return nil, errors.New("embedder returned unexpected number of embeddings")
+		}
+
+		for k, n := range batch {
+			documents[n].Embedding = embedding.Embeddings[k]
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ids := make([]string, 0, len(documents))
+
+	for _, d := range documents {
+		ids = append(ids, d.ID)
+
+		if previous, ok := i.documents[d.ID]; ok {
+			i.removeKey(previous)
+		} else {
+			i.order = append(i.order, d.ID)
+		}
+
+		i.documents[d.ID] = d
+		i.addKey(d)
+	}
+
+	return ids, nil
+}
+
+func (i *Index) Delete(ctx context.Context, ids ...string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, id := range ids {
+		if d, ok := i.documents[id]; ok {
+			i.removeKey(d)
+			delete(i.documents, id)
+		}
+	}
+
+	i.order = slices.DeleteFunc(i.order, func(id string) bool {
+		_, ok := i.documents[id]
+		return !ok
+	})
+
+	return nil
+}
+
+func (i *Index) ListDocuments(ctx context.Context) ([]index.Document, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	result := make([]index.Document, 0, len(i.keyOrder))
+
+	for _, key := range i.keyOrder {
+		result = append(result, i.keys[key].document)
+	}
+
+	return result, nil
+}
+
+// addKey counts a chunk of its document. Must be called with mu held.
+func (i *Index) addKey(d index.Document) {
+	key := d.Key()
+
+	e, ok := i.keys[key]
+
+	if !ok {
+		e = &documentEntry{
+			document: index.Document{
+				ID: key,
+
+				Title:  d.Title,
+				Source: d.Source,
+
+				Metadata: d.Metadata,
+			},
+		}
+
+		i.keys[key] = e
+		i.keyOrder = append(i.keyOrder, key)
+	}
+
+	e.chunks++
+}
+
+// removeKey uncounts a chunk of its document. Must be called with mu held.
+func (i *Index) removeKey(d index.Document) {
+	key := d.Key()
+
+	e, ok := i.keys[key]
+
+	if !ok {
+		return
+	}
+
+	if e.chunks--; e.chunks > 0 {
+		return
+	}
+
+	delete(i.keys, key)
+	i.keyOrder = slices.DeleteFunc(i.keyOrder, func(k string) bool { return k == key })
+}
+
+func (i *Index) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	if options == nil {
+		options = new(index.QueryOptions)
+	}
+
+	embedding, err := i.embedder.Embed(ctx, []string{query}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embedding.Embeddings) == 0 {
+		return nil, errors.New("embedder returned no embedding")
+	}
+
+	vector := embedding.Embeddings[0]
+
+	limit := i.limit
+
+	if options.Limit != nil {
+		limit = *options.Limit
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	results := make([]index.Result, 0)
+
+	for _, id := range i.order {
+		d := i.documents[id]
+
+		if !d.Matches(options.Filters) {
+			continue
+		}
+
+		results = append(results, index.Result{
+			Document: d,
+			Score:    provider.CosineSimilarity(vector, d.Embedding),
+		})
+	}
+
+	slices.SortStableFunc(results, func(a, b index.Result) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+// keywordEmbedder maps texts onto a fixed vocabulary so similarity is driven
+// by shared keywords.
+type keywordEmbedder struct{}
+
+var vocabulary = []string{"cat", "dog", "fish", "bird"}
+
+func (keywordEmbedder) Embed(_ context.Context, texts []string, _ *provider.EmbedOptions) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for _, text := range texts {
+		vector := make([]float32, len(vocabulary))
+
+		for i, word := range vocabulary {
+			vector[i] = float32(strings.Count(strings.ToLower(text), word))
+		}
+
+		result.Embeddings = append(result.Embeddings, vector)
+	}
+
+	return result, nil
+}
+
+func TestQueryRanksBySimilarity(t *testing.T) {
+	i, err := New(keywordEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if _, err := i.Index(ctx,
+		index.Document{ID: "1", Content: "a dog and a dog"},
+		index.Document{ID: "2", Content: "a cat"},
+		index.Document{ID: "3", Content: "a fish"},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := i.Query(ctx, "cat", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 || results[0].ID != "2" {
+		t.Fatalf("expected document 2 first, got %+v", results)
+	}
+}
+
+func TestQueryFiltersAndLimit(t *testing.T) {
+	i, err := New(keywordEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if _, err := i.Index(ctx,
+		index.Document{ID: "1", Content: "cat", Metadata: map[string]string{"lang": "en"}},
+		index.Document{ID: "2", Content: "cat cat", Metadata: map[string]string{"lang": "de"}},
+		index.Document{ID: "3", Content: "dog", Metadata: map[string]string{"lang": "en"}},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	limit := 1
+
+	results, err := i.Query(ctx, "cat", &index.QueryOptions{
+		Limit:   &limit,
+		Filters: map[string]string{"lang": "en"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || results[0].ID != "1" {
+		t.Fatalf("expected only document 1, got %+v", results)
+	}
+}
+
+func TestIndexUpsertAndDelete(t *testing.T) {
+	i, err := New(keywordEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	i.Index(ctx, index.Document{ID: "1", Content: "cat"}, index.Document{ID: "2", Content: "dog"})
+	i.Index(ctx, index.Document{ID: "1", Content: "bird"})
+
+	if err := i.Delete(ctx, "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	documents, _ := i.List(ctx, nil)
+
+	if len(documents) != 1 || documents[0].Content != "bird" {
+		t.Fatalf("expected the replaced document only, got %+v", documents)
+	}
+}
+
+func TestIndexReturnsAssignedIDs(t *testing.T) {
+	i, err := New(keywordEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documents := []index.Document{{ID: "1", Content: "cat"}, {Content: "dog"}}
+
+	ids, err := i.Index(context.Background(), documents...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 2 || ids[0] != "1" || ids[1] == "" {
+		t.Fatalf("expected the given and an assigned id, got %v", ids)
+	}
+
+	if documents[1].ID != "" {
+		t.Fatalf("expected the caller's documents to stay unchanged, got %+v", documents[1])
+	}
+}
+
+func TestListDocuments(t *testing.T) {
+	i, err := New(keywordEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	chunk := func(id, document string) index.Document {
+		return index.Document{ID: id, Title: document, Content: "cat", Metadata: map[string]string{"document": document}}
+	}
+
+	i.Index(ctx, chunk("a-1", "a"), chunk("a-2", "a"), chunk("b-1", "b"), index.Document{ID: "loose", Content: "dog"})
+
+	keys := func() []string {
+		documents, _ := i.ListDocuments(ctx)
+
+		var result []string
+
+		for _, d := range documents {
+			result = append(result, d.ID)
+		}
+
+		return result
+	}
+
+	if got := keys(); !slices.Equal(got, []string{"a", "b", "loose"}) {
+		t.Fatalf("documents = %v", got)
+	}
+
+	// moving the last chunk of b to a retires b
+	i.Index(ctx, chunk("b-1", "a"))
+	i.Delete(ctx, "a-1", "loose")
+
+	if got := keys(); !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("documents = %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package custom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/limiter"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	_ limiter.Provider = (*Client)(nil)
+)
+
+type Client struct {
+	url    string
+	client LimiterClient
+}
+
+func New(url string, options ...Option) (*Client, error) {
+	if url == "" || !strings.HasPrefix(url, "grpc://") {
+		return nil, errors.New("invalid url")
+	}
+
+	c := &Client{
+		url: url,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	client, err := grpc.NewClient(strings.TrimPrefix(c.url, "grpc://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.client = NewLimiterClient(client)
+
+	return c, nil
+}
+
+func (c *Client) Add(ctx context.Context, key string, n int64, window time.Duration) (*limiter.Counter, error) {
+	req := &AddRequest{
+		Key: key,
+
+		Value:  n,
+		Window: int64(window.Seconds()),
+	}
+
+	resp, err := c.client.Add(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &limiter.Counter{
+		Value: resp.Value,
+		Reset: time.Unix(resp.Expires, 0),
+	}, nil
+}
@@ -0,0 +1,3 @@
+package custom
+
+type Option func(*Client)
@@ -0,0 +1,54 @@
+package limiter
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Provider keeps usage counters in fixed time windows. Windows are aligned
+// to the Unix epoch, so a 24h window resets at midnight UTC.
+type Provider interface {
+	// Add increments the counter for key by n (0 just reads it) and returns
+	// the updated counter of the current window.
+	Add(ctx context.Context, key string, n int64, window time.Duration) (*Counter, error)
+}
+
+type Counter struct {
+	Value int64
+	Reset time.Time
+}
+
+// Rule limits the traffic of every matching user, counted separately per
+// user and model. Empty Users, Groups or Models match everyone.
+type Rule struct {
+	Users  []string
+	Groups []string
+	Models []string
+
+	// Requests and Tokens cap usage per minute, DailyTokens per UTC day.
+	Requests    int64
+	Tokens      int64
+	DailyTokens int64
+}
+
+func (r Rule) Matches(user string, groups []string, model string) bool {
+	if len(r.Users) > 0 && !slices.Contains(r.Users, user) {
+		return false
+	}
+
+	if len(r.Groups) > 0 && !slices.ContainsFunc(groups, func(g string) bool { return slices.Contains(r.Groups, g) }) {
+		return false
+	}
+
+	if len(r.Models) > 0 && !slices.Contains(r.Models, model) {
+		return false
+	}
+
+	return true
+}
+
+// WindowReset returns the end of the window containing t.
+func WindowReset(t time.Time, window time.Duration) time.Time {
+	return t.Truncate(window).Add(window)
+}
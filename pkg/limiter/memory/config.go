@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"time"
+)
+
+type Option func(*Limiter)
+
+func WithClock(now func() time.Time) Option {
+	return func(l *Limiter) {
+		l.now = now
+	}
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/limiter"
+)
+
+var _ limiter.Provider = (*Limiter)(nil)
+
+// Limiter counts usage in process memory. Counters are not shared between
+// replicas and are lost on restart.
+type Limiter struct {
+	mu       sync.Mutex
+	counters map[string]*limiter.Counter
+
+	// sweep is when counters of elapsed windows are dropped next
+	sweep time.Time
+
+	now func() time.Time
+}
+
+// sweepInterval bounds how often idle counters are dropped. Counters of an
+// active key are reset on access, so sweeping only keeps idle keys from
+// accumulating and need not run on every insert.
+const sweepInterval = time.Minute
+
+func New(options ...Option) *Limiter {
+	l := &Limiter{
+		counters: make(map[string]*limiter.Counter),
+
+		now: time.Now,
+	}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	return l
+}
+
+func (l *Limiter) Add(ctx context.Context, key string, n int64, window time.Duration) (*limiter.Counter, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	if !now.Before(l.sweep) {
+		l.evict(now)
+		l.sweep = now.Add(sweepInterval)
+	}
+
+	c, ok := l.counters[key]
+
+	if !ok || !now.Before(c.Reset) {
+		c = &limiter.Counter{
+			Reset: limiter.WindowReset(now, window),
+		}
+
+		l.counters[key] = c
+	}
+
+	c.Value += n
+
+	return &limiter.Counter{
+		Value: c.Value,
+		Reset: c.Reset,
+	}, nil
+}
+
+// evict drops counters of elapsed windows so idle keys don't accumulate.
+func (l *Limiter) evict(now time.Time) {
+	for key, c := range l.counters {
+		if !now.Before(c.Reset) {
+			delete(l.counters, key)
+		}
+	}
+}
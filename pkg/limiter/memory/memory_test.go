@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWindowReset(t *testing.T) {
+	now := time.Date(2026, 1, 1, 23, 59, 0, 0, time.UTC)
+
+	l := New(WithClock(func() time.Time { return now }))
+
+	ctx := context.Background()
+
+	l.Add(ctx, "key", 5, 24*time.Hour)
+
+	c, _ := l.Add(ctx, "key", 1, 24*time.Hour)
+
+	if c.Value != 6 || !c.Reset.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected counter: %+v", c)
+	}
+
+	now = now.Add(time.Minute)
+
+	c, _ = l.Add(ctx, "key", 0, 24*time.Hour)
+
+	if c.Value != 0 {
+		t.Fatalf("expected counter to reset at midnight UTC, got %d", c.Value)
+	}
+}
+
+func TestIdleCountersAreSwept(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l := New(WithClock(func() time.Time { return now }))
+
+	ctx := context.Background()
+
+	l.Add(ctx, "idle", 1, time.Second)
+
+	now = now.Add(2 * time.Second)
+	l.Add(ctx, "active", 1, time.Hour)
+
+	if _, ok := l.counters["idle"]; !ok {
+		t.Fatal("expected no sweep before the interval elapsed")
+	}
+
+	now = now.Add(sweepInterval)
+	l.Add(ctx, "active", 1, time.Hour)
+
+	if _, ok := l.counters["idle"]; ok {
+		t.Fatal("expected the idle counter to be swept")
+	}
+}
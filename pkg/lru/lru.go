@@ -0,0 +1,123 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is a fixed-capacity, concurrency-safe map that evicts its least
+// recently used entry once the capacity is exceeded.
+type Cache[K comparable, V any] struct {
+	mu sync.Mutex
+
+	capacity int
+
+	ll    *list.List
+	items map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func New[K comparable, V any](capacity int) *Cache[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &Cache[K, V]{
+		capacity: capacity,
+
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value of key and marks it as most recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		return el.Value.(*entry[K, V]).value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Add inserts or replaces the value of key as most recently used.
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*entry[K, V]).value = value
+
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value})
+
+	if c.ll.Len() > c.capacity {
+		if oldest := c.ll.Back(); oldest != nil {
+			c.remove(oldest)
+		}
+	}
+}
+
+// Remove drops key from the cache.
+func (c *Cache[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// RemoveFunc drops every entry for which drop returns true.
+func (c *Cache[K, V]) RemoveFunc(drop func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.ll.Front(); el != nil; {
+		next := el.Next()
+
+		if e := el.Value.(*entry[K, V]); drop(e.key, e.value) {
+			c.remove(el)
+		}
+
+		el = next
+	}
+}
+
+// Values returns the values from most to least recently used, without
+// changing their order.
+func (c *Cache[K, V]) Values() []V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]V, 0, c.ll.Len())
+
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		result = append(result, el.Value.(*entry[K, V]).value)
+	}
+
+	return result
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+func (c *Cache[K, V]) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,45 @@
+package lru
+
+import (
+	"testing"
+)
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2)
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+
+	// touch a, so b is the oldest
+	c.Get("a")
+
+	c.Add("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a to stay, got %v %v", v, ok)
+	}
+
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestRemoveFunc(t *testing.T) {
+	c := New[int, int](10)
+
+	for i := range 5 {
+		c.Add(i, i)
+	}
+
+	c.RemoveFunc(func(_ int, v int) bool {
+		return v%2 == 0
+	})
+
+	if got := c.Values(); len(got) != 2 || got[0] != 3 || got[1] != 1 {
+		t.Fatalf("expected odd values newest first, got %v", got)
+	}
+}
@@ -0,0 +1,39 @@
+package gateway
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/adrianliechti/wingman/pkg/mcp/server"
+	"github.com/adrianliechti/wingman/pkg/otel"
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+// Separator joins the MCP id and the tool name, e.g. github__create_issue.
+const Separator = "__"
+
+// New serves the tools of several MCPs as one MCP. Each tool is named after
+// its MCP and listed only to callers the policy allows to call it; every
+// call is checked against the policy and traced.
+func New(name, instructions string, members map[string][]tool.Provider, p policy.Provider) (*server.Server, error) {
+	if p == nil {
+		p = noop.New()
+	}
+
+	var tools []tool.Provider
+
+	for _, id := range slices.Sorted(maps.Keys(members)) {
+		for _, member := range members[id] {
+			tools = append(tools, otel.NewTool("mcp", &namespacedTool{
+				prefix: id + Separator,
+
+				tool:   member,
+				policy: p,
+			}))
+		}
+	}
+
+	return server.New(name, instructions, tools, server.WithPolicy(p))
+}
@@ -0,0 +1,152 @@
+package gateway
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/tool"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type echoTool struct {
+	names []string
+}
+
+func (t *echoTool) Tools(ctx context.Context) ([]tool.Tool, error) {
+	var result []tool.Tool
+
+	for _, name := range t.names {
+		result = append(result, tool.Tool{
+			Name: name,
+
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"text": map[string]any{"type": "string"}},
+			},
+		})
+	}
+
+	return result, nil
+}
+
+func (t *echoTool) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	text, _ := parameters["text"].(string)
+	return name + ": " + text, nil
+}
+
+// denyPolicy denies calling the listed tools.
+type denyPolicy []string
+
+func (p denyPolicy) Verify(ctx context.Context, resource policy.Resource, id string, action policy.Action) error {
+	if resource == policy.ResourceTool && action == policy.ActionCall && slices.Contains(p, id) {
+		return policy.ErrAccessDenied
+	}
+
+	return nil
+}
+
+func connect(t *testing.T, p policy.Provider) *mcp.ClientSession {
+	t.Helper()
+
+	s, err := New("gateway", "", map[string][]tool.Provider{
+		"github": {&echoTool{names: []string{"create_issue", "delete_repo"}}},
+		"files":  {&echoTool{names: []string{"read"}}},
+	}, p)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer := httptest.NewServer(s)
+	t.Cleanup(httpServer.Close)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{Endpoint: httpServer.URL}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { session.Close() })
+
+	return session
+}
+
+func toolNames(t *testing.T, session *mcp.ClientSession) []string {
+	t.Helper()
+
+	// Tools are registered in the background
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		result, err := session.ListTools(t.Context(), nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(result.Tools) > 0 || time.Now().After(deadline) {
+			var names []string
+
+			for _, tl := range result.Tools {
+				names = append(names, tl.Name)
+			}
+
+			slices.Sort(names)
+
+			return names
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGatewayNamespacesTools(t *testing.T) {
+	session := connect(t, nil)
+
+	want := []string{"files__read", "github__create_issue", "github__delete_repo"}
+
+	if got := toolNames(t, session); !slices.Equal(got, want) {
+		t.Fatalf("tools = %v, want %v", got, want)
+	}
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+		Name:      "github__create_issue",
+		Arguments: map[string]any{"text": "bug"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.Content[0].(*mcp.TextContent).Text; got != "create_issue: bug" {
+		t.Errorf("result = %q", got)
+	}
+}
+
+func TestGatewayEnforcesPolicy(t *testing.T) {
+	session := connect(t, denyPolicy{"github__delete_repo"})
+
+	want := []string{"files__read", "github__create_issue"}
+
+	if got := toolNames(t, session); !slices.Equal(got, want) {
+		t.Fatalf("tools = %v, want %v", got, want)
+	}
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+		Name:      "github__delete_repo",
+		Arguments: map[string]any{"text": "wingman"},
+	})
+
+	if err == nil && !result.IsError {
+		t.Fatal("expected the call to be denied")
+	}
+}
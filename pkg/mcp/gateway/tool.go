@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+var (
+	_ tool.Provider = (*namespacedTool)(nil)
+	_ tool.Resulter = (*namespacedTool)(nil)
+)
+
+// namespacedTool prefixes the tools of an MCP and checks every call against
+// the policy.
+type namespacedTool struct {
+	prefix string
+
+	tool   tool.Provider
+	policy policy.Provider
+}
+
+func (t *namespacedTool) Tools(ctx context.Context) ([]tool.Tool, error) {
+	tools, err := t.tool.Tools(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]tool.Tool, 0, len(tools))
+
+	for _, tl := range tools {
+		tl.Name = t.prefix + tl.Name
+		result = append(result, tl)
+	}
+
+	return result, nil
+}
+
+func (t *namespacedTool) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	inner, ok := strings.CutPrefix(name, t.prefix)
+
+	if !ok {
+		return nil, tool.ErrInvalidTool
+	}
+
+	if err := t.policy.Verify(ctx, policy.ResourceTool, name, policy.ActionCall); err != nil {
+		return nil, err
+	}
+
+	return t.tool.Execute(ctx, inner, parameters)
+}
+
+func (t *namespacedTool) Result(name string, value any) provider.ToolResult {
+	if r, ok := t.tool.(tool.Resulter); ok {
+		return r.Result(strings.TrimPrefix(name, t.prefix), value)
+	}
+
+	return provider.ToolResult{}
+}
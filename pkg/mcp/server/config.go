@@ -0,0 +1,45 @@
+package server
+
+import (
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/policy"
+)
+
+type Option func(*Server)
+
+// WithIndex publishes the documents of an index as resources under
+// index://{id}/{document}.
+func WithIndex(id string, index index.Provider) Option {
+	return func(s *Server) {
+		s.indexes[id] = index
+	}
+}
+
+// WithResources publishes files by URI (file://, http:// or https://).
+func WithResources(resources ...Resource) Option {
+	return func(s *Server) {
+		s.resources = append(s.resources, resources...)
+	}
+}
+
+// WithExtractor converts binary resources, e.g. PDFs, to text when read.
+func WithExtractor(extractor extractor.Provider) Option {
+	return func(s *Server) {
+		s.extractor = extractor
+	}
+}
+
+func WithPrompts(prompts ...Prompt) Option {
+	return func(s *Server) {
+		s.prompts = append(s.prompts, prompts...)
+	}
+}
+
+// WithPolicy lists only the tools the caller may call (policy.ResourceTool
+// with policy.ActionCall). It does not check the calls themselves.
+func WithPolicy(policy policy.Provider) Option {
+	return func(s *Server) {
+		s.policy = policy
+	}
+}
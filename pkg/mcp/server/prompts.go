@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adrianliechti/wingman/pkg/template"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Prompt is a named prompt template. It is rendered with the client's
+// arguments as data, e.g. {{ .language }}.
+type Prompt struct {
+	Name        string
+	Title       string
+	Description string
+
+	Arguments []PromptArgument
+
+	Template *template.Template
+}
+
+type PromptArgument struct {
+	Name        string
+	Description string
+
+	Required bool
+}
+
+func (s *Server) addPrompt(p Prompt) {
+	prompt := &mcp.Prompt{
+		Name:        p.Name,
+		Title:       p.Title,
+		Description: p.Description,
+	}
+
+	for _, a := range p.Arguments {
+		prompt.Arguments = append(prompt.Arguments, &mcp.PromptArgument{
+			Name:        a.Name,
+			Description: a.Description,
+
+			Required: a.Required,
+		})
+	}
+
+	s.server.AddPrompt(prompt, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		data := map[string]any{}
+
+		for key, value := range req.Params.Arguments {
+			data[key] = value
+		}
+
+		for _, a := range p.Arguments {
+			if a.Required && req.Params.Arguments[a.Name] == "" {
+				return nil, errors.New("missing argument: " + a.Name)
+			}
+		}
+
+		text, err := p.Template.Execute(data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &mcp.GetPromptResult{
+			Description: p.Description,
+
+			Messages: []*mcp.PromptMessage{
+				{
+					Role:    "user",
+					Content: &mcp.TextContent{Text: text},
+				},
+			},
+		}, nil
+	})
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/template"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGetPromptRendersTemplate(t *testing.T) {
+	s, err := New("wingman-test", "", nil, WithPrompts(Prompt{
+		Name: "review",
+
+		Arguments: []PromptArgument{
+			{Name: "language", Required: true},
+		},
+
+		Template: template.MustTemplate("Review this {{ .language }} code."),
+	}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := connectTo(t, s)
+
+	result, err := session.GetPrompt(t.Context(), &mcp.GetPromptParams{
+		Name:      "review",
+		Arguments: map[string]string{"language": "Go"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.Messages[0].Content.(*mcp.TextContent).Text; got != "Review this Go code." {
+		t.Errorf("text = %q", got)
+	}
+
+	if _, err := session.GetPrompt(t.Context(), &mcp.GetPromptParams{Name: "review"}); err == nil {
+		t.Error("expected an error for the missing argument")
+	}
+}
@@ -0,0 +1,329 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const maxResourceSize = 32 << 20
+
+// Resource is a file published by URI: file:// paths are read from disk,
+// http(s) URLs are fetched on every read.
+type Resource struct {
+	URI string
+
+	Name        string
+	Description string
+
+	MIMEType string
+}
+
+func (s *Server) addResource(r Resource) {
+	u, err := url.Parse(r.URI)
+
+	if err != nil {
+		return
+	}
+
+	name := r.Name
+
+	if name == "" {
+		name = path.Base(u.Path)
+	}
+
+	s.server.AddResource(&mcp.Resource{
+		URI: r.URI,
+
+		Name:        name,
+		Description: r.Description,
+
+		MIMEType: r.MIMEType,
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		file, err := readFile(ctx, u)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if r.MIMEType != "" {
+			file.ContentType = r.MIMEType
+		}
+
+		contents, err := s.fileContents(ctx, r.URI, file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{contents},
+		}, nil
+	})
+}
+
+// fileContents returns text files as text, converts other files to text
+// with the extractor if there is one and returns them as blobs otherwise.
+func (s *Server) fileContents(ctx context.Context, uri string, file *provider.File) (*mcp.ResourceContents, error) {
+	if isText(file.ContentType) {
+		return &mcp.ResourceContents{
+			URI:      uri,
+			MIMEType: file.ContentType,
+			Text:     string(file.Content),
+		}, nil
+	}
+
+	if s.extractor != nil {
+		doc, err := s.extractor.Extract(ctx, *file, &extractor.ExtractOptions{})
+
+		if err == nil {
+			return &mcp.ResourceContents{
+				URI:      uri,
+				MIMEType: "text/plain",
+				Text:     doc.Text,
+			}, nil
+		}
+
+		if !errors.Is(err, extractor.ErrUnsupported) {
+			return nil, err
+		}
+	}
+
+	return &mcp.ResourceContents{
+		URI:      uri,
+		MIMEType: file.ContentType,
+		Blob:     file.Content,
+	}, nil
+}
+
+// refreshResources publishes the documents of the indexes. Chunks ingested
+// by a pipeline are grouped by their document.
+func (s *Server) refreshResources(ctx context.Context) error {
+	var resultErr error
+
+	for id, idx := range s.indexes {
+		// listed without the lock, a slow index must not hold up the others
+		documents, err := index.ListDocuments(ctx, idx)
+
+		if err != nil {
+			resultErr = errors.Join(resultErr, err)
+			continue
+		}
+
+		s.publishDocuments(id, documents)
+	}
+
+	return resultErr
+}
+
+// publishDocuments makes the documents the resources of index id.
+func (s *Server) publishDocuments(id string, documents []index.Document) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make(map[string]bool)
+
+	for _, d := range documents {
+		uri := indexURI(id, d.Key())
+
+		if current[uri] {
+			continue
+		}
+
+		current[uri] = true
+
+		if s.registeredResources[uri] {
+			continue
+		}
+
+		name := cmp.Or(d.Title, d.Source, d.Key())
+
+		s.server.AddResource(&mcp.Resource{
+			URI: uri,
+
+			Name:        name,
+			Description: d.Source,
+
+			MIMEType: "text/plain",
+		}, s.readIndexDocument)
+	}
+
+	var stale []string
+
+	for uri := range s.registeredResources {
+		if strings.HasPrefix(uri, indexURI(id, "")) && !current[uri] {
+			stale = append(stale, uri)
+		}
+	}
+
+	if len(stale) > 0 {
+		s.server.RemoveResources(stale...)
+	}
+
+	for _, uri := range stale {
+		delete(s.registeredResources, uri)
+	}
+
+	for uri := range current {
+		s.registeredResources[uri] = true
+	}
+}
+
+// readIndexDocument returns the text of index://{index}/{document}: the
+// chunks of the document in order.
+func (s *Server) readIndexDocument(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+
+	u, err := url.Parse(uri)
+
+	if err != nil || u.Scheme != "index" {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	idx, ok := s.indexes[u.Host]
+
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	key := strings.TrimPrefix(u.Path, "/")
+
+	documents, err := idx.List(ctx, &index.ListOptions{
+		Filters: map[string]string{"document": key},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(documents) == 0 {
+		all, err := idx.List(ctx, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range all {
+			if d.ID == key {
+				documents = append(documents, d)
+			}
+		}
+	}
+
+	if len(documents) == 0 {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	slices.SortStableFunc(documents, func(a, b index.Document) int {
+		x, _ := strconv.Atoi(a.Metadata["chunk"])
+		y, _ := strconv.Atoi(b.Metadata["chunk"])
+
+		return x - y
+	})
+
+	var texts []string
+
+	for _, d := range documents {
+		texts = append(texts, d.Content)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: "text/plain",
+				Text:     strings.Join(texts, "\n\n"),
+			},
+		},
+	}, nil
+}
+
+func indexURI(id, document string) string {
+	return "index://" + id + "/" + url.PathEscape(document)
+}
+
+func readFile(ctx context.Context, u *url.URL) (*provider.File, error) {
+	switch u.Scheme {
+	case "file":
+		data, err := os.ReadFile(u.Path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		contentType, _, _ := mime.ParseMediaType(mime.TypeByExtension(path.Ext(u.Path)))
+
+		return &provider.File{
+			Name: path.Base(u.Path),
+
+			Content:     data,
+			ContentType: contentType,
+		}, nil
+
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := provider.DefaultClient.Do(req)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s failed: %s", u, resp.Status)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxResourceSize+1))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) > maxResourceSize {
+			return nil, fmt.Errorf("resource %s too large", u)
+		}
+
+		contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+		return &provider.File{
+			Name: path.Base(u.Path),
+
+			Content:     data,
+			ContentType: contentType,
+		}, nil
+	}
+
+	return nil, errors.New("unsupported resource scheme: " + u.Scheme)
+}
+
+func isText(contentType string) bool {
+	switch {
+	case strings.HasPrefix(contentType, "text/"):
+		return true
+	case contentType == "application/json", contentType == "application/xml", contentType == "application/yaml":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/index/memory"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, []float32{1, 0})
+	}
+
+	return result, nil
+}
+
+func TestReadFileResource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.md")
+
+	if err := os.WriteFile(path, []byte("# Notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New("wingman-test", "", nil, WithResources(Resource{URI: "file://" + path, MIMEType: "text/markdown"}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := connectTo(t, s)
+
+	list, err := session.ListResources(t.Context(), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Resources) != 1 || list.Resources[0].Name != "notes.md" {
+		t.Fatalf("resources = %+v", list.Resources)
+	}
+
+	result, err := session.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: "file://" + path})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.Contents[0].Text; got != "# Notes" {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestReadIndexDocumentJoinsChunks(t *testing.T) {
+	idx, err := memory.New(fakeEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx.Index(t.Context(),
+		index.Document{ID: "doc-2", Title: "Handbook", Content: "Second.", Metadata: map[string]string{"document": "doc", "chunk": "2"}},
+		index.Document{ID: "doc-1", Title: "Handbook", Content: "First.", Metadata: map[string]string{"document": "doc", "chunk": "1"}},
+		index.Document{ID: "faq", Title: "FAQ", Content: "Answers."},
+	)
+
+	s, err := New("wingman-test", "", nil, WithIndex("docs", idx))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.refreshResources(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	session := connectTo(t, s)
+
+	list, err := session.ListResources(t.Context(), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Resources) != 2 {
+		t.Fatalf("resources = %+v", list.Resources)
+	}
+
+	result, err := session.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: "index://docs/doc"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.Contents[0].Text; got != "First.\n\nSecond." {
+		t.Errorf("text = %q", got)
+	}
+
+	idx.Delete(t.Context(), "faq")
+
+	if err := s.refreshResources(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err = session.ListResources(t.Context(), nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Resources) != 1 || list.Resources[0].URI != "index://docs/doc" {
+		t.Fatalf("resources = %+v", list.Resources)
+	}
+}
+
+func TestReadURLResourceTooLarge(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("a"), maxResourceSize+1))
+	}))
+
+	defer upstream.Close()
+
+	u, _ := url.Parse(upstream.URL + "/large.txt")
+
+	if _, err := readFile(t.Context(), u); err == nil {
+		t.Fatal("expected an oversized resource to fail")
+	}
+}
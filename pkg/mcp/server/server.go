@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index"
 	mcppkg "github.com/adrianliechti/wingman/pkg/mcp"
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/tool"
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -21,6 +26,14 @@ type Server struct {
 
 	tools []tool.Provider
 
+	indexes   map[string]index.Provider
+	resources []Resource
+	extractor extractor.Provider
+
+	prompts []Prompt
+
+	policy policy.Provider
+
 	server *mcp.Server
 
 	mu sync.Mutex
@@ -28,9 +41,15 @@ type Server struct {
 	// their definition, so a refresh can retire tools that disappeared upstream
 	// and skip re-adding ones that did not change.
 	registered []map[string]string
+
+	// registeredResources holds the URIs of the published index documents.
+	registeredResources map[string]bool
+
+	// stop ends the refresh loop, see Close
+	stop context.CancelFunc
 }
 
-func New(name, instructions string, tools []tool.Provider) (*Server, error) {
+func New(name, instructions string, tools []tool.Provider, options ...Option) (*Server, error) {
 	serverImpl := &mcp.Implementation{
 		Name:    name,
 		Version: "1.0.0",
@@ -58,32 +77,107 @@ func New(name, instructions string, tools []tool.Provider) (*Server, error) {
 		server: server,
 		tools:  tools,
 
-		registered: make([]map[string]string, len(tools)),
+		indexes: make(map[string]index.Provider),
+
+		registered:          make([]map[string]string, len(tools)),
+		registeredResources: make(map[string]bool),
+	}
+
+	for _, option := range options {
+		option(s)
 	}
 
-	go s.refresh()
+	if s.policy != nil {
+		server.AddReceivingMiddleware(s.filterTools)
+	}
+
+	for _, r := range s.resources {
+		s.addResource(r)
+	}
+
+	for _, p := range s.prompts {
+		s.addPrompt(p)
+	}
+
+	if len(s.indexes) > 0 {
+		server.AddResourceTemplate(&mcp.ResourceTemplate{
+			URITemplate: "index://{index}/{document}",
+
+			Name:        "document",
+			Description: "A document in an index",
+
+			MIMEType: "text/plain",
+		}, s.readIndexDocument)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop = cancel
+
+	go s.refresh(ctx)
 
 	return s, nil
 }
 
+// Close stops refreshing the tools and resources. Sessions keep being
+// served with what was registered last.
+func (s *Server) Close() error {
+	s.stop()
+	return nil
+}
+
 func (s *Server) Icon() (string, []byte) {
 	return "", nil
 }
 
-func (s *Server) refresh() {
-	for {
-		if err := s.refreshTools(); err != nil {
-			time.Sleep(time.Second * 30)
-			continue
+// filterTools removes the tools the caller may not call from tools/list.
+func (s *Server) filterTools(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+
+		if err != nil || method != "tools/list" {
+			return result, err
+		}
+
+		list, ok := result.(*mcp.ListToolsResult)
+
+		if !ok {
+			return result, nil
+		}
+
+		tools := make([]*mcp.Tool, 0, len(list.Tools))
+
+		for _, t := range list.Tools {
+			if s.policy.Verify(ctx, policy.ResourceTool, t.Name, policy.ActionCall) != nil {
+				continue
+			}
+
+			tools = append(tools, t)
 		}
 
-		time.Sleep(time.Minute * 5)
+		list.Tools = tools
+
+		return list, nil
 	}
 }
 
-func (s *Server) refreshTools() error {
-	ctx := context.Background()
+func (s *Server) refresh(ctx context.Context) {
+	for {
+		delay := time.Minute * 5
+
+		if err := errors.Join(s.refreshTools(ctx), s.refreshResources(ctx)); err != nil {
+			delay = time.Second * 30
+		}
 
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (s *Server) refreshTools(ctx context.Context) error {
 	var resultErr error
 
 	s.mu.Lock()
@@ -135,9 +229,20 @@ func (s *Server) refreshTools() error {
 					return nil, err
 				}
 
-				switch v := result.(type) {
-				case *mcp.CallToolResult:
+				// Results of MCP-backed tools are passed through as they are
+				if v, ok := result.(*mcp.CallToolResult); ok {
 					return v, nil
+				}
+
+				if r, ok := p.(tool.Resulter); ok {
+					if content := toolContent(r.Result(t.Name, result)); len(content) > 0 {
+						return &mcp.CallToolResult{
+							Content: content,
+						}, nil
+					}
+				}
+
+				switch v := result.(type) {
 
 				case string:
 					return &mcp.CallToolResult{
@@ -186,3 +291,44 @@ func (s *Server) refreshTools() error {
 
 	return resultErr
 }
+
+// toolContent maps a tool result to MCP content: text as text, images and
+// audio as such, and other files as embedded resources.
+func toolContent(result provider.ToolResult) []mcp.Content {
+	var content []mcp.Content
+
+	for _, part := range result.Parts {
+		if part.Text != "" {
+			content = append(content, &mcp.TextContent{
+				Text: part.Text,
+			})
+		}
+
+		if f := part.File; f != nil {
+			switch {
+			case strings.HasPrefix(f.ContentType, "image/"):
+				content = append(content, &mcp.ImageContent{
+					Data:     f.Content,
+					MIMEType: f.ContentType,
+				})
+
+			case strings.HasPrefix(f.ContentType, "audio/"):
+				content = append(content, &mcp.AudioContent{
+					Data:     f.Content,
+					MIMEType: f.ContentType,
+				})
+
+			default:
+				content = append(content, &mcp.EmbeddedResource{
+					Resource: &mcp.ResourceContents{
+						URI:      "file:///" + f.Name,
+						MIMEType: f.ContentType,
+						Blob:     f.Content,
+					},
+				})
+			}
+		}
+	}
+
+	return content
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/tool"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -92,7 +93,7 @@ func newServer(t *testing.T, instructions string, providers ...tool.Provider) *S
 		t.Fatal(err)
 	}
 
-	if err := s.refreshTools(); err != nil {
+	if err := s.refreshTools(t.Context()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -179,7 +180,7 @@ func TestRefreshRetiresRemovedTools(t *testing.T) {
 
 	p.set([]string{"alpha"}, nil)
 
-	if err := s.refreshTools(); err != nil {
+	if err := s.refreshTools(t.Context()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -197,7 +198,7 @@ func TestRefreshKeepsToolsOnUpstreamError(t *testing.T) {
 
 	p.set(nil, errors.New("upstream down"))
 
-	if err := s.refreshTools(); err == nil {
+	if err := s.refreshTools(t.Context()); err == nil {
 		t.Fatal("expected error")
 	}
 
@@ -236,7 +237,7 @@ func TestRefreshIsQuietWhenNothingChanged(t *testing.T) {
 
 	t.Cleanup(func() { session.Close() })
 
-	if err := s.refreshTools(); err != nil {
+	if err := s.refreshTools(t.Context()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -248,7 +249,7 @@ func TestRefreshIsQuietWhenNothingChanged(t *testing.T) {
 
 	p.set([]string{"alpha", "beta"}, nil)
 
-	if err := s.refreshTools(); err != nil {
+	if err := s.refreshTools(t.Context()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -286,3 +287,84 @@ func TestListAndCallTool(t *testing.T) {
 		t.Errorf("content = %+v", result.Content)
 	}
 }
+
+type imageProvider struct {
+	fakeProvider
+}
+
+func (p *imageProvider) Result(name string, value any) provider.ToolResult {
+	return provider.ToolResult{
+		Parts: []provider.Part{
+			{Text: "generated"},
+			{File: &provider.File{Name: "image.png", Content: []byte("png"), ContentType: "image/png"}},
+		},
+	}
+}
+
+// TestCallToolReturnsFileParts asserts tools rendering file parts (images,
+// audio) reach MCP clients as typed content rather than serialized JSON.
+func TestCallToolReturnsFileParts(t *testing.T) {
+	session := connectTo(t, newServer(t, "", &imageProvider{fakeProvider{names: []string{"draw"}}}))
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "draw", Arguments: map[string]any{}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Content) != 2 {
+		t.Fatalf("content = %d parts, want 2", len(result.Content))
+	}
+
+	image, ok := result.Content[1].(*mcp.ImageContent)
+
+	if !ok {
+		t.Fatalf("content[1] = %T, want image", result.Content[1])
+	}
+
+	if image.MIMEType != "image/png" || string(image.Data) != "png" {
+		t.Errorf("image = %s %q", image.MIMEType, image.Data)
+	}
+}
+
+// contextProvider hands out the contexts its tools are listed with.
+type contextProvider struct {
+	fakeProvider
+
+	contexts chan context.Context
+}
+
+func (p *contextProvider) Tools(ctx context.Context) ([]tool.Tool, error) {
+	select {
+	case p.contexts <- ctx:
+	default:
+	}
+
+	return p.fakeProvider.Tools(ctx)
+}
+
+func TestCloseStopsRefresh(t *testing.T) {
+	p := &contextProvider{contexts: make(chan context.Context, 1)}
+
+	s, err := New("wingman-test", "", []tool.Provider{p})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ctx context.Context
+
+	select {
+	case ctx = <-p.contexts:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tools were not refreshed")
+	}
+
+	s.Close()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresh still running after close")
+	}
+}
@@ -0,0 +1,286 @@
+package stdio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	DefaultConcurrency = 4
+	DefaultIdleTimeout = 10 * time.Minute
+
+	// A process that exits sooner than this after starting counts as a
+	// crash and delays the next start.
+	minUptime = 10 * time.Second
+
+	maxBackoff = time.Minute
+)
+
+// Command describes a local MCP server speaking JSON-RPC over stdin/stdout.
+type Command struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// Process supervises one running instance of a command: it starts the
+// process on first use, shares its session between callers up to the
+// concurrency limit, restarts it after a crash (backing off while it keeps
+// crashing) and stops it when idle.
+type Process struct {
+	command Command
+
+	sem chan struct{}
+
+	idleTimeout time.Duration
+
+	mu sync.Mutex
+
+	session *mcp.ClientSession
+	started time.Time
+
+	crashes int
+	retryAt time.Time
+
+	lastUsed time.Time
+	reaping  bool
+}
+
+var (
+	poolMu sync.Mutex
+	pool   = map[string]*Process{}
+)
+
+// Shared returns the process for the command and concurrency, starting a
+// new one only if none exists. Processes are shared process-wide, so a
+// configuration reload keeps unchanged servers running.
+func Shared(command Command, concurrency int) *Process {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	data, _ := json.Marshal(command)
+	key := fmt.Sprintf("%s/%d", data, concurrency)
+
+	poolMu.Lock()
+	defer poolMu.Unlock()
+
+	if p, ok := pool[key]; ok {
+		return p
+	}
+
+	p := New(command, concurrency, DefaultIdleTimeout)
+	pool[key] = p
+
+	return p
+}
+
+func New(command Command, concurrency int, idleTimeout time.Duration) *Process {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	return &Process{
+		command: command,
+
+		sem: make(chan struct{}, concurrency),
+
+		idleTimeout: idleTimeout,
+	}
+}
+
+// Session returns a connected session, starting the process if needed.
+// Callers must call release when done; it frees the concurrency slot but
+// keeps the process running.
+func (p *Process) Session(ctx context.Context) (*mcp.ClientSession, func(), error) {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	release := func() {
+		p.mu.Lock()
+		p.lastUsed = time.Now()
+		p.mu.Unlock()
+
+		<-p.sem
+	}
+
+	session, err := p.connect(ctx)
+
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	return session, release, nil
+}
+
+// Close stops the process. It is started again on the next use.
+func (p *Process) Close() error {
+	p.mu.Lock()
+	session := p.session
+	p.session = nil
+	p.mu.Unlock()
+
+	if session == nil {
+		return nil
+	}
+
+	return session.Close()
+}
+
+func (p *Process) connect(ctx context.Context) (*mcp.ClientSession, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.session != nil {
+		return p.session, nil
+	}
+
+	if wait := time.Until(p.retryAt); wait > 0 {
+		return nil, fmt.Errorf("mcp: %s keeps crashing, restarting in %s", p.command.Command, wait.Round(time.Second))
+	}
+
+	if p.command.Command == "" {
+		return nil, errors.New("mcp: missing command")
+	}
+
+	cmd := exec.Command(p.command.Command, p.command.Args...)
+	cmd.Env = environ(p.command.Env)
+
+	cmd.Stderr = &stderrLog{command: p.command.Command}
+
+	impl := &mcp.Implementation{
+		Name:    "wingman",
+		Version: "1.0.0",
+	}
+
+	client := mcp.NewClient(impl, nil)
+
+	session, err := client.Connect(ctx, &mcp.CommandTransport{Command: cmd}, nil)
+
+	if err != nil {
+		p.crashed()
+		return nil, err
+	}
+
+	p.session = session
+	p.started = time.Now()
+	p.lastUsed = p.started
+
+	go p.watch(session)
+
+	if !p.reaping && p.idleTimeout > 0 {
+		p.reaping = true
+		go p.reap()
+	}
+
+	return session, nil
+}
+
+// watch clears the session once the process exits so the next use
+// restarts it.
+func (p *Process) watch(session *mcp.ClientSession) {
+	err := session.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.session != session {
+		return // closed on purpose
+	}
+
+	p.session = nil
+
+	if time.Since(p.started) < minUptime {
+		p.crashed()
+	} else {
+		p.crashes = 0
+	}
+
+	slog.Warn("mcp: stdio server exited", "command", p.command.Command, "error", err)
+}
+
+// crashed records a failed start and backs off exponentially. Must be
+// called with mu held.
+func (p *Process) crashed() {
+	p.crashes++
+
+	backoff := min(time.Second<<min(p.crashes-1, 6), maxBackoff)
+	p.retryAt = time.Now().Add(backoff)
+}
+
+// reap stops the process once it has been idle for the idle timeout.
+func (p *Process) reap() {
+	ticker := time.NewTicker(p.idleTimeout / 4)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		p.mu.Lock()
+
+		session := p.session
+		idle := session != nil && len(p.sem) == 0 && time.Since(p.lastUsed) > p.idleTimeout
+
+		if idle {
+			p.session = nil
+		}
+
+		p.mu.Unlock()
+
+		if idle {
+			session.Close()
+		}
+	}
+}
+
+// inheritedEnv are the only variables a server inherits from wingman, so
+// provider keys and other secrets in its environment stay out of reach.
+var inheritedEnv = []string{
+	"PATH",
+	"HOME",
+}
+
+// environ returns the environment of a server: the inherited variables
+// plus the ones configured for it.
+func environ(env map[string]string) []string {
+	var result []string
+
+	for _, key := range inheritedEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			result = append(result, key+"="+value)
+		}
+	}
+
+	for key, value := range env {
+		result = append(result, key+"="+value)
+	}
+
+	return result
+}
+
+// stderrLog forwards what the server writes to stderr to the debug log.
+type stderrLog struct {
+	command string
+}
+
+func (l *stderrLog) Write(data []byte) (int, error) {
+	for line := range strings.Lines(string(data)) {
+		if line = strings.TrimSpace(line); line != "" {
+			slog.Debug("mcp: stdio server", "command", l.command, "stderr", line)
+		}
+	}
+
+	return len(data), nil
+}
@@ -0,0 +1,177 @@
+package stdio
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TestMain doubles as the MCP server under test: started with
+// STDIO_TEST_SERVER=1, the test binary serves an echo and a crash tool over
+// stdio.
+func TestMain(m *testing.M) {
+	if os.Getenv("STDIO_TEST_SERVER") == "1" {
+		serve()
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+type echoArgs struct {
+	Text string `json:"text"`
+}
+
+func serve() {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(ctx context.Context, req *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: args.Text}}}, nil, nil
+	})
+
+	mcp.AddTool(server, &mcp.Tool{Name: "crash"}, func(ctx context.Context, req *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		os.Exit(1)
+		return nil, nil, nil
+	})
+
+	mcp.AddTool(server, &mcp.Tool{Name: "env"}, func(ctx context.Context, req *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: os.Getenv(args.Text)}}}, nil, nil
+	})
+
+	server.Run(context.Background(), &mcp.StdioTransport{})
+}
+
+func testCommand(t *testing.T) Command {
+	t.Helper()
+
+	executable, err := os.Executable()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Command{
+		Command: executable,
+		Env:     map[string]string{"STDIO_TEST_SERVER": "1"},
+	}
+}
+
+func call(t *testing.T, p *Process, name, text string) (string, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	session, release, err := p.Session(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer release()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: map[string]any{"text": text}})
+
+	if err != nil {
+		return "", err
+	}
+
+	return result.Content[0].(*mcp.TextContent).Text, nil
+}
+
+func TestProcessSharesSession(t *testing.T) {
+	p := New(testCommand(t), 2, time.Minute)
+	t.Cleanup(func() { p.Close() })
+
+	for _, text := range []string{"one", "two"} {
+		got, err := call(t, p, "echo", text)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != text {
+			t.Errorf("echo = %q, want %q", got, text)
+		}
+	}
+}
+
+func TestProcessEnvironment(t *testing.T) {
+	t.Setenv("STDIO_TEST_SECRET", "secret")
+
+	p := New(testCommand(t), 1, time.Minute)
+	t.Cleanup(func() { p.Close() })
+
+	for name, want := range map[string]string{
+		"STDIO_TEST_SERVER": "1",
+		"STDIO_TEST_SECRET": "",
+		"PATH":              os.Getenv("PATH"),
+	} {
+		got, err := call(t, p, "env", name)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestProcessRestartsAfterCrash(t *testing.T) {
+	p := New(testCommand(t), 1, time.Minute)
+	t.Cleanup(func() { p.Close() })
+
+	if _, err := call(t, p, "echo", "before"); err != nil {
+		t.Fatal(err)
+	}
+
+	call(t, p, "crash", "")
+
+	// The crash is detected asynchronously and delays the restart
+	deadline := time.Now().Add(10 * time.Second)
+
+	for {
+		got, err := call(t, p, "echo", "after")
+
+		if err == nil && got == "after" {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("no restart: %v", err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestProcessLimitsConcurrency(t *testing.T) {
+	p := New(testCommand(t), 1, time.Minute)
+	t.Cleanup(func() { p.Close() })
+
+	_, release, err := p.Session(t.Context())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := p.Session(ctx); err == nil {
+		t.Fatal("expected the second session to wait for the first")
+	}
+
+	release()
+
+	if _, release, err := p.Session(t.Context()); err != nil {
+		t.Fatal(err)
+	} else {
+		release()
+	}
+}
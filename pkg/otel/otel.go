@@ -2,6 +2,7 @@ package otel
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -9,8 +10,11 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
+// shutdowns flush and stop the exporters started by Setup.
+var shutdowns []func(context.Context) error
+
 func Setup() error {
-	if !EnableTelemetry {
+	if !EnableTelemetry && !EnablePrometheus {
 		return nil
 	}
 
@@ -41,16 +45,18 @@ func Setup() error {
 		return err
 	}
 
-	if err := setupTracer(ctx, resource); err != nil {
-		return err
-	}
-
 	if err := setupMeter(ctx, resource); err != nil {
 		return err
 	}
 
-	if err := setupLogger(ctx, resource); err != nil {
-		return err
+	if EnableTelemetry {
+		if err := setupTracer(ctx, resource); err != nil {
+			return err
+		}
+
+		if err := setupLogger(ctx, resource); err != nil {
+			return err
+		}
 	}
 
 	if err := setupHTTP(ctx, resource); err != nil {
@@ -59,3 +65,17 @@ func Setup() error {
 
 	return nil
 }
+
+// Shutdown flushes pending telemetry and stops the exporters. Call it once
+// the server has drained, so spans of the last requests are not lost.
+func Shutdown(ctx context.Context) error {
+	var errs []error
+
+	for _, shutdown := range shutdowns {
+		if err := shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
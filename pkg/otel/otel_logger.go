@@ -36,6 +36,7 @@ func setupLogger(ctx context.Context, resource *sdkresource.Resource) error {
 	)
 
 	global.SetLoggerProvider(provider)
+	shutdowns = append(shutdowns, provider.Shutdown)
 
 	logger := otelslog.NewLogger("", otelslog.WithLoggerProvider(provider))
 	slog.SetDefault(logger)
@@ -18,6 +18,41 @@ import (
 )
 
 func setupMeter(ctx context.Context, resource *sdkresource.Resource) error {
+	options := []sdkmetric.Option{
+		sdkmetric.WithResource(resource),
+	}
+
+	if EnableTelemetry {
+		readers, err := otlpReaders(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		for _, r := range readers {
+			options = append(options, sdkmetric.WithReader(r))
+		}
+	}
+
+	if EnablePrometheus {
+		reader, err := prometheusReader()
+
+		if err != nil {
+			return err
+		}
+
+		options = append(options, sdkmetric.WithReader(reader))
+	}
+
+	provider := sdkmetric.NewMeterProvider(options...)
+
+	otel.SetMeterProvider(provider)
+	shutdowns = append(shutdowns, provider.Shutdown)
+
+	return nil
+}
+
+func otlpReaders(ctx context.Context) ([]sdkmetric.Reader, error) {
 	var err error
 	var exporter sdkmetric.Exporter
 
@@ -28,12 +63,11 @@ func setupMeter(ctx context.Context, resource *sdkresource.Resource) error {
 	}
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	options := []sdkmetric.Option{
-		sdkmetric.WithResource(resource),
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(30*time.Second))),
+	readers := []sdkmetric.Reader{
+		sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(30*time.Second)),
 	}
 
 	// insights sums the datapoints it receives, so it must consume delta
@@ -47,15 +81,11 @@ func setupMeter(ctx context.Context, resource *sdkresource.Resource) error {
 			otlpmetrichttp.WithTemporalitySelector(deltaTemporality),
 		)
 		if err == nil {
-			options = append(options, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(insights, sdkmetric.WithInterval(60*time.Second))))
+			readers = append(readers, sdkmetric.NewPeriodicReader(insights, sdkmetric.WithInterval(60*time.Second)))
 		}
 	}
 
-	provider := sdkmetric.NewMeterProvider(options...)
-
-	otel.SetMeterProvider(provider)
-
-	return nil
+	return readers, nil
 }
 
 func endpointWithDefaultPath(endpoint, defaultPath string) string {
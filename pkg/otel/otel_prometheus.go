@@ -0,0 +1,33 @@
+package otel
+
+import (
+	"net/http"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+var metricsHandler http.Handler
+
+// MetricsHandler serves the metrics in the Prometheus exposition format. It
+// is nil unless EnablePrometheus was set before Setup.
+func MetricsHandler() http.Handler {
+	return metricsHandler
+}
+
+func prometheusReader() (sdkmetric.Reader, error) {
+	registry := promclient.NewRegistry()
+
+	reader, err := prometheus.New(prometheus.WithRegisterer(registry))
+
+	if err != nil {
+		return nil, err
+	}
+
+	metricsHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+
+	return reader, nil
+}
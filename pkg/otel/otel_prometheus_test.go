@@ -0,0 +1,32 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestPrometheusReader(t *testing.T) {
+	reader, err := prometheusReader()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer provider.Shutdown(context.Background())
+
+	counter, _ := provider.Meter(instrumentationName).Float64Counter("gen_ai.client.cost")
+	counter.Add(context.Background(), 1.5)
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if !strings.Contains(rec.Body.String(), "gen_ai_client_cost") {
+		t.Fatalf("expected cost metric in scrape, got %s", rec.Body.String())
+	}
+}
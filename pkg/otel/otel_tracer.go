@@ -46,6 +46,7 @@ func setupTracer(ctx context.Context, resource *sdkresource.Resource) error {
 	provider := sdktrace.NewTracerProvider(options...)
 
 	otel.SetTracerProvider(provider)
+	shutdowns = append(shutdowns, provider.Shutdown)
 
 	return nil
 }
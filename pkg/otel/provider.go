@@ -9,6 +9,10 @@ const instrumentationName = "github.com/adrianliechti/wingman"
 var (
 	EnableDebug     = false
 	EnableTelemetry = false
+
+	// EnablePrometheus adds a Prometheus reader to the meter provider, so
+	// metrics can be scraped from MetricsHandler without an OTLP collector.
+	EnablePrometheus = false
 )
 
 func init() {
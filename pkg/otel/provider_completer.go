@@ -24,13 +24,15 @@ type observableCompleter struct {
 
 	completer provider.Completer
 
+	cost costRecorder
+
 	tokenUsageMetric         genaiconv.ClientTokenUsage
 	operationDurationMetric  genaiconv.ClientOperationDuration
 	timeToFirstChunkMetric   genaiconv.ClientOperationTimeToFirstChunk
 	timePerOutputChunkMetric genaiconv.ClientOperationTimePerOutputChunk
 }
 
-func NewCompleter(provider, model string, p provider.Completer) Completer {
+func NewCompleter(provider, model string, p provider.Completer, opts ...Option) Completer {
 	meter := otel.Meter(instrumentationName)
 
 	tokenUsageMetric, _ := genaiconv.NewClientTokenUsage(meter)
@@ -44,6 +46,8 @@ func NewCompleter(provider, model string, p provider.Completer) Completer {
 		model:    model,
 		provider: provider,
 
+		cost: newCostRecorder(newOptions(opts)),
+
 		tokenUsageMetric:         tokenUsageMetric,
 		operationDurationMetric:  operationDurationMetric,
 		timeToFirstChunkMetric:   timeToFirstChunkMetric,
@@ -131,7 +135,13 @@ func (p *observableCompleter) Complete(ctx context.Context, messages []provider.
 					genaiconv.OperationNameChat, providerName, attrs...)
 			}
 
-			if lastResult == nil || lastResult.Usage == nil {
+			if lastResult == nil {
+				return
+			}
+
+			p.cost.record(ctx, span, p.model, lastResult.Usage, MetricAttrs(ctx, p.model, providerModel))
+
+			if lastResult.Usage == nil {
 				return
 			}
 
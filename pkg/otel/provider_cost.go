@@ -0,0 +1,77 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/usage"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Option func(*options)
+
+type options struct {
+	pricing *provider.Pricing
+}
+
+// WithPricing prices the reported token usage of every request.
+func WithPricing(pricing *provider.Pricing) Option {
+	return func(o *options) {
+		o.pricing = pricing
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+type costRecorder struct {
+	pricing *provider.Pricing
+
+	costMetric metric.Float64Counter
+}
+
+func newCostRecorder(o options) costRecorder {
+	meter := otel.Meter(instrumentationName)
+
+	costMetric, _ := meter.Float64Counter("gen_ai.client.cost",
+		metric.WithDescription("Cost of GenAI requests based on the configured model pricing"),
+		metric.WithUnit("{currency}"),
+	)
+
+	return costRecorder{
+		pricing: o.pricing,
+
+		costMetric: costMetric,
+	}
+}
+
+// record prices the usage of a finished request, adds the cost to the span
+// and metrics and books it in the usage ledger under the requested model.
+func (r costRecorder) record(ctx context.Context, span trace.Span, model string, u *provider.Usage, attrs []KeyValue) {
+	cost := r.pricing.Cost(u)
+
+	usage.Default.Add(ctx, model, u, cost)
+
+	if r.pricing == nil {
+		return
+	}
+
+	if span.IsRecording() {
+		span.SetAttributes(attribute.Float64("gen_ai.usage.cost", cost))
+	}
+
+	if r.costMetric != nil {
+		r.costMetric.Add(ctx, cost, metric.WithAttributes(attrs...))
+	}
+}
@@ -23,11 +23,13 @@ type observableEmbedder struct {
 
 	embedder provider.Embedder
 
+	cost costRecorder
+
 	tokenUsageMetric        genaiconv.ClientTokenUsage
 	operationDurationMetric genaiconv.ClientOperationDuration
 }
 
-func NewEmbedder(provider, model string, p provider.Embedder) Embedder {
+func NewEmbedder(provider, model string, p provider.Embedder, opts ...Option) Embedder {
 	meter := otel.Meter(instrumentationName)
 
 	tokenUsageMetric, _ := genaiconv.NewClientTokenUsage(meter)
@@ -39,6 +41,8 @@ func NewEmbedder(provider, model string, p provider.Embedder) Embedder {
 		model:    model,
 		provider: provider,
 
+		cost: newCostRecorder(newOptions(opts)),
+
 		tokenUsageMetric:        tokenUsageMetric,
 		operationDurationMetric: operationDurationMetric,
 	}
@@ -83,6 +87,8 @@ func (p *observableEmbedder) Embed(ctx context.Context, texts []string, options
 			)...)
 		}
 
+		p.cost.record(ctx, span, p.model, result.Usage, MetricAttrs(ctx, p.model, providerModel))
+
 		if result.Usage != nil {
 			attrs := MetricAttrs(ctx, p.model, providerModel)
 
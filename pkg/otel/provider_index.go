@@ -0,0 +1,99 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+
+	"go.opentelemetry.io/otel"
+)
+
+type Index interface {
+	Observable
+	index.Provider
+	index.DocumentLister
+}
+
+type observableIndex struct {
+	name     string
+	provider string
+
+	index index.Provider
+}
+
+func NewIndex(provider, name string, p index.Provider) Index {
+	return &observableIndex{
+		index: p,
+
+		name:     name,
+		provider: provider,
+	}
+}
+
+func (p *observableIndex) otelSetup() {
+}
+
+func (p *observableIndex) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "index list "+p.name)
+	defer span.End()
+
+	result, err := p.index.List(ctx, options)
+
+	if err != nil {
+		RecordError(span, err)
+	}
+
+	return result, err
+}
+
+func (p *observableIndex) ListDocuments(ctx context.Context) ([]index.Document, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "index list documents "+p.name)
+	defer span.End()
+
+	result, err := index.ListDocuments(ctx, p.index)
+
+	if err != nil {
+		RecordError(span, err)
+	}
+
+	return result, err
+}
+
+func (p *observableIndex) Index(ctx context.Context, documents ...index.Document) ([]string, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "index upsert "+p.name)
+	defer span.End()
+
+	ids, err := p.index.Index(ctx, documents...)
+
+	if err != nil {
+		RecordError(span, err)
+	}
+
+	return ids, err
+}
+
+func (p *observableIndex) Delete(ctx context.Context, ids ...string) error {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "index delete "+p.name)
+	defer span.End()
+
+	err := p.index.Delete(ctx, ids...)
+
+	if err != nil {
+		RecordError(span, err)
+	}
+
+	return err
+}
+
+func (p *observableIndex) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "index query "+p.name)
+	defer span.End()
+
+	result, err := p.index.Query(ctx, query, options)
+
+	if err != nil {
+		RecordError(span, err)
+	}
+
+	return result, err
+}
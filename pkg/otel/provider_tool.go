@@ -3,6 +3,7 @@ package otel
 import (
 	"context"
 
+	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/tool"
 
 	"go.opentelemetry.io/otel"
@@ -21,11 +22,31 @@ type observableTool struct {
 }
 
 func NewTool(provider string, p tool.Provider) Tool {
-	return &observableTool{
+	t := &observableTool{
 		tool: p,
 
 		provider: provider,
 	}
+
+	// Keep the wrapped tool's result rendering (e.g. file parts) visible
+	if r, ok := p.(tool.Resulter); ok {
+		return &resulterTool{
+			observableTool: t,
+			resulter:       r,
+		}
+	}
+
+	return t
+}
+
+type resulterTool struct {
+	*observableTool
+
+	resulter tool.Resulter
+}
+
+func (p *resulterTool) Result(name string, value any) provider.ToolResult {
+	return p.resulter.Result(name, value)
 }
 
 func (p *observableTool) otelSetup() {
@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/scraper"
+)
+
+type Option func(*Pipeline)
+
+// WithScraper fetches URLs through the scraper instead of downloading and
+// extracting them directly.
+func WithScraper(scraper scraper.Provider) Option {
+	return func(p *Pipeline) {
+		p.scraper = scraper
+	}
+}
+
+func WithClient(client *http.Client) Option {
+	return func(p *Pipeline) {
+		p.client = client
+	}
+}
+
+func WithSegmentLength(length int) Option {
+	return func(p *Pipeline) {
+		if length > 0 {
+			p.segmentLength = &length
+		}
+	}
+}
+
+func WithSegmentOverlap(overlap int) Option {
+	return func(p *Pipeline) {
+		if overlap >= 0 {
+			p.segmentOverlap = &overlap
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// DefaultJobs tracks the ingest jobs of the process. It outlives
+// configuration reloads, so running jobs stay visible.
+var DefaultJobs = NewJobs(24 * time.Hour)
+
+type Status string
+
+const (
+	StatusQueued     Status = "queued"
+	StatusInProgress Status = "in_progress"
+	StatusCompleted  Status = "completed"
+	StatusFailed     Status = "failed"
+)
+
+// Job is the state of an asynchronous ingest.
+type Job struct {
+	ID string
+
+	Pipeline string
+	Status   Status
+
+	Document string
+	Source   string
+	Chunks   int
+
+	Error string
+
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// Jobs runs ingests in the background and keeps their state in memory;
+// finished jobs are dropped after the retention.
+type Jobs struct {
+	mu sync.Mutex
+
+	retention time.Duration
+
+	jobs map[string]*Job
+}
+
+func NewJobs(retention time.Duration) *Jobs {
+	return &Jobs{
+		retention: retention,
+
+		jobs: make(map[string]*Job),
+	}
+}
+
+// Start queues the input on the pipeline and returns the new job. ctx must
+// not be tied to the request that started it.
+func (j *Jobs) Start(ctx context.Context, name string, p *Pipeline, input Input) Job {
+	job := &Job{
+		ID: "ingest_" + randomID(),
+
+		Pipeline: name,
+		Status:   StatusQueued,
+
+		Source: input.URL,
+
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if job.Source == "" && input.File != nil {
+		job.Source = input.File.Name
+	}
+
+	j.mu.Lock()
+	j.prune()
+	j.jobs[job.ID] = job
+	snapshot := *job
+	j.mu.Unlock()
+
+	go func() {
+		j.update(job.ID, func(job *Job) {
+			job.Status = StatusInProgress
+		})
+
+		result, err := p.Ingest(ctx, input)
+
+		j.update(job.ID, func(job *Job) {
+			job.CompletedAt = new(time.Now().UTC())
+
+			if err != nil {
+				job.Status = StatusFailed
+				job.Error = err.Error()
+
+				return
+			}
+
+			job.Status = StatusCompleted
+
+			job.Document = result.Document
+			job.Chunks = result.Chunks
+		})
+	}()
+
+	return snapshot
+}
+
+func (j *Jobs) Get(id string) (*Job, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job, ok := j.jobs[id]
+
+	if !ok {
+		return nil, false
+	}
+
+	snapshot := *job
+
+	return &snapshot, true
+}
+
+func (j *Jobs) update(id string, fn func(*Job)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if job, ok := j.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+func (j *Jobs) prune() {
+	cutoff := time.Now().Add(-j.retention)
+
+	for id, job := range j.jobs {
+		if job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
+			delete(j.jobs, id)
+		}
+	}
+}
+
+func randomID() string {
+	data := make([]byte, 12)
+	rand.Read(data)
+
+	return hex.EncodeToString(data)
+}
@@ -0,0 +1,390 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/scraper"
+	"github.com/adrianliechti/wingman/pkg/segmenter"
+)
+
+const maxFileSize = 64 << 20
+
+// Pipeline ingests documents into an index: it extracts their text, splits
+// it into chunks and upserts the chunks, which the index embeds.
+type Pipeline struct {
+	index     index.Provider
+	extractor extractor.Provider
+	segmenter segmenter.Provider
+	scraper   scraper.Provider
+
+	client *http.Client
+
+	segmentLength  *int
+	segmentOverlap *int
+}
+
+// Input is a document to ingest, either a file or a URL.
+type Input struct {
+	// ID identifies the document; its chunks replace those of an earlier
+	// ingest with the same ID. Defaults to a hash of the URL or file name.
+	ID string
+
+	URL  string
+	File *provider.File
+
+	Title    string
+	Metadata map[string]string
+}
+
+type Result struct {
+	Document string
+	Source   string
+
+	Chunks int
+}
+
+func New(index index.Provider, extractor extractor.Provider, segmenter segmenter.Provider, options ...Option) (*Pipeline, error) {
+	if index == nil {
+		return nil, errors.New("pipeline: missing index")
+	}
+
+	if extractor == nil {
+		return nil, errors.New("pipeline: missing extractor")
+	}
+
+	if segmenter == nil {
+		return nil, errors.New("pipeline: missing segmenter")
+	}
+
+	p := &Pipeline{
+		index:     index,
+		extractor: extractor,
+		segmenter: segmenter,
+
+		client: provider.DefaultClient,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	return p, nil
+}
+
+// Ingest runs the document through the pipeline and replaces its chunks in
+// the index.
+func (p *Pipeline) Ingest(ctx context.Context, input Input) (*Result, error) {
+	source := input.URL
+
+	if source == "" && input.File != nil {
+		source = input.File.Name
+	}
+
+	if input.URL == "" && input.File == nil {
+		return nil, errors.New("pipeline: missing file or url")
+	}
+
+	id := input.ID
+
+	if id == "" {
+		id = documentID(input)
+	}
+
+	title := input.Title
+
+	if title == "" {
+		title = source
+	}
+
+	doc, err := p.extract(ctx, input)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, err := p.segment(ctx, doc, path.Base(source))
+
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]index.Document, 0, len(chunks))
+
+	for n, c := range chunks {
+		metadata := maps.Clone(input.Metadata)
+
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+
+		maps.Copy(metadata, c.metadata)
+
+		metadata["document"] = id
+		metadata["chunk"] = strconv.Itoa(n)
+
+		documents = append(documents, index.Document{
+			ID: fmt.Sprintf("%s-%d", id, n),
+
+			Title:   title,
+			Source:  source,
+			Content: c.text,
+
+			Metadata: metadata,
+		})
+	}
+
+	// Chunks of an earlier version are replaced, including any beyond the
+	// new chunk count
+	existing, err := p.index.List(ctx, &index.ListOptions{
+		Filters: map[string]string{"document": id},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(documents) > 0 {
+		if _, err := p.index.Index(ctx, documents...); err != nil {
+			return nil, err
+		}
+	}
+
+	var stale []string
+
+	for _, d := range existing {
+		if n, err := strconv.Atoi(d.Metadata["chunk"]); err != nil || n >= len(documents) {
+			stale = append(stale, d.ID)
+		}
+	}
+
+	if len(stale) > 0 {
+		if err := p.index.Delete(ctx, stale...); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{
+		Document: id,
+		Source:   source,
+
+		Chunks: len(documents),
+	}, nil
+}
+
+func (p *Pipeline) extract(ctx context.Context, input Input) (*extractor.Document, error) {
+	if input.File != nil {
+		return p.extractor.Extract(ctx, *input.File, &extractor.ExtractOptions{})
+	}
+
+	u, err := url.Parse(input.URL)
+
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("pipeline: invalid url %q", input.URL)
+	}
+
+	if p.scraper != nil {
+		result, err := p.scraper.Scrape(ctx, input.URL, &scraper.ScrapeOptions{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &extractor.Document{
+			Text: result.Text,
+		}, nil
+	}
+
+	file, err := p.download(ctx, u)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.extractor.Extract(ctx, *file, &extractor.ExtractOptions{})
+}
+
+func (p *Pipeline) download(ctx context.Context, u *url.URL) (*provider.File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pipeline: fetching %s failed: %s", u, resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > maxFileSize {
+		return nil, errors.New("pipeline: document too large")
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	return &provider.File{
+		Name: path.Base(u.Path),
+
+		Content:     content,
+		ContentType: contentType,
+	}, nil
+}
+
+type chunk struct {
+	text     string
+	metadata map[string]string
+}
+
+// segment splits the document into chunks. Documents with layout blocks are
+// segmented page by page, so each chunk keeps its page number and the
+// polygons of the blocks it contains for citations.
+func (p *Pipeline) segment(ctx context.Context, doc *extractor.Document, name string) ([]chunk, error) {
+	options := &segmenter.SegmentOptions{
+		FileName: name,
+
+		SegmentLength:  p.segmentLength,
+		SegmentOverlap: p.segmentOverlap,
+	}
+
+	if len(doc.Blocks) == 0 {
+		segments, err := p.segmenter.Segment(ctx, doc.Text, options)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var result []chunk
+
+		for _, s := range segments {
+			if strings.TrimSpace(s.Text) == "" {
+				continue
+			}
+
+			result = append(result, chunk{text: s.Text})
+		}
+
+		return result, nil
+	}
+
+	var pages []int
+	blocks := make(map[int][]extractor.Block)
+
+	for _, b := range doc.Blocks {
+		if _, ok := blocks[b.Page]; !ok {
+			pages = append(pages, b.Page)
+		}
+
+		blocks[b.Page] = append(blocks[b.Page], b)
+	}
+
+	var result []chunk
+
+	for _, page := range pages {
+		var texts []string
+
+		for _, b := range blocks[page] {
+			if text := strings.TrimSpace(b.Text); text != "" {
+				texts = append(texts, text)
+			}
+		}
+
+		segments, err := p.segmenter.Segment(ctx, strings.Join(texts, "\n\n"), options)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range segments {
+			if strings.TrimSpace(s.Text) == "" {
+				continue
+			}
+
+			metadata := pageMetadata(doc, page)
+
+			var polygons [][][2]float64
+
+			for _, b := range blocks[page] {
+				if text := strings.TrimSpace(b.Text); text != "" && len(b.Polygon) > 0 && strings.Contains(s.Text, text) {
+					polygons = append(polygons, b.Polygon)
+				}
+			}
+
+			if len(polygons) > 0 {
+				data, _ := json.Marshal(polygons)
+				metadata["polygons"] = string(data)
+			}
+
+			result = append(result, chunk{text: s.Text, metadata: metadata})
+		}
+	}
+
+	return result, nil
+}
+
+func pageMetadata(doc *extractor.Document, page int) map[string]string {
+	metadata := map[string]string{}
+
+	if page > 0 {
+		metadata["page"] = strconv.Itoa(page)
+	}
+
+	for _, p := range doc.Pages {
+		if p.Page != page {
+			continue
+		}
+
+		if p.Unit != "" {
+			metadata["page_unit"] = p.Unit
+		}
+
+		if p.Width > 0 && p.Height > 0 {
+			metadata["page_width"] = strconv.FormatFloat(p.Width, 'f', -1, 64)
+			metadata["page_height"] = strconv.FormatFloat(p.Height, 'f', -1, 64)
+		}
+	}
+
+	return metadata
+}
+
+func documentID(input Input) string {
+	h := sha256.New()
+
+	switch {
+	case input.URL != "":
+		h.Write([]byte(input.URL))
+	case input.File.Name != "":
+		h.Write([]byte(input.File.Name))
+	default:
+		h.Write(input.File.Content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
@@ -0,0 +1,165 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/index/memory"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/segmenter/text"
+)
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, []float32{1, 0})
+	}
+
+	return result, nil
+}
+
+type fakeExtractor struct {
+	document *extractor.Document
+}
+
+func (f *fakeExtractor) Extract(ctx context.Context, file extractor.File, options *extractor.ExtractOptions) (*extractor.Document, error) {
+	return f.document, nil
+}
+
+func newPipeline(t *testing.T, doc *extractor.Document) (*Pipeline, index.Provider) {
+	t.Helper()
+
+	idx, err := memory.New(fakeEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segmenter, _ := text.New()
+
+	p, err := New(idx, &fakeExtractor{document: doc}, segmenter)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p, idx
+}
+
+func TestIngestKeepsPagesAndPolygons(t *testing.T) {
+	doc := &extractor.Document{
+		Pages: []extractor.Page{
+			{Page: 1, Unit: "inch", Width: 8.5, Height: 11},
+			{Page: 2, Unit: "inch", Width: 8.5, Height: 11},
+		},
+
+		Blocks: []extractor.Block{
+			{Page: 1, Text: "First page.", Polygon: [][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+			{Page: 2, Text: "Second page.", Polygon: [][2]float64{{2, 2}, {3, 2}, {3, 3}, {2, 3}}},
+		},
+	}
+
+	p, idx := newPipeline(t, doc)
+
+	file := &provider.File{Name: "report.pdf", Content: []byte("%PDF"), ContentType: "application/pdf"}
+
+	result, err := p.Ingest(t.Context(), Input{File: file, Metadata: map[string]string{"team": "hr"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Chunks != 2 {
+		t.Fatalf("chunks = %d, want 2", result.Chunks)
+	}
+
+	documents, _ := idx.List(t.Context(), nil)
+
+	if len(documents) != 2 {
+		t.Fatalf("indexed %d documents, want 2", len(documents))
+	}
+
+	second := documents[1]
+
+	if second.Metadata["page"] != "2" || second.Metadata["page_unit"] != "inch" || second.Metadata["team"] != "hr" {
+		t.Errorf("metadata = %v", second.Metadata)
+	}
+
+	if second.Metadata["polygons"] != "[[[2,2],[3,2],[3,3],[2,3]]]" {
+		t.Errorf("polygons = %s", second.Metadata["polygons"])
+	}
+
+	if second.Source != "report.pdf" || second.Metadata["document"] != result.Document {
+		t.Errorf("source = %q, document = %q", second.Source, second.Metadata["document"])
+	}
+}
+
+func TestIngestReplacesEarlierChunks(t *testing.T) {
+	p, idx := newPipeline(t, &extractor.Document{
+		Blocks: []extractor.Block{
+			{Page: 1, Text: "One."},
+			{Page: 2, Text: "Two."},
+		},
+	})
+
+	input := Input{ID: "doc", File: &provider.File{Name: "a.txt", Content: []byte("x")}}
+
+	if _, err := p.Ingest(t.Context(), input); err != nil {
+		t.Fatal(err)
+	}
+
+	p.extractor = &fakeExtractor{document: &extractor.Document{Text: "Only one chunk now."}}
+
+	if _, err := p.Ingest(t.Context(), input); err != nil {
+		t.Fatal(err)
+	}
+
+	documents, _ := idx.List(t.Context(), nil)
+
+	if len(documents) != 1 || !strings.Contains(documents[0].Content, "Only one chunk") {
+		t.Fatalf("documents = %+v", documents)
+	}
+}
+
+func TestJobsReportCompletion(t *testing.T) {
+	p, _ := newPipeline(t, &extractor.Document{Text: "Hello."})
+
+	jobs := NewJobs(time.Hour)
+
+	job := jobs.Start(context.Background(), "docs", p, Input{File: &provider.File{Name: "a.txt", Content: []byte("x")}})
+
+	if job.Status != StatusQueued || job.Source != "a.txt" {
+		t.Fatalf("job = %+v", job)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		current, ok := jobs.Get(job.ID)
+
+		if !ok {
+			t.Fatal("job not found")
+		}
+
+		if current.Status == StatusCompleted {
+			if current.Chunks != 1 || current.CompletedAt == nil {
+				t.Errorf("job = %+v", current)
+			}
+
+			return
+		}
+
+		if current.Status == StatusFailed || time.Now().After(deadline) {
+			t.Fatalf("job = %+v", current)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -2,14 +2,21 @@ package opa
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/adrianliechti/wingman/pkg/policy"
 
 	"github.com/open-policy-agent/opa/v1/rego"
 )
 
+var (
+	_ policy.Provider = (*File)(nil)
+	_ policy.Reviewer = (*File)(nil)
+)
+
 type File struct {
-	query rego.PreparedEvalQuery
+	query  rego.PreparedEvalQuery
+	review rego.PreparedEvalQuery
 }
 
 func NewFile(path string) (*File, error) {
@@ -22,8 +29,18 @@ func NewFile(path string) (*File, error) {
 		return nil, err
 	}
 
+	review, err := rego.New(
+		rego.Query("data.wingman.review"),
+		rego.Load([]string{path}, nil),
+	).PrepareForEval(context.Background())
+
+	if err != nil {
+		return nil, err
+	}
+
 	p := &File{
-		query: query,
+		query:  query,
+		review: review,
 	}
 
 	return p, nil
@@ -48,3 +65,31 @@ func (p *File) Verify(ctx context.Context, resource policy.Resource, id string,
 
 	return nil
 }
+
+// Review evaluates data.wingman.review, an optional object with the fields of
+// policy.Decision.
+func (p *File) Review(ctx context.Context, request policy.Request) (*policy.Decision, error) {
+	results, err := p.review.Eval(ctx, rego.EvalInput(reviewInput(ctx, request)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(results[0].Expressions[0].Value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var decision policy.Decision
+
+	if err := json.Unmarshal(data, &decision); err != nil {
+		return nil, err
+	}
+
+	return &decision, nil
+}
@@ -0,0 +1,62 @@
+package opa
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/policy"
+)
+
+const testPolicy = `package wingman
+
+default allow := true
+
+review["effort"] := "high" if {
+	input.effort == "xhigh"
+	"interns" in input.groups
+}
+
+review["deny"] := "no file uploads to external models" if {
+	count(input.content_types) > 0
+	startswith(input.model, "gpt-")
+}
+`
+
+func TestFileReview(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.rego")
+
+	if err := os.WriteFile(path, []byte(testPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), auth.GroupsContextKey, []string{"interns"})
+
+	decision, err := p.Review(ctx, policy.Request{Model: "claude-opus-4", Effort: "xhigh", Tools: []string{}, ContentTypes: []string{}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decision == nil || decision.Effort != "high" || decision.Deny != "" {
+		t.Fatalf("decision = %+v", decision)
+	}
+
+	decision, err = p.Review(context.Background(), policy.Request{Model: "gpt-5", Tools: []string{}, ContentTypes: []string{"application/pdf"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decision == nil || decision.Deny == "" || decision.Effort != "" {
+		t.Fatalf("decision = %+v", decision)
+	}
+}
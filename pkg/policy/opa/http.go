@@ -11,10 +11,16 @@ import (
 	"github.com/adrianliechti/wingman/pkg/policy"
 )
 
+var (
+	_ policy.Provider = (*Client)(nil)
+	_ policy.Reviewer = (*Client)(nil)
+)
+
 type Client struct {
 	client *http.Client
 
-	url string
+	url       string
+	reviewURL string
 }
 
 type Option func(*Client)
@@ -33,7 +39,8 @@ func NewClient(url string, opts ...Option) (*Client, error) {
 	c := &Client{
 		client: http.DefaultClient,
 
-		url: strings.TrimRight(url, "/") + "/v1/data/wingman/allow",
+		url:       strings.TrimRight(url, "/") + "/v1/data/wingman/allow",
+		reviewURL: strings.TrimRight(url, "/") + "/v1/data/wingman/review",
 	}
 
 	for _, opt := range opts {
@@ -86,3 +93,45 @@ func (c *Client) Verify(ctx context.Context, resource policy.Resource, id string
 
 	return nil
 }
+
+// Review queries data.wingman.review, an optional object with the fields of
+// policy.Decision.
+func (c *Client) Review(ctx context.Context, request policy.Request) (*policy.Decision, error) {
+	body, err := json.Marshal(map[string]any{
+		"input": reviewInput(ctx, request),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.reviewURL, bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unable to query policy: " + resp.Status)
+	}
+
+	var result struct {
+		Result *policy.Decision `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.Result, nil
+}
@@ -60,3 +60,49 @@ func TestClientVerify(t *testing.T) {
 		t.Fatalf("expected access denied, got %v", err)
 	}
 }
+
+func TestClientReview(t *testing.T) {
+	var gotPath string
+	var gotInput map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+
+		var body struct {
+			Input map[string]any `json:"input"`
+		}
+
+		json.NewDecoder(r.Body).Decode(&body)
+		gotInput = body.Input
+
+		json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"effort": "high"}})
+	}))
+
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), auth.GroupsContextKey, []string{"interns"})
+
+	decision, err := client.Review(ctx, policy.Request{Model: "gpt-5", Effort: "xhigh", Tools: []string{}, ContentTypes: []string{}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/v1/data/wingman/review" {
+		t.Fatalf("unexpected path: %s", gotPath)
+	}
+
+	if gotInput["action"] != "complete" || gotInput["effort"] != "xhigh" || gotInput["groups"].([]any)[0] != "interns" {
+		t.Fatalf("unexpected input: %v", gotInput)
+	}
+
+	if decision == nil || decision.Effort != "high" {
+		t.Fatalf("decision = %+v", decision)
+	}
+}
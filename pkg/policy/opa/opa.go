@@ -26,3 +26,16 @@ func evalInput(ctx context.Context, resource policy.Resource, id string, action
 		"groups": groups,
 	}
 }
+
+func reviewInput(ctx context.Context, request policy.Request) map[string]any {
+	input := evalInput(ctx, policy.ResourceModel, request.Model, policy.ActionComplete)
+
+	input["model"] = request.Model
+	input["tools"] = request.Tools
+	input["max_tokens"] = request.MaxTokens
+	input["effort"] = request.Effort
+	input["input_tokens"] = request.InputTokens
+	input["content_types"] = request.ContentTypes
+
+	return input
+}
@@ -12,12 +12,30 @@ type Resource string
 const (
 	ResourceModel Resource = "model"
 	ResourceMCP   Resource = "mcp"
+	ResourceIndex Resource = "index"
+
+	// ResourceTool guards single tools of an MCP gateway, by their
+	// namespaced name.
+	ResourceTool Resource = "tool"
+
+	// ResourceUsage guards the usage report of all users; without access,
+	// callers only see their own usage.
+	ResourceUsage Resource = "usage"
 )
 
 type Action string
 
 const (
 	ActionAccess Action = "access"
+	ActionCall   Action = "call"
+
+	// ActionWrite guards changes to a resource, e.g. adding documents to
+	// or removing them from an index.
+	ActionWrite Action = "write"
+
+	// ActionComplete reviews the content of a completion request, see
+	// Reviewer.
+	ActionComplete Action = "complete"
 )
 
 type Provider interface {
@@ -0,0 +1,234 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tokens"
+)
+
+// Reviewer is implemented by policies that decide on the content of a
+// completion request, not only on access to the model.
+type Reviewer interface {
+	Review(ctx context.Context, request Request) (*Decision, error)
+}
+
+// Request describes a completion request. The caller is taken from the
+// context, as for Verify.
+type Request struct {
+	Model string `json:"model"`
+
+	Tools []string `json:"tools"`
+
+	MaxTokens *int   `json:"max_tokens,omitempty"`
+	Effort    string `json:"effort,omitempty"`
+
+	// InputTokens is an estimate of the prompt size, see tokens.Estimate
+	InputTokens int `json:"input_tokens"`
+
+	// ContentTypes lists the distinct types of the attached files
+	ContentTypes []string `json:"content_types"`
+}
+
+// Decision denies a request or rewrites its options. Empty fields keep the
+// request as it is.
+type Decision struct {
+	// Deny is the reason the request is denied
+	Deny string `json:"deny,omitempty"`
+
+	// MaxTokens caps the requested output tokens
+	MaxTokens *int `json:"max_tokens,omitempty"`
+
+	// Effort replaces the reasoning effort; "none" disables reasoning
+	Effort string `json:"effort,omitempty"`
+
+	// Tools lists the tools to keep, by name
+	Tools []string `json:"tools,omitempty"`
+}
+
+// Review lets the policy review a completion request if it is a Reviewer
+// and applies its decision to the options. It returns ErrAccessDenied if the
+// request is denied.
+func Review(ctx context.Context, p Provider, model string, messages []provider.Message, options *provider.CompleteOptions) error {
+	r, ok := p.(Reviewer)
+
+	if !ok {
+		return nil
+	}
+
+	if options == nil {
+		options = new(provider.CompleteOptions)
+	}
+
+	request := Request{
+		Model: model,
+
+		Tools: []string{},
+
+		MaxTokens: options.MaxTokens,
+
+		InputTokens: tokens.Estimate(model, tokens.Input{
+			Messages: messages,
+			Tools:    options.Tools,
+		}),
+
+		ContentTypes: contentTypes(messages),
+	}
+
+	for _, t := range options.Tools {
+		request.Tools = append(request.Tools, t.Name)
+	}
+
+	if o := options.ReasoningOptions; o != nil {
+		request.Effort = string(o.Effort)
+
+		if o.Type == provider.ReasoningTypeDisabled {
+			request.Effort = "none"
+		}
+	}
+
+	decision, err := r.Review(ctx, request)
+
+	if err != nil {
+		return &ReviewError{Err: err}
+	}
+
+	if decision == nil {
+		return nil
+	}
+
+	if decision.Deny != "" {
+		return &DeniedError{Reason: decision.Deny}
+	}
+
+	if limit := decision.MaxTokens; limit != nil && (options.MaxTokens == nil || *options.MaxTokens > *limit) {
+		options.MaxTokens = new(*limit)
+	}
+
+	if decision.Effort != "" {
+		if options.ReasoningOptions == nil {
+			options.ReasoningOptions = &provider.ReasoningOptions{}
+		}
+
+		if decision.Effort == "none" {
+			options.ReasoningOptions.Type = provider.ReasoningTypeDisabled
+			options.ReasoningOptions.Effort = ""
+		} else {
+			options.ReasoningOptions.Type = provider.ReasoningTypeAdaptive
+			options.ReasoningOptions.Effort = provider.Effort(decision.Effort)
+		}
+	}
+
+	if decision.Tools != nil {
+		options.Tools = slices.DeleteFunc(options.Tools, func(t provider.Tool) bool {
+			return !slices.Contains(decision.Tools, t.Name)
+		})
+
+		keepToolChoice(options)
+	}
+
+	return nil
+}
+
+// keepToolChoice drops removed tools from the tool choice. A choice forced
+// onto tools that are all gone falls back to auto, and without any tools
+// left there is nothing to choose.
+func keepToolChoice(options *provider.CompleteOptions) {
+	o := options.ToolOptions
+
+	if o == nil {
+		return
+	}
+
+	if len(options.Tools) == 0 {
+		options.ToolOptions = nil
+		return
+	}
+
+	forced := len(o.Allowed) > 0
+
+	o.Allowed = slices.DeleteFunc(o.Allowed, func(name string) bool {
+		return !slices.ContainsFunc(options.Tools, func(t provider.Tool) bool {
+			return t.Name == name
+		})
+	})
+
+	if forced && len(o.Allowed) == 0 {
+		o.Allowed = nil
+
+		if o.Choice == provider.ToolChoiceAny {
+			o.Choice = provider.ToolChoiceAuto
+		}
+	}
+}
+
+// DeniedError is returned for requests a Reviewer denied. It matches
+// ErrAccessDenied.
+type DeniedError struct {
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	return ErrAccessDenied.Error() + ": " + e.Reason
+}
+
+func (e *DeniedError) Is(target error) bool {
+	return target == ErrAccessDenied
+}
+
+// ReviewError is returned if the Reviewer failed to decide on a request.
+type ReviewError struct {
+	Err error
+}
+
+func (e *ReviewError) Error() string {
+	return "policy review failed: " + e.Err.Error()
+}
+
+func (e *ReviewError) Unwrap() error {
+	return e.Err
+}
+
+// CodeFromError returns the HTTP status for an error of Review: 403 if the
+// request is denied, 502 if the policy failed to decide, and the fallback
+// for any other error.
+func CodeFromError(err error, fallback int) int {
+	if errors.Is(err, ErrAccessDenied) {
+		return http.StatusForbidden
+	}
+
+	if _, ok := errors.AsType[*ReviewError](err); ok {
+		return http.StatusBadGateway
+	}
+
+	return fallback
+}
+
+func contentTypes(messages []provider.Message) []string {
+	result := []string{}
+
+	add := func(f *provider.File) {
+		if f != nil && f.ContentType != "" && !slices.Contains(result, f.ContentType) {
+			result = append(result, f.ContentType)
+		}
+	}
+
+	for _, m := range messages {
+		for _, c := range m.Content {
+			add(c.File)
+
+			if c.ToolResult != nil {
+				for _, part := range c.ToolResult.Parts {
+					add(part.File)
+				}
+			}
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
+}
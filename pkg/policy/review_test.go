@@ -0,0 +1,140 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type fakeReviewer struct {
+	request  Request
+	decision *Decision
+	err      error
+}
+
+func (r *fakeReviewer) Verify(ctx context.Context, resource Resource, id string, action Action) error {
+	return nil
+}
+
+func (r *fakeReviewer) Review(ctx context.Context, request Request) (*Decision, error) {
+	r.request = request
+	return r.decision, r.err
+}
+
+func TestReviewDescribesRequest(t *testing.T) {
+	r := &fakeReviewer{}
+
+	messages := []provider.Message{
+		provider.UserMessage("Summarize this."),
+		{Role: provider.MessageRoleUser, Content: []provider.Content{{File: &provider.File{ContentType: "application/pdf"}}}},
+	}
+
+	options := &provider.CompleteOptions{
+		Tools:     []provider.Tool{{Name: "web_search"}},
+		MaxTokens: new(4096),
+
+		ReasoningOptions: &provider.ReasoningOptions{Effort: provider.EffortXHigh},
+	}
+
+	if err := Review(t.Context(), r, "gpt-5", messages, options); err != nil {
+		t.Fatal(err)
+	}
+
+	got := r.request
+
+	if got.Model != "gpt-5" || got.Effort != "xhigh" || *got.MaxTokens != 4096 || got.InputTokens == 0 {
+		t.Errorf("request = %+v", got)
+	}
+
+	if len(got.Tools) != 1 || got.Tools[0] != "web_search" {
+		t.Errorf("tools = %v", got.Tools)
+	}
+
+	if len(got.ContentTypes) != 1 || got.ContentTypes[0] != "application/pdf" {
+		t.Errorf("content types = %v", got.ContentTypes)
+	}
+}
+
+func TestReviewAppliesDecision(t *testing.T) {
+	r := &fakeReviewer{decision: &Decision{
+		MaxTokens: new(1000),
+		Effort:    "high",
+		Tools:     []string{"retrieve"},
+	}}
+
+	options := &provider.CompleteOptions{
+		Tools:     []provider.Tool{{Name: "web_search"}, {Name: "retrieve"}},
+		MaxTokens: new(4096),
+
+		ReasoningOptions: &provider.ReasoningOptions{Effort: provider.EffortXHigh},
+	}
+
+	if err := Review(t.Context(), r, "gpt-5", nil, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if *options.MaxTokens != 1000 || options.ReasoningOptions.Effort != provider.EffortHigh {
+		t.Errorf("options = %+v", options)
+	}
+
+	if len(options.Tools) != 1 || options.Tools[0].Name != "retrieve" {
+		t.Errorf("tools = %+v", options.Tools)
+	}
+}
+
+func TestReviewDenies(t *testing.T) {
+	r := &fakeReviewer{decision: &Decision{Deny: "no file uploads to external models"}}
+
+	err := Review(t.Context(), r, "gpt-5", nil, &provider.CompleteOptions{})
+
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestReviewDropsRemovedToolChoice(t *testing.T) {
+	r := &fakeReviewer{decision: &Decision{Tools: []string{"retrieve"}}}
+
+	options := &provider.CompleteOptions{
+		Tools: []provider.Tool{{Name: "web_search"}, {Name: "retrieve"}},
+
+		ToolOptions: &provider.ToolOptions{
+			Choice:  provider.ToolChoiceAny,
+			Allowed: []string{"web_search"},
+		},
+	}
+
+	if err := Review(t.Context(), r, "gpt-5", nil, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if o := options.ToolOptions; o.Choice != provider.ToolChoiceAuto || len(o.Allowed) != 0 {
+		t.Errorf("tool options = %+v", o)
+	}
+
+	r.decision.Tools = []string{}
+
+	if err := Review(t.Context(), r, "gpt-5", nil, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if options.ToolOptions != nil {
+		t.Errorf("tool options = %+v", options.ToolOptions)
+	}
+}
+
+func TestReviewErrorCodes(t *testing.T) {
+	denied := Review(t.Context(), &fakeReviewer{decision: &Decision{Deny: "no"}}, "gpt-5", nil, nil)
+	failed := Review(t.Context(), &fakeReviewer{err: errors.New("policy unreachable")}, "gpt-5", nil, nil)
+
+	if code := CodeFromError(denied, http.StatusInternalServerError); code != http.StatusForbidden {
+		t.Errorf("denied: code = %d", code)
+	}
+
+	if code := CodeFromError(failed, http.StatusInternalServerError); code != http.StatusBadGateway {
+		t.Errorf("failed: code = %d", code)
+	}
+}
@@ -22,6 +22,8 @@ type CompletionAccumulator struct {
 	toolCalls      []ToolCall
 	lastToolCallID string
 
+	logprobs []Logprob
+
 	usage *Usage
 
 	contentOrder []accumulatedContentRef
@@ -55,6 +57,12 @@ type accumulatedContentRef struct {
 }
 
 func (a *CompletionAccumulator) Add(c Completion) {
+	// of a stream with several choices only the first answer is kept,
+	// along with the usage of all of them
+	if c.Choice > 0 {
+		c = Completion{Usage: c.Usage}
+	}
+
 	if c.ID != "" {
 		a.id = c.ID
 	}
@@ -115,6 +123,8 @@ func (a *CompletionAccumulator) Add(c Completion) {
 		}
 	}
 
+	a.logprobs = append(a.logprobs, c.Logprobs...)
+
 	if c.Usage != nil {
 		if a.usage == nil {
 			a.usage = &Usage{}
@@ -307,6 +317,8 @@ func (a *CompletionAccumulator) Result() *Completion {
 			Content: content,
 		},
 
+		Logprobs: a.logprobs,
+
 		Usage: a.usage,
 	}
 }
@@ -146,6 +146,20 @@ func TestCompletionAccumulatorPreservesStopReason(t *testing.T) {
 	}
 }
 
+func TestCompletionAccumulatorConcatsLogprobs(t *testing.T) {
+	acc := CompletionAccumulator{}
+
+	acc.Add(Completion{Logprobs: []Logprob{{Token: "Hel", Logprob: -0.1}}})
+	acc.Add(Completion{Usage: &Usage{OutputTokens: 2}})
+	acc.Add(Completion{Logprobs: []Logprob{{Token: "lo", Logprob: -0.2}}})
+
+	got := acc.Result().Logprobs
+
+	if len(got) != 2 || got[0].Token != "Hel" || got[1].Token != "lo" {
+		t.Fatalf("logprobs: got %+v", got)
+	}
+}
+
 // When a streaming response carries two reasoning items, each must end up
 // as its own Reasoning entry. Collapsing them pairs item 1's ID with
 // item 2's encrypted_content, which OpenAI rejects on the next turn with
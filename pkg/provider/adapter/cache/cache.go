@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/lru"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+// Store holds the recorded completion streams by request key. Entries
+// expire after their TTL. A store may outlive its Completer, e.g. to keep
+// cached answers across a config reload.
+type Store struct {
+	entries *lru.Cache[string, *entry]
+}
+
+type entry struct {
+	key string
+
+	// scope groups entries that only differ in their last user turn;
+	// semantic lookups compare embeddings within one scope.
+	scope     string
+	embedding []float32
+
+	chunks  []provider.Completion
+	expires time.Time
+}
+
+// NewStore creates a store of at most capacity completions.
+func NewStore(capacity int) *Store {
+	return &Store{
+		entries: lru.New[string, *entry](capacity),
+	}
+}
+
+func (s *Store) get(key string, now time.Time) (*entry, bool) {
+	e, ok := s.entries.Get(key)
+
+	if !ok {
+		return nil, false
+	}
+
+	if !now.Before(e.expires) {
+		s.entries.Remove(key)
+		return nil, false
+	}
+
+	return e, true
+}
+
+// nearest returns the live entry of scope whose embedding is most similar
+// to embedding, if it reaches threshold.
+func (s *Store) nearest(scope string, embedding []float32, threshold float32, now time.Time) (*entry, bool) {
+	s.entries.RemoveFunc(func(_ string, e *entry) bool {
+		return !now.Before(e.expires)
+	})
+
+	var best *entry
+	var bestScore float32
+
+	for _, e := range s.entries.Values() {
+		if e.scope != scope || e.embedding == nil {
+			continue
+		}
+
+		if score := provider.CosineSimilarity(embedding, e.embedding); score >= threshold && score > bestScore {
+			best, bestScore = e, score
+		}
+	}
+
+	if best == nil {
+		return nil, false
+	}
+
+	// mark the match as recently used
+	s.entries.Get(best.key)
+
+	return best, true
+}
+
+func (s *Store) put(e *entry) {
+	s.entries.Add(e.key, e)
+}
@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"iter"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ provider.Completer = (*Completer)(nil)
+
+// Completer serves repeated requests from recorded completion streams. A
+// request matches exactly on a hash of its messages and options, or, with
+// an embedder configured, semantically on its last user turn. Only requests
+// with an explicit zero temperature and no tools are cached; the provider
+// default temperature samples, so those answers are not meant to repeat.
+type Completer struct {
+	completer provider.Completer
+
+	cache *Store
+	ttl   time.Duration
+
+	embedder  provider.Embedder
+	threshold float32
+
+	now func() time.Time
+}
+
+func FromCompleter(completer provider.Completer, options ...Option) *Completer {
+	c := &Completer{
+		completer: completer,
+
+		cache: NewStore(1000),
+		ttl:   time.Hour,
+
+		now: time.Now,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+type bypassKey struct{}
+
+// Bypass marks requests made with the returned context as uncached, e.g.
+// the calls fanning out n alternative answers, which would otherwise all
+// be served the first one.
+func Bypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+func bypassed(ctx context.Context) bool {
+	v, _ := ctx.Value(bypassKey{}).(bool)
+	return v
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	if bypassed(ctx) || !cacheable(messages, options) {
+		return c.completer.Complete(ctx, messages, options)
+	}
+
+	key, scope, query, err := requestKeys(messages, options)
+
+	// a request that cannot be keyed reliably is passed through uncached
+	if err != nil {
+		return c.completer.Complete(ctx, messages, options)
+	}
+
+	return func(yield func(*provider.Completion, error) bool) {
+		if cached, ok := c.cache.get(key, c.now()); ok {
+			replay(cached.chunks, yield)
+			return
+		}
+
+		var embedding []float32
+
+		if c.embedder != nil && query != "" {
+			// Embedding failures only cost the cache hit, never the request.
+			if result, err := c.embedder.Embed(ctx, []string{query}, nil); err == nil && len(result.Embeddings) > 0 {
+				embedding = result.Embeddings[0]
+			}
+
+			if embedding != nil {
+				if cached, ok := c.cache.nearest(scope, embedding, c.threshold, c.now()); ok {
+					replay(cached.chunks, yield)
+					return
+				}
+			}
+		}
+
+		var chunks []provider.Completion
+		var status provider.CompletionStatus
+
+		for completion, err := range c.completer.Complete(ctx, messages, options) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if completion.Status != "" {
+				status = completion.Status
+			}
+
+			chunks = append(chunks, *completion)
+
+			if !yield(completion, nil) {
+				return
+			}
+		}
+
+		if status != provider.CompletionStatusCompleted {
+			return
+		}
+
+		c.cache.put(&entry{
+			key: key,
+
+			scope:     scope,
+			embedding: embedding,
+
+			chunks:  chunks,
+			expires: c.now().Add(c.ttl),
+		})
+	}
+}
+
+// replay yields the recorded chunks in their original order and shape.
+func replay(chunks []provider.Completion, yield func(*provider.Completion, error) bool) {
+	for _, chunk := range chunks {
+		if !yield(&chunk, nil) {
+			return
+		}
+	}
+}
+
+func cacheable(messages []provider.Message, options *provider.CompleteOptions) bool {
+	if len(messages) == 0 || options == nil {
+		return false
+	}
+
+	if len(options.Tools) > 0 {
+		return false
+	}
+
+	return options.Temperature != nil && *options.Temperature == 0
+}
+
+// requestKeys returns the exact key of the request, the scope key covering
+// everything but the last user turn, and that turn's text.
+func requestKeys(messages []provider.Message, options *provider.CompleteOptions) (key, scope, query string, err error) {
+	last := len(messages) - 1
+
+	if messages[last].Role == provider.MessageRoleUser {
+		query = messages[last].Text()
+	}
+
+	if key, err = hash(messages, options); err != nil {
+		return "", "", "", err
+	}
+
+	if scope, err = hash(messages[:last], options); err != nil {
+		return "", "", "", err
+	}
+
+	return key, scope, query, nil
+}
+
+// hash canonicalizes the request as JSON. Struct fields encode in
+// declaration order and map keys sorted, so equal requests hash equally.
+func hash(messages []provider.Message, options *provider.CompleteOptions) (string, error) {
+	data, err := json.Marshal(struct {
+		Messages []provider.Message
+		Options  *provider.CompleteOptions
+	}{messages, options})
+
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
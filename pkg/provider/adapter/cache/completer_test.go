@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type countingCompleter struct {
+	calls int
+}
+
+func (c *countingCompleter) Complete(_ context.Context, _ []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		c.calls++
+
+		for _, chunk := range []provider.Completion{
+			{Message: &provider.Message{Role: provider.MessageRoleAssistant, Content: []provider.Content{provider.TextContent("hel")}}},
+			{Message: &provider.Message{Role: provider.MessageRoleAssistant, Content: []provider.Content{provider.TextContent("lo")}}},
+			{Status: provider.CompletionStatusCompleted},
+		} {
+			if !yield(&chunk, nil) {
+				return
+			}
+		}
+	}
+}
+
+// prefixEmbedder maps texts sharing their first word onto the same vector.
+type prefixEmbedder struct{}
+
+func (prefixEmbedder) Embed(_ context.Context, texts []string, _ *provider.EmbedOptions) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for _, t := range texts {
+		v := []float32{0, 1}
+
+		if len(t) > 0 && t[0] == 'w' {
+			v = []float32{1, 0}
+		}
+
+		result.Embeddings = append(result.Embeddings, v)
+	}
+
+	return result, nil
+}
+
+// deterministic asks for greedy sampling, the only requests that are cached.
+var deterministic = &provider.CompleteOptions{Temperature: new(float32(0))}
+
+func run(t *testing.T, c provider.Completer, options *provider.CompleteOptions, messages ...provider.Message) (string, int) {
+	t.Helper()
+
+	var acc provider.CompletionAccumulator
+	var chunks int
+
+	for completion, err := range c.Complete(context.Background(), messages, options) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		acc.Add(*completion)
+		chunks++
+	}
+
+	return acc.Result().Message.Text(), chunks
+}
+
+func TestExactMatch(t *testing.T) {
+	inner := &countingCompleter{}
+	c := FromCompleter(inner)
+
+	run(t, c, deterministic, provider.UserMessage("hi"))
+	text, chunks := run(t, c, deterministic, provider.UserMessage("hi"))
+
+	if inner.calls != 1 {
+		t.Fatalf("expected cached replay, got %d upstream calls", inner.calls)
+	}
+
+	if text != "hello" || chunks != 3 {
+		t.Fatalf("expected replayed stream, got %q in %d chunks", text, chunks)
+	}
+
+	run(t, c, deterministic, provider.UserMessage("bye"))
+
+	if inner.calls != 2 {
+		t.Fatalf("expected miss for different prompt, got %d upstream calls", inner.calls)
+	}
+}
+
+func TestSkipsToolsAndTemperature(t *testing.T) {
+	inner := &countingCompleter{}
+	c := FromCompleter(inner)
+
+	for _, options := range []*provider.CompleteOptions{
+		nil,
+		{},
+		{Temperature: new(float32(0.7))},
+		{Tools: []provider.Tool{{Name: "lookup"}}, Temperature: new(float32(0))},
+	} {
+		run(t, c, options, provider.UserMessage("hi"))
+		run(t, c, options, provider.UserMessage("hi"))
+	}
+
+	if inner.calls != 8 {
+		t.Fatalf("expected no caching, got %d upstream calls", inner.calls)
+	}
+
+	run(t, c, &provider.CompleteOptions{Temperature: new(float32(0))}, provider.UserMessage("hi"))
+	run(t, c, &provider.CompleteOptions{Temperature: new(float32(0))}, provider.UserMessage("hi"))
+
+	if inner.calls != 9 {
+		t.Fatalf("expected zero temperature to be cached, got %d upstream calls", inner.calls)
+	}
+}
+
+func TestBypass(t *testing.T) {
+	inner := &countingCompleter{}
+	c := FromCompleter(inner)
+
+	run(t, c, deterministic, provider.UserMessage("hi"))
+
+	ctx := Bypass(context.Background())
+
+	for range 2 {
+		for _, err := range c.Complete(ctx, []provider.Message{provider.UserMessage("hi")}, deterministic) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if inner.calls != 3 {
+		t.Fatalf("expected bypassed requests to reach upstream, got %d upstream calls", inner.calls)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	inner := &countingCompleter{}
+	c := FromCompleter(inner, WithTTL(time.Minute))
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	run(t, c, deterministic, provider.UserMessage("hi"))
+
+	now = now.Add(2 * time.Minute)
+
+	run(t, c, deterministic, provider.UserMessage("hi"))
+
+	if inner.calls != 2 {
+		t.Fatalf("expected expired entry to miss, got %d upstream calls", inner.calls)
+	}
+}
+
+func TestSemanticMatch(t *testing.T) {
+	inner := &countingCompleter{}
+	c := FromCompleter(inner, WithEmbedder(prefixEmbedder{}, 0.9))
+
+	system := provider.SystemMessage("be brief")
+
+	run(t, c, deterministic, system, provider.UserMessage("what is go"))
+	run(t, c, deterministic, system, provider.UserMessage("what's go?"))
+
+	if inner.calls != 1 {
+		t.Fatalf("expected semantic hit, got %d upstream calls", inner.calls)
+	}
+
+	run(t, c, deterministic, system, provider.UserMessage("how is go"))
+	run(t, c, deterministic, provider.SystemMessage("be verbose"), provider.UserMessage("what is go"))
+
+	if inner.calls != 3 {
+		t.Fatalf("expected dissimilar turn and different context to miss, got %d upstream calls", inner.calls)
+	}
+}
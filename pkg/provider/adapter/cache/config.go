@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Option func(*Completer)
+
+// WithTTL sets how long a cached completion is served. Defaults to 1h.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Completer) {
+		c.ttl = ttl
+	}
+}
+
+// WithCapacity caps the number of cached completions. Defaults to 1000.
+func WithCapacity(capacity int) Option {
+	return func(c *Completer) {
+		c.cache = NewStore(capacity)
+	}
+}
+
+// WithStore records completions in the given store instead of a new one.
+func WithStore(store *Store) Option {
+	return func(c *Completer) {
+		c.cache = store
+	}
+}
+
+// WithEmbedder enables semantic matching: a request whose last user turn
+// embeds within threshold (cosine similarity) of a cached one, with an
+// otherwise identical conversation and options, is served from the cache.
+func WithEmbedder(embedder provider.Embedder, threshold float32) Option {
+	return func(c *Completer) {
+		c.embedder = embedder
+		c.threshold = threshold
+	}
+}
@@ -0,0 +1,391 @@
+package guardrail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/guard"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ provider.Completer = (*Completer)(nil)
+
+type Action string
+
+const (
+	// ActionBlock refuses the request (or withholds the answer) once a guard
+	// flags it.
+	ActionBlock Action = "block"
+
+	// ActionRedact replaces flagged text and lets the conversation continue.
+	ActionRedact Action = "redact"
+
+	// ActionAnnotate passes everything through and only reports the flagged
+	// categories in the completion's StopDetails.
+	ActionAnnotate Action = "annotate"
+)
+
+const redacted = "[redacted]"
+
+// Completer runs a guard on the latest input turn and/or the generated
+// output of the wrapped completer. Blocking or redacting output requires
+// buffering the full stream before anything reaches the client; annotating
+// keeps the stream live and reports findings on the final chunk.
+type Completer struct {
+	completer provider.Completer
+
+	guard  guard.Provider
+	action Action
+
+	input  bool
+	output bool
+}
+
+func FromCompleter(completer provider.Completer, guard guard.Provider, options ...Option) (*Completer, error) {
+	if guard == nil {
+		return nil, errors.New("guardrail requires a guard")
+	}
+
+	c := &Completer{
+		completer: completer,
+
+		guard:  guard,
+		action: ActionBlock,
+
+		input: true,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	switch c.action {
+	case ActionBlock, ActionRedact, ActionAnnotate:
+	default:
+		return nil, errors.New("invalid guardrail action: " + string(c.action))
+	}
+
+	return c, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		var details *provider.StopDetails
+
+		if c.input {
+			result, err := c.guard.Check(ctx, inputText(messages), nil)
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if result.Flagged {
+				details = stopDetails(result, "input")
+
+				switch c.action {
+				case ActionBlock:
+					yield(refusal(details), nil)
+					return
+
+				case ActionRedact:
+					messages = redactInput(messages)
+				}
+			}
+		}
+
+		if !c.output || c.action == ActionAnnotate {
+			c.stream(ctx, messages, options, details, yield)
+			return
+		}
+
+		c.buffer(ctx, messages, options, details, yield)
+	}
+}
+
+// stream forwards chunks as they arrive. Output findings can only be
+// annotated, so they are attached to a trailing chunk once the stream ends.
+func (c *Completer) stream(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions, details *provider.StopDetails, yield func(*provider.Completion, error) bool) {
+	var acc provider.CompletionAccumulator
+
+	for completion, err := range c.completer.Complete(ctx, messages, options) {
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		if c.output {
+			acc.Add(*completion)
+		}
+
+		if !yield(completion, nil) {
+			return
+		}
+	}
+
+	if c.output {
+		result, err := c.guard.Check(ctx, outputText(acc.Result()), nil)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		if result.Flagged {
+			details = stopDetails(result, "output")
+		}
+	}
+
+	if details != nil {
+		yield(&provider.Completion{StopDetails: details}, nil)
+	}
+}
+
+// buffer holds back the whole answer until the guard has cleared it, then
+// replays the original chunks so the stream keeps its shape.
+func (c *Completer) buffer(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions, details *provider.StopDetails, yield func(*provider.Completion, error) bool) {
+	var acc provider.CompletionAccumulator
+	var chunks []*provider.Completion
+
+	for completion, err := range c.completer.Complete(ctx, messages, options) {
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		acc.Add(*completion)
+		chunks = append(chunks, completion)
+	}
+
+	completion := acc.Result()
+
+	result, err := c.guard.Check(ctx, outputText(completion), nil)
+
+	if err != nil {
+		yield(nil, err)
+		return
+	}
+
+	if result.Flagged {
+		details = stopDetails(result, "output")
+
+		if c.action == ActionBlock {
+			blocked := refusal(details)
+			blocked.ID = completion.ID
+			blocked.Model = completion.Model
+			blocked.Usage = completion.Usage
+
+			yield(blocked, nil)
+			return
+		}
+
+		completion.Message = redactOutput(completion.Message)
+		completion.StopDetails = details
+
+		yield(completion, nil)
+		return
+	}
+
+	for _, chunk := range chunks {
+		if !yield(chunk, nil) {
+			return
+		}
+	}
+
+	if details != nil {
+		yield(&provider.Completion{StopDetails: details}, nil)
+	}
+}
+
+// inputText collects the text of the trailing turn: every message after the
+// last assistant reply, which is what the client just sent.
+func inputText(messages []provider.Message) string {
+	start := 0
+
+	for i, m := range messages {
+		if m.Role == provider.MessageRoleAssistant {
+			start = i + 1
+		}
+	}
+
+	var parts []string
+
+	for _, m := range messages[start:] {
+		if m.Role == provider.MessageRoleSystem {
+			continue
+		}
+
+		parts = append(parts, contentText(m.Content)...)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+func outputText(completion *provider.Completion) string {
+	if completion == nil || completion.Message == nil {
+		return ""
+	}
+
+	return strings.Join(contentText(completion.Message.Content), "\n\n")
+}
+
+func contentText(contents []provider.Content) []string {
+	var parts []string
+
+	for _, c := range contents {
+		if c.Text != "" {
+			parts = append(parts, c.Text)
+		}
+
+		if c.ToolCall != nil && c.ToolCall.Arguments != "" {
+			parts = append(parts, c.ToolCall.Arguments)
+		}
+
+		if c.ToolResult != nil {
+			for _, p := range c.ToolResult.Parts {
+				if p.Text != "" {
+					parts = append(parts, p.Text)
+				}
+			}
+		}
+	}
+
+	return parts
+}
+
+func redactInput(messages []provider.Message) []provider.Message {
+	result := make([]provider.Message, len(messages))
+	copy(result, messages)
+
+	for i := len(result) - 1; i >= 0; i-- {
+		if result[i].Role == provider.MessageRoleAssistant {
+			break
+		}
+
+		if result[i].Role == provider.MessageRoleSystem {
+			continue
+		}
+
+		result[i].Content = redactContents(result[i].Content)
+	}
+
+	return result
+}
+
+func redactOutput(message *provider.Message) *provider.Message {
+	if message == nil {
+		return nil
+	}
+
+	return &provider.Message{
+		Role:    message.Role,
+		Content: redactContents(message.Content),
+	}
+}
+
+func redactContents(contents []provider.Content) []provider.Content {
+	result := make([]provider.Content, 0, len(contents))
+
+	for _, c := range contents {
+		if c.Text != "" {
+			c.Text = redacted
+		}
+
+		if c.ToolCall != nil && c.ToolCall.Arguments != "" {
+			tc := *c.ToolCall
+			tc.Arguments = redactArguments(tc.Kind, tc.Arguments)
+			c.ToolCall = &tc
+		}
+
+		if c.ToolResult != nil {
+			tr := *c.ToolResult
+			tr.Parts = []provider.Part{{Text: redacted}}
+			c.ToolResult = &tr
+		}
+
+		result = append(result, c)
+	}
+
+	return result
+}
+
+// redactArguments replaces every string in the JSON arguments of a tool call,
+// so the call keeps its shape without the flagged values. Freeform input of
+// custom tools is replaced as a whole, invalid JSON is dropped.
+func redactArguments(kind provider.ToolKind, arguments string) string {
+	if kind == provider.ToolKindCustom {
+		return redacted
+	}
+
+	var value any
+
+	if err := json.Unmarshal([]byte(arguments), &value); err != nil {
+		return "{}"
+	}
+
+	data, err := json.Marshal(redactValue(value))
+
+	if err != nil {
+		return "{}"
+	}
+
+	return string(data)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return redacted
+
+	case []any:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+
+	case map[string]any:
+		for key := range v {
+			v[key] = redactValue(v[key])
+		}
+	}
+
+	return value
+}
+
+func stopDetails(result *guard.Result, stage string) *provider.StopDetails {
+	details := &provider.StopDetails{
+		Type: "refusal",
+	}
+
+	for _, c := range result.Categories {
+		details.Categories = append(details.Categories, c.Name)
+	}
+
+	if len(details.Categories) > 0 {
+		details.Category = details.Categories[0]
+		details.Explanation = stage + " flagged by guardrail: " + strings.Join(details.Categories, ", ")
+	} else {
+		details.Explanation = stage + " flagged by guardrail"
+	}
+
+	return details
+}
+
+func refusal(details *provider.StopDetails) *provider.Completion {
+	return &provider.Completion{
+		Status: provider.CompletionStatusRefused,
+
+		StopReason:  provider.StopReasonRefusal,
+		StopDetails: details,
+
+		Message: &provider.Message{
+			Role: provider.MessageRoleAssistant,
+
+			Content: []provider.Content{
+				provider.RefusalContent("The request was blocked by a content policy (" + details.Explanation + ")."),
+			},
+		},
+	}
+}
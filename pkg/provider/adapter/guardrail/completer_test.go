@@ -0,0 +1,205 @@
+package guardrail
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/guard"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type keywordGuard struct {
+	keyword string
+}
+
+func (g *keywordGuard) Check(_ context.Context, text string, _ *guard.CheckOptions) (*guard.Result, error) {
+	if !strings.Contains(text, g.keyword) {
+		return &guard.Result{}, nil
+	}
+
+	return &guard.Result{
+		Flagged: true,
+
+		Categories: []guard.Category{
+			{Name: "violence", Score: 0.9},
+			{Name: "harassment", Score: 0.6},
+		},
+	}, nil
+}
+
+type chunkCompleter struct {
+	calls    int
+	messages []provider.Message
+
+	chunks []string
+}
+
+func (c *chunkCompleter) Complete(_ context.Context, messages []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		c.calls++
+		c.messages = messages
+
+		for _, chunk := range c.chunks {
+			if !yield(&provider.Completion{
+				Message: &provider.Message{
+					Role:    provider.MessageRoleAssistant,
+					Content: []provider.Content{provider.TextContent(chunk)},
+				},
+			}, nil) {
+				return
+			}
+		}
+
+		yield(&provider.Completion{Status: provider.CompletionStatusCompleted}, nil)
+	}
+}
+
+func collect(t *testing.T, c *Completer, messages []provider.Message) (*provider.Completion, int) {
+	t.Helper()
+
+	var acc provider.CompletionAccumulator
+	var count int
+
+	for completion, err := range c.Complete(context.Background(), messages, nil) {
+		if err != nil {
+			t.Fatalf("complete: %v", err)
+		}
+
+		acc.Add(*completion)
+		count++
+	}
+
+	return acc.Result(), count
+}
+
+func TestBlockInput(t *testing.T) {
+	inner := &chunkCompleter{chunks: []string{"hello"}}
+
+	c, err := FromCompleter(inner, &keywordGuard{keyword: "attack"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := collect(t, c, []provider.Message{provider.UserMessage("plan an attack")})
+
+	if inner.calls != 0 {
+		t.Fatalf("expected upstream not to be called, got %d calls", inner.calls)
+	}
+
+	if result.Status != provider.CompletionStatusRefused || result.StopReason != provider.StopReasonRefusal {
+		t.Fatalf("expected refusal, got status=%q reason=%q", result.Status, result.StopReason)
+	}
+
+	if result.StopDetails == nil || result.StopDetails.Category != "violence" || len(result.StopDetails.Categories) != 2 {
+		t.Fatalf("unexpected stop details: %+v", result.StopDetails)
+	}
+}
+
+func TestInputChecksOnlyLatestTurn(t *testing.T) {
+	inner := &chunkCompleter{chunks: []string{"ok"}}
+
+	c, _ := FromCompleter(inner, &keywordGuard{keyword: "attack"})
+
+	result, _ := collect(t, c, []provider.Message{
+		provider.UserMessage("plan an attack"),
+		provider.AssistantMessage("no"),
+		provider.UserMessage("tell me a joke"),
+	})
+
+	if inner.calls != 1 || result.Status == provider.CompletionStatusRefused {
+		t.Fatalf("expected earlier turns to be ignored, got status=%q", result.Status)
+	}
+}
+
+func TestRedactInput(t *testing.T) {
+	inner := &chunkCompleter{chunks: []string{"ok"}}
+
+	c, _ := FromCompleter(inner, &keywordGuard{keyword: "attack"}, WithAction(ActionRedact))
+
+	result, _ := collect(t, c, []provider.Message{provider.UserMessage("plan an attack")})
+
+	if inner.calls != 1 || inner.messages[0].Text() != redacted {
+		t.Fatalf("expected redacted input upstream, got %+v", inner.messages)
+	}
+
+	if result.Status == provider.CompletionStatusRefused || result.StopDetails == nil {
+		t.Fatalf("expected annotated completion, got %+v", result)
+	}
+}
+
+func TestBlockOutput(t *testing.T) {
+	inner := &chunkCompleter{chunks: []string{"the ", "attack ", "plan"}}
+
+	c, _ := FromCompleter(inner, &keywordGuard{keyword: "attack"}, WithOutput(true))
+
+	result, count := collect(t, c, []provider.Message{provider.UserMessage("hi")})
+
+	if count != 1 {
+		t.Fatalf("expected flagged output to be withheld, got %d chunks", count)
+	}
+
+	if result.Status != provider.CompletionStatusRefused || strings.Contains(result.Message.Text(), "attack") {
+		t.Fatalf("expected refusal without flagged text, got %+v", result.Message)
+	}
+}
+
+func TestCleanOutputIsReplayed(t *testing.T) {
+	inner := &chunkCompleter{chunks: []string{"a ", "b ", "c"}}
+
+	c, _ := FromCompleter(inner, &keywordGuard{keyword: "attack"}, WithOutput(true))
+
+	result, count := collect(t, c, []provider.Message{provider.UserMessage("hi")})
+
+	if count != 4 || result.Message.Text() != "a b c" || result.StopDetails != nil {
+		t.Fatalf("expected original chunks, got %d chunks: %+v", count, result)
+	}
+}
+
+func TestAnnotateOutput(t *testing.T) {
+	inner := &chunkCompleter{chunks: []string{"the ", "attack"}}
+
+	c, _ := FromCompleter(inner, &keywordGuard{keyword: "attack"}, WithOutput(true), WithAction(ActionAnnotate))
+
+	result, _ := collect(t, c, []provider.Message{provider.UserMessage("hi")})
+
+	if result.Message.Text() != "the attack" {
+		t.Fatalf("expected output to pass through, got %q", result.Message.Text())
+	}
+
+	if result.StopDetails == nil || !strings.HasPrefix(result.StopDetails.Explanation, "output") {
+		t.Fatalf("expected output annotation, got %+v", result.StopDetails)
+	}
+}
+
+func TestInvalidAction(t *testing.T) {
+	if _, err := FromCompleter(&chunkCompleter{}, &keywordGuard{}, WithAction("drop")); err == nil {
+		t.Fatal("expected error for invalid action")
+	}
+}
+
+func TestRedactToolCallArguments(t *testing.T) {
+	contents := redactContents([]provider.Content{
+		{ToolCall: &provider.ToolCall{ID: "call_1", Name: "lookup", Arguments: `{"email":"jane@example.com","limit":3,"tags":["a"]}`}},
+		{ToolCall: &provider.ToolCall{ID: "call_2", Name: "lookup", Arguments: `jane@example.com`}},
+		{ToolCall: &provider.ToolCall{ID: "call_3", Kind: provider.ToolKindCustom, Name: "note", Arguments: `jane@example.com`}},
+	})
+
+	if got := contents[0].ToolCall.Arguments; got != `{"email":"[redacted]","limit":3,"tags":["[redacted]"]}` {
+		t.Fatalf("unexpected redacted arguments: %s", got)
+	}
+
+	if got := contents[1].ToolCall.Arguments; got != "{}" {
+		t.Fatalf("expected invalid arguments to be dropped, got %s", got)
+	}
+
+	if got := contents[2].ToolCall.Arguments; got != redacted {
+		t.Fatalf("expected freeform input to be replaced, got %s", got)
+	}
+
+	if contents[0].ToolCall.Name != "lookup" {
+		t.Fatalf("expected the call to keep its name, got %+v", contents[0].ToolCall)
+	}
+}
@@ -0,0 +1,23 @@
+package guardrail
+
+type Option func(*Completer)
+
+func WithAction(action Action) Option {
+	return func(c *Completer) {
+		c.action = action
+	}
+}
+
+// WithInput toggles checking the latest input turn. Enabled by default.
+func WithInput(enabled bool) Option {
+	return func(c *Completer) {
+		c.input = enabled
+	}
+}
+
+// WithOutput toggles checking the generated answer. Disabled by default.
+func WithOutput(enabled bool) Option {
+	return func(c *Completer) {
+		c.output = enabled
+	}
+}
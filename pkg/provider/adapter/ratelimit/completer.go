@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/limiter"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ provider.Completer = (*Completer)(nil)
+
+// Completer enforces request, token and daily budgets of the matching rules
+// before calling the wrapped completer, and books the reported token usage
+// once the completion finishes. Token limits are checked against usage
+// already booked, so a single request can overshoot its budget.
+type Completer struct {
+	model     string
+	completer provider.Completer
+
+	limiter limiter.Provider
+	rules   []limiter.Rule
+
+	now func() time.Time
+}
+
+func FromCompleter(model string, completer provider.Completer, limiter limiter.Provider, rules []limiter.Rule) *Completer {
+	return &Completer{
+		model:     model,
+		completer: completer,
+
+		limiter: limiter,
+		rules:   rules,
+
+		now: time.Now,
+	}
+}
+
+type budget struct {
+	key    string
+	window time.Duration
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		budgets, err := c.acquire(ctx)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		var usage provider.Usage
+
+		defer func() {
+			tokens := int64(usage.InputTokens + usage.OutputTokens)
+
+			if tokens <= 0 {
+				return
+			}
+
+			// Book usage even if the client went away mid-stream; the
+			// tokens were spent upstream regardless.
+			ctx := context.WithoutCancel(ctx)
+
+			for _, b := range budgets {
+				c.limiter.Add(ctx, b.key, tokens, b.window)
+			}
+		}()
+
+		for completion, err := range c.completer.Complete(ctx, messages, options) {
+			if completion != nil && completion.Usage != nil {
+				usage.InputTokens = max(usage.InputTokens, completion.Usage.InputTokens)
+				usage.OutputTokens = max(usage.OutputTokens, completion.Usage.OutputTokens)
+			}
+
+			if !yield(completion, err) {
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// acquire counts the request against every matching rule and returns the
+// token budgets the completion's usage has to be booked on.
+func (c *Completer) acquire(ctx context.Context) ([]budget, error) {
+	user, _ := ctx.Value(auth.UserContextKey).(string)
+	groups, _ := ctx.Value(auth.GroupsContextKey).([]string)
+
+	var budgets []budget
+
+	for i, r := range c.rules {
+		if !r.Matches(user, groups, c.model) {
+			continue
+		}
+
+		prefix := strconv.Itoa(i) + "/" + user + "/" + c.model
+
+		if r.Requests > 0 {
+			counter, err := c.limiter.Add(ctx, prefix+"/requests", 1, time.Minute)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if counter.Value > r.Requests {
+				return nil, c.exceeded("requests per minute", counter)
+			}
+		}
+
+		for _, t := range []struct {
+			name   string
+			limit  int64
+			window time.Duration
+		}{
+			{"tokens per minute", r.Tokens, time.Minute},
+			{"tokens per day", r.DailyTokens, 24 * time.Hour},
+		} {
+			if t.limit <= 0 {
+				continue
+			}
+
+			key := prefix + "/tokens/" + t.window.String()
+
+			counter, err := c.limiter.Add(ctx, key, 0, t.window)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if counter.Value >= t.limit {
+				return nil, c.exceeded(t.name, counter)
+			}
+
+			budgets = append(budgets, budget{key, t.window})
+		}
+	}
+
+	return budgets, nil
+}
+
+func (c *Completer) exceeded(limit string, counter *limiter.Counter) error {
+	return &provider.ProviderError{
+		Code:    http.StatusTooManyRequests,
+		Type:    "rate_limit_exceeded",
+		Message: "rate limit exceeded for " + c.model + ": " + limit,
+
+		RetryAfter: counter.Reset.Sub(c.now()),
+	}
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/limiter"
+	"github.com/adrianliechti/wingman/pkg/limiter/memory"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type usageCompleter struct {
+	calls int
+	usage provider.Usage
+}
+
+func (c *usageCompleter) Complete(_ context.Context, _ []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		c.calls++
+
+		yield(&provider.Completion{
+			Status: provider.CompletionStatusCompleted,
+			Usage:  &c.usage,
+		}, nil)
+	}
+}
+
+func complete(c provider.Completer, user string, groups ...string) error {
+	ctx := context.WithValue(context.Background(), auth.UserContextKey, user)
+	ctx = context.WithValue(ctx, auth.GroupsContextKey, groups)
+
+	for _, err := range c.Complete(ctx, []provider.Message{provider.UserMessage("hi")}, nil) {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newCompleter(inner provider.Completer, rules ...limiter.Rule) *Completer {
+	now := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	c := FromCompleter("model", inner, memory.New(memory.WithClock(clock)), rules)
+	c.now = clock
+
+	return c
+}
+
+func TestRequestsPerMinute(t *testing.T) {
+	inner := &usageCompleter{}
+	c := newCompleter(inner, limiter.Rule{Requests: 2})
+
+	for range 2 {
+		if err := complete(c, "alice"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	err := complete(c, "alice")
+
+	perr, ok := errors.AsType[*provider.ProviderError](err)
+
+	if !ok || perr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %v", err)
+	}
+
+	if perr.RetryAfter != 30*time.Second {
+		t.Fatalf("expected retry after end of minute, got %v", perr.RetryAfter)
+	}
+
+	if inner.calls != 2 {
+		t.Fatalf("expected rejected request not to reach upstream, got %d calls", inner.calls)
+	}
+
+	if err := complete(c, "bob"); err != nil {
+		t.Fatalf("expected separate budget per user, got %v", err)
+	}
+}
+
+func TestTokensPerMinute(t *testing.T) {
+	inner := &usageCompleter{usage: provider.Usage{InputTokens: 60, OutputTokens: 40}}
+	c := newCompleter(inner, limiter.Rule{Tokens: 150})
+
+	for range 2 {
+		if err := complete(c, "alice"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := complete(c, "alice"); provider.CodeFromError(err, 0) != http.StatusTooManyRequests {
+		t.Fatalf("expected token budget to be exhausted, got %v", err)
+	}
+}
+
+func TestRuleMatchesGroups(t *testing.T) {
+	inner := &usageCompleter{}
+	c := newCompleter(inner, limiter.Rule{Groups: []string{"free"}, Requests: 1})
+
+	for range 3 {
+		if err := complete(c, "alice", "staff"); err != nil {
+			t.Fatalf("expected unmatched group to be unlimited, got %v", err)
+		}
+	}
+
+	complete(c, "bob", "free")
+
+	if err := complete(c, "bob", "free"); err == nil {
+		t.Fatal("expected limit for matching group")
+	}
+}
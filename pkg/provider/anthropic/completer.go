@@ -982,6 +982,11 @@ func (c *Completer) convertMessageRequest(input []provider.Message, options *pro
 		req.Temperature = anthropic.Float(float64(*options.Temperature))
 	}
 
+	// Recent models reject temperature and top_p together; temperature wins
+	if options.TopP != nil && !req.Temperature.Valid() && req.Thinking.OfAdaptive == nil && !matchesModel(c.model, NoSamplingModels) {
+		req.TopP = anthropic.Float(float64(*options.TopP))
+	}
+
 	if len(messages) > 0 {
 		req.Messages = messages
 	}
@@ -100,6 +100,10 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 			config.Temperature = options.Temperature
 		}
 
+		if options.TopP != nil && !matchesModel(c.model, NoSamplingModels) {
+			config.TopP = options.TopP
+		}
+
 		if len(options.Stop) > 0 {
 			config.StopSequences = options.Stop
 		}
@@ -118,6 +122,7 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		if fields, thinking := c.converseAdditionalFields(messages, options); len(fields) > 0 {
 			if thinking {
 				config.Temperature = nil
+				config.TopP = nil
 			}
 
 			params.AdditionalModelRequestFields = document.NewLazyDocument(fields)
@@ -232,6 +232,16 @@ type CompleteOptions struct {
 
 	MaxTokens   *int
 	Temperature *float32
+	TopP        *float32
+
+	// Seed asks for deterministic sampling where the provider supports it
+	Seed *int
+
+	PresencePenalty  *float32
+	FrequencyPenalty *float32
+
+	// LogitBias maps token ids to a bias added to their logits (-100 to 100)
+	LogitBias map[string]int
 
 	Tools       []Tool
 	ToolOptions *ToolOptions
@@ -239,8 +249,20 @@ type CompleteOptions struct {
 	OutputOptions     *OutputOptions
 	ReasoningOptions  *ReasoningOptions
 	CompactionOptions *CompactionOptions
+	LogprobsOptions   *LogprobsOptions
 
 	Schema *Schema
+
+	// Choices asks for that many alternative answers in one call. Only
+	// models with the Choices capability honor it; the chunks of each
+	// answer carry its index in Completion.Choice.
+	Choices int
+}
+
+// LogprobsOptions requests the log probabilities of the generated tokens,
+// along with the most likely alternatives at each position.
+type LogprobsOptions struct {
+	TopLogprobs int
 }
 
 type CompletionStatus string
@@ -270,6 +292,10 @@ const (
 type Completion struct {
 	ID string
 
+	// Choice is the index of the answer the chunk belongs to, see
+	// CompleteOptions.Choices
+	Choice int
+
 	Model  string
 	Status CompletionStatus
 
@@ -279,14 +305,29 @@ type Completion struct {
 
 	Message *Message
 
+	// Logprobs holds the log probabilities of the tokens generated in this
+	// chunk, when requested through LogprobsOptions
+	Logprobs []Logprob
+
 	Usage *Usage
 }
 
+type Logprob struct {
+	Token   string
+	Logprob float64
+
+	TopLogprobs []Logprob
+}
+
 type StopDetails struct {
 	Type string
 
 	Category    string
 	Explanation string
+
+	// Categories lists every flagged category when more than one applies
+	// (e.g. guardrail checks); Category holds the most severe one.
+	Categories []string
 }
 
 type ReasoningType string
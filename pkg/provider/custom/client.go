@@ -0,0 +1,19 @@
+package custom
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func newClient(url string) (*grpc.ClientConn, error) {
+	if url == "" || !strings.HasPrefix(url, "grpc://") {
+		return nil, errors.New("invalid url")
+	}
+
+	return grpc.NewClient(strings.TrimPrefix(url, "grpc://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
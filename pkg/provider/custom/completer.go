@@ -0,0 +1,334 @@
+package custom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+var _ provider.Completer = (*Completer)(nil)
+
+type Completer struct {
+	*Config
+	client CompleterClient
+}
+
+func NewCompleter(url, model string, options ...Option) (*Completer, error) {
+	cfg := &Config{
+		url:   url,
+		model: model,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	conn, err := newClient(cfg.url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completer{
+		Config: cfg,
+		client: NewCompleterClient(conn),
+	}, nil
+}
+
+func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		if options == nil {
+			options = new(provider.CompleteOptions)
+		}
+
+		req, err := c.convertRequest(messages, options)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		stream, err := c.client.Complete(ctx, req)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(convertCompletion(resp), nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *Completer) convertRequest(messages []provider.Message, options *provider.CompleteOptions) (*CompleteRequest, error) {
+	req := &CompleteRequest{
+		Model: c.model,
+
+		Options: &CompleteOptions{
+			Stop: options.Stop,
+		},
+	}
+
+	for _, m := range messages {
+		req.Messages = append(req.Messages, convertMessage(m))
+	}
+
+	if options.MaxTokens != nil {
+		req.Options.MaxTokens = new(int32(*options.MaxTokens))
+	}
+
+	if options.Temperature != nil {
+		req.Options.Temperature = options.Temperature
+	}
+
+	req.Options.TopP = options.TopP
+
+	if options.Seed != nil {
+		req.Options.Seed = new(int32(*options.Seed))
+	}
+
+	req.Options.PresencePenalty = options.PresencePenalty
+	req.Options.FrequencyPenalty = options.FrequencyPenalty
+
+	for token, bias := range options.LogitBias {
+		if req.Options.LogitBias == nil {
+			req.Options.LogitBias = make(map[string]int32)
+		}
+
+		req.Options.LogitBias[token] = int32(bias)
+	}
+
+	if options.LogprobsOptions != nil {
+		req.Options.TopLogprobs = new(int32(options.LogprobsOptions.TopLogprobs))
+	}
+
+	for _, t := range options.Tools {
+		if t.Kind != provider.ToolKindFunction {
+			continue
+		}
+
+		parameters, err := json.Marshal(t.Parameters)
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Options.Tools = append(req.Options.Tools, &Tool{
+			Name:        t.Name,
+			Description: t.Description,
+
+			Parameters: string(parameters),
+
+			Strict: t.Strict != nil && *t.Strict,
+		})
+	}
+
+	if options.ToolOptions != nil {
+		req.Options.ToolChoice = string(options.ToolOptions.Choice)
+	}
+
+	if options.ReasoningOptions != nil {
+		req.Options.Effort = string(options.ReasoningOptions.Effort)
+	}
+
+	if options.Schema != nil {
+		properties, err := json.Marshal(options.Schema.Properties)
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Options.Schema = &Schema{
+			Name:        options.Schema.Name,
+			Description: options.Schema.Description,
+
+			Properties: string(properties),
+
+			Strict: options.Schema.Strict != nil && *options.Schema.Strict,
+		}
+	}
+
+	return req, nil
+}
+
+func convertMessage(m provider.Message) *Message {
+	result := &Message{
+		Role: string(m.Role),
+	}
+
+	for _, c := range m.Content {
+		content := &Content{
+			Text:    c.Text,
+			Refusal: c.Refusal,
+
+			File: convertFile(c.File),
+		}
+
+		if c.Reasoning != nil {
+			content.Reasoning = &Reasoning{
+				Id: c.Reasoning.ID,
+
+				Text:    c.Reasoning.Text,
+				Summary: c.Reasoning.Summary,
+
+				Signature: c.Reasoning.Signature,
+
+				Redacted: c.Reasoning.Redacted,
+			}
+		}
+
+		if c.ToolCall != nil {
+			content.ToolCall = &ToolCall{
+				Id: c.ToolCall.ID,
+
+				Name:      c.ToolCall.Name,
+				Arguments: c.ToolCall.Arguments,
+			}
+		}
+
+		if c.ToolResult != nil {
+			result := &ToolResult{
+				Id: c.ToolResult.ID,
+
+				IsError: c.ToolResult.IsError,
+			}
+
+			for _, p := range c.ToolResult.Parts {
+				result.Parts = append(result.Parts, &Part{
+					Text: p.Text,
+					File: convertFile(p.File),
+				})
+			}
+
+			content.ToolResult = result
+		}
+
+		result.Content = append(result.Content, content)
+	}
+
+	return result
+}
+
+func convertFile(f *provider.File) *File {
+	if f == nil {
+		return nil
+	}
+
+	return &File{
+		Name: f.Name,
+
+		Content:     f.Content,
+		ContentType: f.ContentType,
+	}
+}
+
+func convertCompletion(resp *Completion) *provider.Completion {
+	result := &provider.Completion{
+		ID:    resp.Id,
+		Model: resp.Model,
+
+		Status:     provider.CompletionStatus(resp.Status),
+		StopReason: provider.StopReason(resp.StopReason),
+	}
+
+	if m := resp.Message; m != nil {
+		message := &provider.Message{
+			Role: provider.MessageRole(m.Role),
+		}
+
+		if message.Role == "" {
+			message.Role = provider.MessageRoleAssistant
+		}
+
+		for _, c := range m.Content {
+			content := provider.Content{
+				Text:    c.Text,
+				Refusal: c.Refusal,
+			}
+
+			if c.File != nil {
+				content.File = &provider.File{
+					Name: c.File.Name,
+
+					Content:     c.File.Content,
+					ContentType: c.File.ContentType,
+				}
+			}
+
+			if r := c.Reasoning; r != nil {
+				content.Reasoning = &provider.Reasoning{
+					ID: r.Id,
+
+					Text:    r.Text,
+					Summary: r.Summary,
+
+					Signature: r.Signature,
+
+					Redacted: r.Redacted,
+				}
+			}
+
+			if t := c.ToolCall; t != nil {
+				content.ToolCall = &provider.ToolCall{
+					ID: t.Id,
+
+					Name:      t.Name,
+					Arguments: t.Arguments,
+				}
+			}
+
+			message.Content = append(message.Content, content)
+		}
+
+		result.Message = message
+	}
+
+	if u := resp.Usage; u != nil {
+		result.Usage = &provider.Usage{
+			InputTokens:  int(u.InputTokens),
+			OutputTokens: int(u.OutputTokens),
+
+			ReasoningTokens: int(u.ReasoningTokens),
+
+			CacheReadInputTokens:     int(u.CacheReadInputTokens),
+			CacheCreationInputTokens: int(u.CacheCreationInputTokens),
+		}
+	}
+
+	result.Logprobs = convertLogprobs(resp.Logprobs)
+
+	return result
+}
+
+func convertLogprobs(logprobs []*Logprob) []provider.Logprob {
+	var result []provider.Logprob
+
+	for _, l := range logprobs {
+		result = append(result, provider.Logprob{
+			Token:   l.Token,
+			Logprob: l.Logprob,
+
+			TopLogprobs: convertLogprobs(l.TopLogprobs),
+		})
+	}
+
+	return result
+}
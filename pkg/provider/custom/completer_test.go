@@ -0,0 +1,109 @@
+package custom
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"google.golang.org/grpc"
+)
+
+type echoServer struct {
+	UnimplementedCompleterServer
+}
+
+func (echoServer) Complete(req *CompleteRequest, stream grpc.ServerStreamingServer[Completion]) error {
+	last := req.Messages[len(req.Messages)-1]
+
+	for _, text := range []string{req.Model + ": ", last.Content[0].Text} {
+		if err := stream.Send(&Completion{Message: &Message{Content: []*Content{{Text: text}}}}); err != nil {
+			return err
+		}
+	}
+
+	return stream.Send(&Completion{
+		Status: string(provider.CompletionStatusCompleted),
+
+		Message: &Message{
+			Content: []*Content{{ToolCall: &ToolCall{Id: "call_1", Name: "lookup", Arguments: "{}"}}},
+		},
+
+		Usage: &Usage{InputTokens: 3, OutputTokens: 5},
+	})
+}
+
+func TestCompleter(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := grpc.NewServer()
+	RegisterCompleterServer(s, echoServer{})
+
+	go s.Serve(l)
+	defer s.Stop()
+
+	c, err := NewCompleter("grpc://"+l.Addr().String(), "house-llm")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var acc provider.CompletionAccumulator
+
+	for completion, err := range c.Complete(context.Background(), []provider.Message{provider.UserMessage("hello")}, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		acc.Add(*completion)
+	}
+
+	result := acc.Result()
+
+	if got := result.Message.Text(); got != "house-llm: hello" {
+		t.Fatalf("unexpected text %q", got)
+	}
+
+	if calls := result.Message.ToolCalls(); len(calls) != 1 || calls[0].Name != "lookup" {
+		t.Fatalf("unexpected tool calls %+v", calls)
+	}
+
+	if result.Status != provider.CompletionStatusCompleted || result.Usage == nil || result.Usage.OutputTokens != 5 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestConvertRequestSampling(t *testing.T) {
+	c := &Completer{Config: &Config{model: "house-llm"}}
+
+	req, err := c.convertRequest(nil, &provider.CompleteOptions{
+		TopP: new(float32(0.9)),
+		Seed: new(42),
+
+		PresencePenalty:  new(float32(0.5)),
+		FrequencyPenalty: new(float32(0.25)),
+
+		LogitBias: map[string]int{"1234": -100},
+
+		LogprobsOptions: &provider.LogprobsOptions{TopLogprobs: 3},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := req.Options
+
+	if o.GetTopP() != 0.9 || o.GetSeed() != 42 || o.GetPresencePenalty() != 0.5 || o.GetFrequencyPenalty() != 0.25 {
+		t.Fatalf("unexpected sampling options %+v", o)
+	}
+
+	if o.LogitBias["1234"] != -100 || o.TopLogprobs == nil || *o.TopLogprobs != 3 {
+		t.Fatalf("unexpected logit bias or logprobs %+v", o)
+	}
+}
@@ -97,6 +97,8 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 					}
 				}
 
+				delta.Logprobs = toLogprobs(candidate.LogprobsResult)
+
 				applyFinishReason(delta, candidate.FinishReason, sawToolCall)
 			}
 
@@ -213,6 +215,30 @@ func convertGenerateConfig(instruction *genai.Content, options *provider.Complet
 		config.Temperature = options.Temperature
 	}
 
+	if options.TopP != nil {
+		config.TopP = options.TopP
+	}
+
+	if options.Seed != nil {
+		config.Seed = new(int32(*options.Seed))
+	}
+
+	if options.PresencePenalty != nil {
+		config.PresencePenalty = options.PresencePenalty
+	}
+
+	if options.FrequencyPenalty != nil {
+		config.FrequencyPenalty = options.FrequencyPenalty
+	}
+
+	if options.LogprobsOptions != nil {
+		config.ResponseLogprobs = true
+
+		if options.LogprobsOptions.TopLogprobs > 0 {
+			config.Logprobs = new(int32(options.LogprobsOptions.TopLogprobs))
+		}
+	}
+
 	if options.Schema != nil {
 		config.ResponseMIMEType = "application/json"
 		config.ResponseJsonSchema = options.Schema.Properties
@@ -578,6 +604,40 @@ func applyFinishReason(delta *provider.Completion, reason genai.FinishReason, sa
 	}
 }
 
+// toLogprobs pairs the chosen token of each decoding step with the top
+// candidates of the same step.
+func toLogprobs(result *genai.LogprobsResult) []provider.Logprob {
+	if result == nil {
+		return nil
+	}
+
+	var logprobs []provider.Logprob
+
+	for i, chosen := range result.ChosenCandidates {
+		if chosen == nil {
+			continue
+		}
+
+		logprob := provider.Logprob{
+			Token:   chosen.Token,
+			Logprob: float64(chosen.LogProbability),
+		}
+
+		if i < len(result.TopCandidates) && result.TopCandidates[i] != nil {
+			for _, c := range result.TopCandidates[i].Candidates {
+				logprob.TopLogprobs = append(logprob.TopLogprobs, provider.Logprob{
+					Token:   c.Token,
+					Logprob: float64(c.LogProbability),
+				})
+			}
+		}
+
+		logprobs = append(logprobs, logprob)
+	}
+
+	return logprobs
+}
+
 func toCompletionUsage(metadata *genai.GenerateContentResponseUsageMetadata) *provider.Usage {
 	if metadata == nil {
 		return nil
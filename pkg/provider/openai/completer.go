@@ -56,24 +56,29 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 		stream := c.completions.NewStreaming(ctx, *req)
 
 		toolAliases := provider.ToolAliases(options.Tools)
-		toolCallIDs := map[int64]string{}
+		toolCallIDs := map[[2]int64]string{}
 
 		for stream.Next() {
 			chunk := stream.Current()
 
-			delta := &provider.Completion{
-				ID:    chunk.ID,
-				Model: c.model,
+			newDelta := func(choice int) *provider.Completion {
+				return &provider.Completion{
+					ID:     chunk.ID,
+					Model:  c.model,
+					Choice: choice,
 
-				Message: &provider.Message{
-					Role: provider.MessageRoleAssistant,
-				},
-
-				Usage: toUsage(chunk.Usage),
+					Message: &provider.Message{
+						Role: provider.MessageRoleAssistant,
+					},
+				}
 			}
 
-			if len(chunk.Choices) > 0 {
-				choice := chunk.Choices[0]
+			// one delta per choice; with several choices requested, a chunk
+			// may carry more than one
+			var deltas []*provider.Completion
+
+			for _, choice := range chunk.Choices {
+				delta := newDelta(int(choice.Index))
 
 				if choice.Delta.JSON.Content.Valid() {
 					delta.Message.Content = append(delta.Message.Content, provider.TextContent(choice.Delta.Content))
@@ -84,7 +89,7 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 				}
 
 				for _, c := range choice.Delta.ToolCalls {
-					index := max(c.Index, 0)
+					index := [2]int64{choice.Index, max(c.Index, 0)}
 
 					if c.ID != "" {
 						toolCallIDs[index] = c.ID
@@ -107,11 +112,22 @@ func (c *Completer) Complete(ctx context.Context, messages []provider.Message, o
 					delta.Message.Content = append(delta.Message.Content, provider.ToolCallContent(call))
 				}
 
+				delta.Logprobs = toLogprobs(choice.Logprobs.Content)
 				delta.Status = toCompletionStatus(choice.FinishReason)
+
+				deltas = append(deltas, delta)
 			}
 
-			if !yield(delta, nil) {
-				return
+			if len(deltas) == 0 {
+				deltas = append(deltas, newDelta(0))
+			}
+
+			deltas[0].Usage = toUsage(chunk.Usage)
+
+			for _, delta := range deltas {
+				if !yield(delta, nil) {
+					return
+				}
 			}
 		}
 
@@ -257,6 +273,42 @@ func (c *Completer) convertCompletionRequest(input []provider.Message, options *
 		req.Temperature = openai.Float(float64(*options.Temperature))
 	}
 
+	if options.Choices > 1 {
+		req.N = openai.Int(int64(options.Choices))
+	}
+
+	if options.TopP != nil {
+		req.TopP = openai.Float(float64(*options.TopP))
+	}
+
+	if options.Seed != nil {
+		req.Seed = openai.Int(int64(*options.Seed))
+	}
+
+	if options.PresencePenalty != nil {
+		req.PresencePenalty = openai.Float(float64(*options.PresencePenalty))
+	}
+
+	if options.FrequencyPenalty != nil {
+		req.FrequencyPenalty = openai.Float(float64(*options.FrequencyPenalty))
+	}
+
+	if len(options.LogitBias) > 0 {
+		req.LogitBias = make(map[string]int64, len(options.LogitBias))
+
+		for token, bias := range options.LogitBias {
+			req.LogitBias[token] = int64(bias)
+		}
+	}
+
+	if options.LogprobsOptions != nil {
+		req.Logprobs = openai.Bool(true)
+
+		if options.LogprobsOptions.TopLogprobs > 0 {
+			req.TopLogprobs = openai.Int(int64(options.LogprobsOptions.TopLogprobs))
+		}
+	}
+
 	if strings.Contains(c.url, "api.mistral.ai") {
 		req.StreamOptions = openai.ChatCompletionStreamOptionsParam{}
 
@@ -527,3 +579,25 @@ func toUsage(metadata openai.CompletionUsage) *provider.Usage {
 		CacheCreationInputTokens: int(metadata.PromptTokensDetails.CacheWriteTokens),
 	}
 }
+
+func toLogprobs(tokens []openai.ChatCompletionTokenLogprob) []provider.Logprob {
+	var result []provider.Logprob
+
+	for _, t := range tokens {
+		logprob := provider.Logprob{
+			Token:   t.Token,
+			Logprob: t.Logprob,
+		}
+
+		for _, top := range t.TopLogprobs {
+			logprob.TopLogprobs = append(logprob.TopLogprobs, provider.Logprob{
+				Token:   top.Token,
+				Logprob: top.Logprob,
+			})
+		}
+
+		result = append(result, logprob)
+	}
+
+	return result
+}
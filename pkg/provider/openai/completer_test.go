@@ -359,3 +359,67 @@ func TestCompleterSynthesizesMissingToolCallIDs(t *testing.T) {
 		t.Errorf("call 1: got %+v", calls[1])
 	}
 }
+
+func TestCompleterSamplingOptionsAndLogprobs(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","model":"test","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"},"logprobs":{"content":[{"token":"Hi","bytes":[72,105],"logprob":-0.25,"top_logprobs":[{"token":"Hi","bytes":[72,105],"logprob":-0.25},{"token":"Hey","bytes":[72,101,121],"logprob":-1.5}]}],"refusal":null}}]}` + "\n\n"))
+		w.Write([]byte(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","model":"test","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}` + "\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	completer, err := NewCompleter(server.URL, "test")
+	if err != nil {
+		t.Fatalf("new completer: %v", err)
+	}
+
+	options := &provider.CompleteOptions{
+		TopP: new(float32(0.5)),
+		Seed: new(42),
+
+		PresencePenalty:  new(float32(0.25)),
+		FrequencyPenalty: new(float32(-0.5)),
+
+		LogitBias: map[string]int{"50256": -100},
+
+		LogprobsOptions: &provider.LogprobsOptions{TopLogprobs: 2},
+	}
+
+	acc := provider.CompletionAccumulator{}
+
+	for completion, err := range completer.Complete(t.Context(), []provider.Message{provider.UserMessage("hi")}, options) {
+		if err != nil {
+			t.Fatalf("complete: %v", err)
+		}
+		acc.Add(*completion)
+	}
+
+	for key, want := range map[string]any{
+		"top_p":             0.5,
+		"seed":              float64(42),
+		"presence_penalty":  0.25,
+		"frequency_penalty": -0.5,
+		"logprobs":          true,
+		"top_logprobs":      float64(2),
+	} {
+		if body[key] != want {
+			t.Errorf("%s: got %v, want %v", key, body[key], want)
+		}
+	}
+
+	if bias, _ := body["logit_bias"].(map[string]any); bias["50256"] != float64(-100) {
+		t.Errorf("logit_bias: got %v", body["logit_bias"])
+	}
+
+	logprobs := acc.Result().Logprobs
+
+	if len(logprobs) != 1 || logprobs[0].Token != "Hi" || logprobs[0].Logprob != -0.25 || len(logprobs[0].TopLogprobs) != 2 || logprobs[0].TopLogprobs[1].Token != "Hey" {
+		t.Fatalf("logprobs: got %+v", logprobs)
+	}
+}
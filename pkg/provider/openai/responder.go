@@ -152,7 +152,17 @@ func (r *Responder) Complete(ctx context.Context, messages []provider.Message, o
 
 			case responses.ResponseContentPartAddedEvent:
 			case responses.ResponseTextDeltaEvent:
-				if !emit(provider.TextContent(event.Delta), "") {
+				if !yield(&provider.Completion{
+					ID:    responseID,
+					Model: responseModel,
+
+					Message: &provider.Message{
+						Role:    provider.MessageRoleAssistant,
+						Content: []provider.Content{provider.TextContent(event.Delta)},
+					},
+
+					Logprobs: toResponseLogprobs(event.Logprobs),
+				}, nil) {
 					return
 				}
 
@@ -307,9 +317,11 @@ func (r *Responder) Complete(ctx context.Context, messages []provider.Message, o
 }
 
 func (r *Responder) convertResponsesRequest(messages []provider.Message, options *provider.CompleteOptions) (*responses.ResponseNewParams, error) {
-	if !isLegacyModel(r.model) && options.Temperature != nil {
+	// Reasoning models reject sampling parameters
+	if !isLegacyModel(r.model) && (options.Temperature != nil || options.TopP != nil) {
 		optsCopy := *options
 		optsCopy.Temperature = nil
+		optsCopy.TopP = nil
 		options = &optsCopy
 	}
 
@@ -458,9 +470,43 @@ func (r *Responder) convertResponsesRequest(messages []provider.Message, options
 		req.Temperature = openai.Float(float64(*options.Temperature))
 	}
 
+	if options.TopP != nil {
+		req.TopP = openai.Float(float64(*options.TopP))
+	}
+
+	if options.LogprobsOptions != nil {
+		req.Include = append(req.Include, responses.ResponseIncludableMessageOutputTextLogprobs)
+
+		if options.LogprobsOptions.TopLogprobs > 0 {
+			req.TopLogprobs = openai.Int(int64(options.LogprobsOptions.TopLogprobs))
+		}
+	}
+
 	return req, nil
 }
 
+func toResponseLogprobs(tokens []responses.ResponseTextDeltaEventLogprob) []provider.Logprob {
+	var result []provider.Logprob
+
+	for _, t := range tokens {
+		logprob := provider.Logprob{
+			Token:   t.Token,
+			Logprob: t.Logprob,
+		}
+
+		for _, top := range t.TopLogprobs {
+			logprob.TopLogprobs = append(logprob.TopLogprobs, provider.Logprob{
+				Token:   top.Token,
+				Logprob: top.Logprob,
+			})
+		}
+
+		result = append(result, logprob)
+	}
+
+	return result
+}
+
 // freeformPatchTool reports whether apply_patch was declared in its freeform
 // (grammar) form. Calls and results then replay as custom_tool_call items so
 // multi-file envelopes survive verbatim.
@@ -0,0 +1,32 @@
+package provider
+
+// Pricing holds a model's rates in currency units per million tokens. Cache
+// and reasoning tokens are billed at their own rates instead of the input
+// and output rates they are part of (see Usage).
+type Pricing struct {
+	Input  float64
+	Output float64
+
+	CacheRead  float64
+	CacheWrite float64
+
+	Reasoning float64
+}
+
+// Cost returns the price of the given usage.
+func (p *Pricing) Cost(usage *Usage) float64 {
+	if p == nil || usage == nil {
+		return 0
+	}
+
+	input := max(usage.InputTokens-usage.CacheReadInputTokens-usage.CacheCreationInputTokens, 0)
+	output := max(usage.OutputTokens-usage.ReasoningTokens, 0)
+
+	cost := float64(input)*p.Input +
+		float64(usage.CacheReadInputTokens)*p.CacheRead +
+		float64(usage.CacheCreationInputTokens)*p.CacheWrite +
+		float64(output)*p.Output +
+		float64(usage.ReasoningTokens)*p.Reasoning
+
+	return cost / 1_000_000
+}
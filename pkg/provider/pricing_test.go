@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPricingCost(t *testing.T) {
+	pricing := &Pricing{
+		Input:  2,
+		Output: 10,
+
+		CacheRead:  0.5,
+		CacheWrite: 2.5,
+
+		Reasoning: 10,
+	}
+
+	usage := &Usage{
+		InputTokens:  1_000_000,
+		OutputTokens: 200_000,
+
+		ReasoningTokens: 100_000,
+
+		CacheReadInputTokens:     400_000,
+		CacheCreationInputTokens: 100_000,
+	}
+
+	// 500k input at 2, 400k cache reads at 0.5, 100k cache writes at 2.5,
+	// 100k output at 10 and 100k reasoning at 10
+	want := 1.0 + 0.2 + 0.25 + 1.0 + 1.0
+
+	if got := pricing.Cost(usage); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("expected cost %v, got %v", want, got)
+	}
+
+	var none *Pricing
+
+	if got := none.Cost(usage); got != 0 {
+		t.Fatalf("expected zero cost without pricing, got %v", got)
+	}
+}
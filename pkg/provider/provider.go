@@ -1,7 +1,64 @@
 package provider
 
+import (
+	"strings"
+	"time"
+)
+
 type Model struct {
 	ID string
+
+	Name        string
+	Description string
+
+	Capabilities ModelCapabilities
+}
+
+// ModelCapabilities describes what a model accepts and produces. Zero values
+// mean unknown: nothing is rejected on their account.
+type ModelCapabilities struct {
+	ContextWindow   int
+	MaxOutputTokens int
+
+	InputModalities  []Modality
+	OutputModalities []Modality
+
+	Tools            *bool
+	Reasoning        *bool
+	StructuredOutput *bool
+
+	// Choices reports whether the model generates alternative answers in
+	// one call, see CompleteOptions.Choices
+	Choices *bool
+
+	DeprecationDate *time.Time
+}
+
+type Modality string
+
+const (
+	ModalityText  Modality = "text"
+	ModalityImage Modality = "image"
+	ModalityAudio Modality = "audio"
+	ModalityVideo Modality = "video"
+	ModalityFile  Modality = "file"
+)
+
+// ModalityOf returns the modality of a file by its content type. Documents
+// such as PDFs are files; plain text counts as text.
+func ModalityOf(contentType string) Modality {
+	switch {
+	case strings.HasPrefix(contentType, "text/"):
+		return ModalityText
+	case strings.HasPrefix(contentType, "image/"):
+		return ModalityImage
+	case strings.HasPrefix(contentType, "audio/"):
+		return ModalityAudio
+	case strings.HasPrefix(contentType, "video/"):
+		return ModalityVideo
+	}
+
+	return ModalityFile
 }
 
 type File struct {
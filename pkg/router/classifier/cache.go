@@ -1,69 +0,0 @@
-package classifier
-
-import (
-	"container/list"
-	"sync"
-)
-
-// lruCache is a small, fixed-capacity, concurrency-safe map of request
-// fingerprint to routing decision.
-type lruCache struct {
-	mu sync.Mutex
-
-	capacity int
-
-	ll    *list.List
-	items map[uint64]*list.Element
-}
-
-type lruEntry struct {
-	key   uint64
-	value decision
-}
-
-func newLRU(capacity int) *lruCache {
-	if capacity < 1 {
-		capacity = 1
-	}
-
-	return &lruCache{
-		capacity: capacity,
-
-		ll:    list.New(),
-		items: make(map[uint64]*list.Element),
-	}
-}
-
-func (c *lruCache) get(key uint64) (decision, bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if el, ok := c.items[key]; ok {
-		c.ll.MoveToFront(el)
-		return el.Value.(*lruEntry).value, true
-	}
-
-	return decision{}, false
-}
-
-func (c *lruCache) put(key uint64, value decision) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if el, ok := c.items[key]; ok {
-		c.ll.MoveToFront(el)
-		el.Value.(*lruEntry).value = value
-
-		return
-	}
-
-	el := c.ll.PushFront(&lruEntry{key: key, value: value})
-	c.items[key] = el
-
-	if c.ll.Len() > c.capacity {
-		if oldest := c.ll.Back(); oldest != nil {
-			c.ll.Remove(oldest)
-			delete(c.items, oldest.Value.(*lruEntry).key)
-		}
-	}
-}
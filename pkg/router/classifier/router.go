@@ -18,6 +18,7 @@ import (
 	"iter"
 	"slices"
 
+	"github.com/adrianliechti/wingman/pkg/lru"
 	"github.com/adrianliechti/wingman/pkg/provider"
 )
 
@@ -86,7 +87,7 @@ type Completer struct {
 
 	judge provider.Completer
 
-	decisionCache *lruCache
+	decisionCache *lru.Cache[uint64, decision]
 
 	centroids *centroidCache
 }
@@ -118,7 +119,7 @@ func NewCompleter(candidates []Candidate, opts Options) (*Completer, error) {
 
 		judge: opts.Judge,
 
-		decisionCache: newLRU(decisionCacheSize),
+		decisionCache: lru.New[uint64, decision](decisionCacheSize),
 	}
 
 	if opts.Embedder != nil {
@@ -190,12 +191,12 @@ func (c *Completer) classify(ctx context.Context, messages []provider.Message, o
 	// A cached decision must still satisfy the hard constraints: the
 	// fingerprint is keyed on the user instruction, but tool round-trips grow
 	// the context and can push it past a cached candidate's MaxContext.
-	if d, ok := c.decisionCache.get(fp); ok && isEligible(c.candidates[d.index], s) && isEligible(c.candidates[d.fallback], s) {
+	if d, ok := c.decisionCache.Get(fp); ok && isEligible(c.candidates[d.index], s) && isEligible(c.candidates[d.fallback], s) {
 		return d
 	}
 
 	d := c.decide(ctx, s)
-	c.decisionCache.put(fp, d)
+	c.decisionCache.Add(fp, d)
 
 	return d
 }
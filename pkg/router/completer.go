@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"iter"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/adrianliechti/wingman/pkg/provider"
@@ -26,6 +27,8 @@ type Completer struct {
 
 	fallback provider.Completer
 
+	names []string
+
 	failureThreshold  int
 	recoveryTimeout   time.Duration
 	firstTokenTimeout time.Duration
@@ -40,6 +43,14 @@ func WithFallback(fallback provider.Completer) Option {
 	}
 }
 
+// WithNames labels the providers, indexed like the completers, e.g. with
+// their model ids for health reporting.
+func WithNames(names ...string) Option {
+	return func(c *Completer) {
+		c.names = names
+	}
+}
+
 // WithFirstTokenTimeout bounds the wait for the first response token. A
 // provider that produces nothing within this window is recorded as failed and
 // the request fails over to the next provider. Zero disables the deadline.
@@ -96,6 +107,38 @@ func (c *Completer) Stats() []*ProviderStats {
 	return c.stats
 }
 
+// Names returns the provider labels, indexed like Stats. Unlabeled
+// providers are named by their index.
+func (c *Completer) Names() []string {
+	names := make([]string, len(c.completers))
+
+	for i := range names {
+		if i < len(c.names) && c.names[i] != "" {
+			names[i] = c.names[i]
+		} else {
+			names[i] = strconv.Itoa(i)
+		}
+	}
+
+	return names
+}
+
+// Available reports whether a request could be served right now: at least
+// one provider's circuit admits traffic, or a fallback is configured.
+func (c *Completer) Available() bool {
+	if c.fallback != nil {
+		return true
+	}
+
+	for _, s := range c.stats {
+		if s.IsCandidate(c.recoveryTimeout) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Complete routes the request to the best available provider, failing over to
 // other providers as long as no output has been delivered to the caller
 func (c *Completer) Complete(ctx context.Context, messages []provider.Message, options *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
@@ -17,6 +17,19 @@ const (
 	CircuitHalfOpen                     // Testing if recovered
 )
 
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+
+	case CircuitHalfOpen:
+		return "half_open"
+
+	default:
+		return "closed"
+	}
+}
+
 // Default configuration values
 const (
 	DefaultFailureThreshold  = 5
@@ -0,0 +1,312 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/store"
+)
+
+var _ store.Provider = (*Store)(nil)
+
+// Store keeps one JSON document per response in a directory, so state
+// survives restarts without an external database. Files and batches live
+// in the files/ and batches/ subdirectories.
+type Store struct {
+	path string
+}
+
+func New(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.New("invalid store path")
+	}
+
+	for _, dir := range []string{path, filepath.Join(path, "files"), filepath.Join(path, "batches")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Store{
+		path: path,
+	}, nil
+}
+
+func (s *Store) GetResponse(ctx context.Context, id string) (*store.Response, error) {
+	path, ok := s.documentPath("", id, ".json")
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	var response store.Response
+
+	if err := readDocument(path, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (s *Store) SaveResponse(ctx context.Context, response *store.Response) error {
+	path, ok := s.documentPath("", response.ID, ".json")
+
+	if !ok {
+		return errors.New("invalid response id: " + response.ID)
+	}
+
+	return writeDocument(path, response)
+}
+
+func (s *Store) DeleteResponse(ctx context.Context, id string) error {
+	path, ok := s.documentPath("", id, ".json")
+
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	return removeFile(path)
+}
+
+func (s *Store) ListFiles(ctx context.Context) ([]store.File, error) {
+	var result []store.File
+
+	err := s.listDocuments("files", func(data []byte) error {
+		var file store.File
+
+		if err := json.Unmarshal(data, &file); err != nil {
+			return err
+		}
+
+		result = append(result, file)
+		return nil
+	})
+
+	slices.SortFunc(result, func(a, b store.File) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, err
+}
+
+func (s *Store) GetFile(ctx context.Context, id string) (*store.File, error) {
+	path, ok := s.documentPath("files", id, ".json")
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	var file store.File
+
+	if err := readDocument(path, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+func (s *Store) GetFileContent(ctx context.Context, id string) ([]byte, error) {
+	path, ok := s.documentPath("files", id, ".data")
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, store.ErrNotFound
+	}
+
+	return data, err
+}
+
+func (s *Store) SaveFile(ctx context.Context, file *store.File, content []byte) error {
+	meta, ok := s.documentPath("files", file.ID, ".json")
+
+	if !ok {
+		return errors.New("invalid file id: " + file.ID)
+	}
+
+	data, _ := s.documentPath("files", file.ID, ".data")
+
+	// content first, so listed metadata always has its data
+	if err := writeFile(data, content); err != nil {
+		return err
+	}
+
+	return writeDocument(meta, file)
+}
+
+func (s *Store) DeleteFile(ctx context.Context, id string) error {
+	meta, ok := s.documentPath("files", id, ".json")
+
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	if err := removeFile(meta); err != nil {
+		return err
+	}
+
+	data, _ := s.documentPath("files", id, ".data")
+	os.Remove(data)
+
+	return nil
+}
+
+func (s *Store) ListBatches(ctx context.Context) ([]store.Batch, error) {
+	var result []store.Batch
+
+	err := s.listDocuments("batches", func(data []byte) error {
+		var batch store.Batch
+
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return err
+		}
+
+		result = append(result, batch)
+		return nil
+	})
+
+	slices.SortFunc(result, func(a, b store.Batch) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, err
+}
+
+func (s *Store) GetBatch(ctx context.Context, id string) (*store.Batch, error) {
+	path, ok := s.documentPath("batches", id, ".json")
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	var batch store.Batch
+
+	if err := readDocument(path, &batch); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+func (s *Store) SaveBatch(ctx context.Context, batch *store.Batch) error {
+	path, ok := s.documentPath("batches", batch.ID, ".json")
+
+	if !ok {
+		return errors.New("invalid batch id: " + batch.ID)
+	}
+
+	return writeDocument(path, batch)
+}
+
+func (s *Store) DeleteBatch(ctx context.Context, id string) error {
+	path, ok := s.documentPath("batches", id, ".json")
+
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	return removeFile(path)
+}
+
+func (s *Store) documentPath(dir, id, ext string) (string, bool) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", false
+	}
+
+	return filepath.Join(s.path, dir, id+ext), true
+}
+
+func (s *Store) listDocuments(dir string, fn func(data []byte) error) error {
+	paths, err := filepath.Glob(filepath.Join(s.path, dir, "*.json"))
+
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			// deleted concurrently
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return err
+		}
+
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readDocument(path string, v any) error {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return store.ErrNotFound
+		}
+
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func writeDocument(path string, v any) error {
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(path, data)
+}
+
+func writeFile(path string, data []byte) error {
+	// write to a temp file first so readers never observe a partial document
+	temp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), path)
+}
+
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return store.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/store"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	s, err := New(t.TempDir())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if err := s.SaveResponse(ctx, &store.Response{ID: "resp_1", PreviousID: "resp_0", Output: []byte(`[]`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := s.GetResponse(ctx, "resp_1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.PreviousID != "resp_0" || string(r.Output) != "[]" {
+		t.Fatalf("unexpected response: %+v", r)
+	}
+
+	if err := s.DeleteResponse(ctx, "resp_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.GetResponse(ctx, "resp_1"); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStoreRejectsPathIDs(t *testing.T) {
+	s, err := New(t.TempDir())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"../escape", "a/b", ".hidden", ""} {
+		if _, err := s.GetResponse(context.Background(), id); !errors.Is(err, store.ErrNotFound) {
+			t.Fatalf("%q: expected ErrNotFound, got %v", id, err)
+		}
+	}
+}
+
+func TestStoreFiles(t *testing.T) {
+	s, err := New(t.TempDir())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if err := s.SaveFile(ctx, &store.File{ID: "file-1", Name: "input.jsonl", Size: 5}, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := s.ListFiles(ctx)
+
+	if err != nil || len(files) != 1 || files[0].Name != "input.jsonl" {
+		t.Fatalf("unexpected files: %+v (%v)", files, err)
+	}
+
+	content, err := s.GetFileContent(ctx, "file-1")
+
+	if err != nil || string(content) != "hello" {
+		t.Fatalf("unexpected content: %q (%v)", content, err)
+	}
+
+	if err := s.DeleteFile(ctx, "file-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.GetFileContent(ctx, "file-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package memory
+
+type Option func(*Store)
+
+// WithLimit caps the number of retained responses. Zero disables the limit.
+func WithLimit(limit int) Option {
+	return func(s *Store) {
+		s.limit = limit
+	}
+}
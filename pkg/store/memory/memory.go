@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/adrianliechti/wingman/pkg/store"
+)
+
+var _ store.Provider = (*Store)(nil)
+
+type Store struct {
+	mu sync.RWMutex
+
+	limit int
+
+	order     []string
+	responses map[string]*store.Response
+
+	files   map[string]*memoryFile
+	batches map[string]*store.Batch
+}
+
+type memoryFile struct {
+	file    store.File
+	content []byte
+}
+
+func New(options ...Option) *Store {
+	s := &Store{
+		limit: 1000,
+
+		responses: make(map[string]*store.Response),
+
+		files:   make(map[string]*memoryFile),
+		batches: make(map[string]*store.Batch),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+func (s *Store) GetResponse(ctx context.Context, id string) (*store.Response, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.responses[id]
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	result := *r
+	return &result, nil
+}
+
+func (s *Store) SaveResponse(ctx context.Context, response *store.Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := *response
+
+	if _, ok := s.responses[r.ID]; !ok {
+		s.order = append(s.order, r.ID)
+	}
+
+	s.responses[r.ID] = &r
+
+	// Evict the oldest turns once the limit is reached; chains that reference
+	// them fail with not found, just like after an explicit delete.
+	for s.limit > 0 && len(s.order) > s.limit {
+		delete(s.responses, s.order[0])
+		s.order = s.order[1:]
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteResponse(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.responses[id]; !ok {
+		return store.ErrNotFound
+	}
+
+	delete(s.responses, id)
+
+	for i, val := range s.order {
+		if val == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) ListFiles(ctx context.Context) ([]store.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []store.File
+
+	for _, f := range s.files {
+		result = append(result, f.file)
+	}
+
+	slices.SortFunc(result, func(a, b store.File) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (s *Store) GetFile(ctx context.Context, id string) (*store.File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.files[id]
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	result := f.file
+	return &result, nil
+}
+
+func (s *Store) GetFileContent(ctx context.Context, id string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.files[id]
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	return f.content, nil
+}
+
+func (s *Store) SaveFile(ctx context.Context, file *store.File, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[file.ID] = &memoryFile{
+		file:    *file,
+		content: slices.Clone(content),
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteFile(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.files[id]; !ok {
+		return store.ErrNotFound
+	}
+
+	delete(s.files, id)
+
+	return nil
+}
+
+func (s *Store) ListBatches(ctx context.Context) ([]store.Batch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []store.Batch
+
+	for _, b := range s.batches {
+		result = append(result, *b)
+	}
+
+	slices.SortFunc(result, func(a, b store.Batch) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (s *Store) GetBatch(ctx context.Context, id string) (*store.Batch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.batches[id]
+
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	result := *b
+	return &result, nil
+}
+
+func (s *Store) SaveBatch(ctx context.Context, batch *store.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := *batch
+	s.batches[b.ID] = &b
+
+	return nil
+}
+
+func (s *Store) DeleteBatch(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.batches[id]; !ok {
+		return store.ErrNotFound
+	}
+
+	delete(s.batches, id)
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/auth"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Owner returns the authenticated user of the request, which is recorded
+// with everything stored on its behalf.
+func Owner(ctx context.Context) string {
+	user, _ := ctx.Value(auth.UserContextKey).(string)
+	return user
+}
+
+// IsOwner reports whether the caller may access a record of the given owner.
+// Records of other users are reported as not found by the handlers.
+func IsOwner(ctx context.Context, owner string) bool {
+	return Owner(ctx) == owner
+}
+
+type Provider interface {
+	GetResponse(ctx context.Context, id string) (*Response, error)
+	SaveResponse(ctx context.Context, response *Response) error
+	DeleteResponse(ctx context.Context, id string) error
+
+	// ListFiles returns file metadata only; use GetFileContent for the data.
+	ListFiles(ctx context.Context) ([]File, error)
+	GetFile(ctx context.Context, id string) (*File, error)
+	GetFileContent(ctx context.Context, id string) ([]byte, error)
+	SaveFile(ctx context.Context, file *File, content []byte) error
+	DeleteFile(ctx context.Context, id string) error
+
+	ListBatches(ctx context.Context) ([]Batch, error)
+	GetBatch(ctx context.Context, id string) (*Batch, error)
+	SaveBatch(ctx context.Context, batch *Batch) error
+	DeleteBatch(ctx context.Context, id string) error
+}
+
+// Response is a persisted Responses API turn. Input, Output and Data hold the
+// wire-format JSON so the server can replay the turn without re-encoding.
+type Response struct {
+	ID         string `json:"id"`
+	PreviousID string `json:"previous_id,omitempty"`
+
+	// Owner is the user that created the turn
+	Owner string `json:"owner,omitempty"`
+
+	Model string `json:"model,omitempty"`
+
+	// Input is the JSON array of input items sent with this turn.
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// Output is the JSON array of output items produced by this turn.
+	Output json.RawMessage `json:"output,omitempty"`
+
+	// Data is the complete response object as returned to the client.
+	Data json.RawMessage `json:"data,omitempty"`
+
+	// Events is the JSON array of stream events of a background turn, so
+	// its stream can be resumed once it finished.
+	Events json.RawMessage `json:"events,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// File is an uploaded or generated file, e.g. batch input and output.
+type File struct {
+	ID string `json:"id"`
+
+	// Owner is the user that uploaded the file or created its batch
+	Owner string `json:"owner,omitempty"`
+
+	Name    string `json:"name"`
+	Purpose string `json:"purpose,omitempty"`
+
+	Size int64 `json:"size"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Batch is a persisted batch job. Type tells the API dialect that owns it
+// ("openai", "anthropic"); Data holds that dialect's wire-format object.
+type Batch struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+
+	// Owner is the user that created the batch
+	Owner string `json:"owner,omitempty"`
+
+	Data json.RawMessage `json:"data,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,270 @@
+package extract
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/scraper"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+const ToolName = "extract_text"
+
+const (
+	defaultMaxChars = 64 * 1024
+
+	maxFileSize = 32 << 20
+)
+
+var (
+	_ tool.Provider = (*Client)(nil)
+	_ tool.Resulter = (*Client)(nil)
+)
+
+// Client returns the text of a document, either downloaded from a URL or
+// passed inline, using the configured extractor.
+type Client struct {
+	extractor extractor.Provider
+	scraper   scraper.Provider
+
+	client *http.Client
+
+	maxChars int
+}
+
+func New(extractor extractor.Provider, options ...Option) (*Client, error) {
+	if extractor == nil {
+		return nil, errors.New("extract: missing extractor provider")
+	}
+
+	c := &Client{
+		extractor: extractor,
+
+		client: provider.DefaultClient,
+
+		maxChars: defaultMaxChars,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	return []tool.Tool{
+		{
+			Name:        ToolName,
+			Description: "Extract the text of a document (PDF, Office, image, HTML, ...) given either its URL or its content. Use it to read attachments and linked files. Long documents are truncated.",
+
+			Parameters: map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"url": map[string]any{
+						"type":        "string",
+						"description": "The absolute http(s) URL of the document. Omit when passing file.",
+					},
+					"file": map[string]any{
+						"type":        "string",
+						"description": "The document content as base64 or a data: URL. Omit when passing url.",
+					},
+					"name": map[string]any{
+						"type":        "string",
+						"description": "The file name including its extension (e.g. 'report.pdf'); helps detect the document type of an inline file.",
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	if name != ToolName {
+		return nil, tool.ErrInvalidTool
+	}
+
+	rawURL, _ := parameters["url"].(string)
+	rawURL = strings.TrimSpace(rawURL)
+
+	data, _ := parameters["file"].(string)
+	data = strings.TrimSpace(data)
+
+	fileName, _ := parameters["name"].(string)
+	fileName = strings.TrimSpace(fileName)
+
+	var source, text string
+
+	switch {
+	case data != "":
+		file, err := decodeFile(fileName, data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := c.extractor.Extract(ctx, *file, &extractor.ExtractOptions{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		source = file.Name
+		text = doc.Text
+
+	case rawURL != "":
+		u, err := url.Parse(rawURL)
+
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("extract: invalid url %q", rawURL)
+		}
+
+		if c.scraper != nil {
+			doc, err := c.scraper.Scrape(ctx, rawURL, &scraper.ScrapeOptions{})
+
+			if err != nil {
+				return nil, err
+			}
+
+			source = rawURL
+			text = doc.Text
+
+			break
+		}
+
+		file, err := c.download(ctx, u)
+
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := c.extractor.Extract(ctx, *file, &extractor.ExtractOptions{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		source = rawURL
+		text = doc.Text
+
+	default:
+		return nil, errors.New("extract: missing url or file parameter")
+	}
+
+	if runes := []rune(text); c.maxChars > 0 && len(runes) > c.maxChars {
+		text = string(runes[:c.maxChars]) + fmt.Sprintf("\n\n[Truncated: showing %d of %d characters.]", c.maxChars, len(runes))
+	}
+
+	var b strings.Builder
+
+	if source != "" {
+		fmt.Fprintf(&b, "Source: %s\n\n", source)
+	}
+
+	b.WriteString(text)
+
+	return b.String(), nil
+}
+
+func (c *Client) Result(name string, value any) provider.ToolResult {
+	text, _ := value.(string)
+
+	return provider.ToolResult{
+		Parts: []provider.Part{{Text: text}},
+	}
+}
+
+func (c *Client) download(ctx context.Context, u *url.URL) (*provider.File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("extract: fetching %s failed: %s", u, resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > maxFileSize {
+		return nil, errors.New("extract: document too large")
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	return &provider.File{
+		Name: path.Base(u.Path),
+
+		Content:     content,
+		ContentType: contentType,
+	}, nil
+}
+
+// decodeFile accepts plain base64 or a data: URL.
+func decodeFile(name, data string) (*provider.File, error) {
+	var contentType string
+
+	if rest, ok := strings.CutPrefix(data, "data:"); ok {
+		header, payload, ok := strings.Cut(rest, ",")
+
+		if !ok || !strings.HasSuffix(header, ";base64") {
+			return nil, errors.New("extract: invalid data url")
+		}
+
+		contentType = strings.TrimSuffix(header, ";base64")
+		data = payload
+	}
+
+	content, err := base64.StdEncoding.DecodeString(data)
+
+	if err != nil {
+		return nil, errors.New("extract: file is not valid base64")
+	}
+
+	if len(content) > maxFileSize {
+		return nil, errors.New("extract: document too large")
+	}
+
+	if contentType == "" && name != "" {
+		contentType, _, _ = mime.ParseMediaType(mime.TypeByExtension(path.Ext(name)))
+	}
+
+	if name == "" {
+		name = "file"
+
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+
+	return &provider.File{
+		Name: name,
+
+		Content:     content,
+		ContentType: contentType,
+	}, nil
+}
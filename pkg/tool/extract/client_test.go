@@ -0,0 +1,100 @@
+package extract
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/extractor"
+)
+
+type fakeExtractor struct {
+	file extractor.File
+}
+
+func (f *fakeExtractor) Extract(ctx context.Context, input extractor.File, options *extractor.ExtractOptions) (*extractor.Document, error) {
+	f.file = input
+	return &extractor.Document{Text: "hello " + string(input.Content)}, nil
+}
+
+func TestNew_RequiresExtractor(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Fatal("expected error when extractor is nil")
+	}
+}
+
+func TestExecute_InlineFile(t *testing.T) {
+	e := &fakeExtractor{}
+	c, _ := New(e)
+
+	data := "data:application/pdf;base64," + base64.StdEncoding.EncodeToString([]byte("world"))
+
+	result, err := c.Execute(t.Context(), ToolName, map[string]any{"file": data})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.file.ContentType != "application/pdf" || e.file.Name != "file.pdf" {
+		t.Errorf("file = %q (%s)", e.file.Name, e.file.ContentType)
+	}
+
+	if text := result.(string); !strings.HasSuffix(text, "hello world") {
+		t.Errorf("result = %q", text)
+	}
+}
+
+func TestExecute_InlineFileTypeFromName(t *testing.T) {
+	e := &fakeExtractor{}
+	c, _ := New(e)
+
+	data := base64.StdEncoding.EncodeToString([]byte("world"))
+
+	if _, err := c.Execute(t.Context(), ToolName, map[string]any{"file": data, "name": "notes.txt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.file.ContentType != "text/plain" || e.file.Name != "notes.txt" {
+		t.Errorf("file = %q (%s)", e.file.Name, e.file.ContentType)
+	}
+}
+
+func TestExecute_DownloadsURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("remote"))
+	}))
+	defer server.Close()
+
+	e := &fakeExtractor{}
+	c, _ := New(e, WithClient(server.Client()))
+
+	result, err := c.Execute(t.Context(), ToolName, map[string]any{"url": server.URL + "/docs/report.pdf"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.file.Name != "report.pdf" || e.file.ContentType != "application/pdf" {
+		t.Errorf("file = %q (%s)", e.file.Name, e.file.ContentType)
+	}
+
+	if text := result.(string); !strings.HasPrefix(text, "Source: "+server.URL) || !strings.HasSuffix(text, "hello remote") {
+		t.Errorf("result = %q", text)
+	}
+}
+
+func TestExecute_RejectsMissingInput(t *testing.T) {
+	c, _ := New(&fakeExtractor{})
+
+	if _, err := c.Execute(t.Context(), ToolName, map[string]any{}); err == nil {
+		t.Fatal("expected error without url or file")
+	}
+
+	if _, err := c.Execute(t.Context(), ToolName, map[string]any{"url": "file:///etc/passwd"}); err == nil {
+		t.Fatal("expected error for non-http url")
+	}
+}
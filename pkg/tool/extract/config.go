@@ -0,0 +1,31 @@
+package extract
+
+import (
+	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/scraper"
+)
+
+type Option func(*Client)
+
+// WithScraper fetches URLs through the scraper instead of downloading and
+// extracting them directly.
+func WithScraper(scraper scraper.Provider) Option {
+	return func(c *Client) {
+		c.scraper = scraper
+	}
+}
+
+func WithClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+func WithMaxChars(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxChars = n
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+const ToolName = "code_interpreter"
+
+const (
+	defaultTimeout = 60 * time.Second
+	defaultMemory  = 512 << 20
+
+	maxOutput   = 64 << 10
+	maxFiles    = 10
+	maxFileSize = 10 << 20
+)
+
+var (
+	_ tool.Provider = (*Client)(nil)
+	_ tool.Resulter = (*Client)(nil)
+)
+
+// Client runs model-supplied Python or shell code on the server. Each run
+// gets its own temporary working directory inside a private, read-only root
+// filesystem, an empty environment, CPU, memory and file size limits, a
+// wall-clock timeout and, unless enabled, no network.
+type Client struct {
+	python string
+
+	timeout time.Duration
+	memory  int64
+
+	network bool
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	ExitCode int
+	TimedOut bool
+
+	Stdout string
+	Stderr string
+
+	Files []provider.File
+}
+
+func New(options ...Option) (*Client, error) {
+	c := &Client{
+		python: "python3",
+
+		timeout: defaultTimeout,
+		memory:  defaultMemory,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	if err := checkSandbox(c.network); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	return []tool.Tool{
+		{
+			Name:        ToolName,
+			Description: "Run Python or shell code in an isolated sandbox without network access and return its output. Files written to the working directory are returned as attachments. Use it for calculations, data analysis and file conversions. State is not kept between runs.",
+
+			Parameters: map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"language": map[string]any{
+						"type":        "string",
+						"enum":        []string{"python", "shell"},
+						"description": "The language of the code. Defaults to python.",
+					},
+					"code": map[string]any{
+						"type":        "string",
+						"description": "The code to run. Print results to stdout.",
+					},
+				},
+
+				"required": []string{"code"},
+			},
+		},
+	}, nil
+}
+
+func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	if name != ToolName {
+		return nil, tool.ErrInvalidTool
+	}
+
+	code, _ := parameters["code"].(string)
+
+	if strings.TrimSpace(code) == "" {
+		return nil, errors.New("interpreter: missing code parameter")
+	}
+
+	language, _ := parameters["language"].(string)
+
+	if language == "" {
+		language = "python"
+	}
+
+	return c.Run(ctx, language, code)
+}
+
+// Run executes code of the given language ("python" or "shell").
+func (c *Client) Run(ctx context.Context, language, code string) (*Result, error) {
+	var script, interpreter string
+
+	switch strings.ToLower(language) {
+	case "python", "py":
+		script, interpreter = "main.py", c.python
+
+	case "shell", "sh", "bash":
+		script, interpreter = "main.sh", "/bin/sh"
+
+	default:
+		return nil, fmt.Errorf("interpreter: unsupported language %q", language)
+	}
+
+	dir, err := os.MkdirTemp("", "wingman-interpreter-")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	work, root, err := workspace(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(filepath.Join(work, script), []byte(code), 0600); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// rlimits are applied by the shell right before it execs the
+	// interpreter; -f counts 512 byte blocks in POSIX mode
+	limits := strings.Join([]string{
+		"ulimit -t " + strconv.Itoa(int(c.timeout.Seconds())+1),
+		"ulimit -v " + strconv.FormatInt(c.memory>>10, 10),
+		"ulimit -f " + strconv.Itoa(maxFileSize/512),
+		"ulimit -n 256",
+		`exec "$@"`,
+	}, " && ")
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", limits, "sh", interpreter, script)
+	cmd.Dir = work
+
+	cmd.Env = []string{
+		"PATH=/usr/local/bin:/usr/bin:/bin",
+		"HOME=" + work,
+		"TMPDIR=" + work,
+		"LANG=C.UTF-8",
+		"MPLBACKEND=Agg",
+		"PYTHONDONTWRITEBYTECODE=1",
+	}
+
+	stdout := &limitedBuffer{limit: maxOutput}
+	stderr := &limitedBuffer{limit: maxOutput}
+
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	cmd.WaitDelay = time.Second
+
+	sandbox(cmd, root, c.python, c.network)
+
+	err = cmd.Run()
+
+	result := &Result{
+		TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}
+
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil && !result.TimedOut {
+		return nil, err
+	}
+
+	result.Files = collectFiles(work, script)
+
+	return result, nil
+}
+
+// Result implements tool.Resulter: the output as text, produced files as
+// file parts.
+func (c *Client) Result(name string, value any) provider.ToolResult {
+	result, ok := value.(*Result)
+
+	if !ok {
+		return provider.ToolResult{}
+	}
+
+	var b strings.Builder
+
+	if result.TimedOut {
+		fmt.Fprintf(&b, "Timed out after %s.\n", c.timeout)
+	} else {
+		fmt.Fprintf(&b, "Exit code: %d\n", result.ExitCode)
+	}
+
+	if result.Stdout != "" {
+		fmt.Fprintf(&b, "\nstdout:\n%s\n", result.Stdout)
+	}
+
+	if result.Stderr != "" {
+		fmt.Fprintf(&b, "\nstderr:\n%s\n", result.Stderr)
+	}
+
+	for _, f := range result.Files {
+		fmt.Fprintf(&b, "\nfile: %s (%s, %d bytes)", f.Name, f.ContentType, len(f.Content))
+	}
+
+	parts := []provider.Part{
+		{Text: strings.TrimSpace(b.String())},
+	}
+
+	for _, f := range result.Files {
+		parts = append(parts, provider.Part{File: &f})
+	}
+
+	return provider.ToolResult{
+		Parts: parts,
+	}
+}
+
+// workspace creates the working directory of a run inside dir, and the
+// mount point of its sandboxed root filesystem next to it.
+func workspace(dir string) (work, root string, err error) {
+	work = filepath.Join(dir, "work")
+	root = filepath.Join(dir, "root")
+
+	for _, d := range []string{work, root} {
+		if err := os.Mkdir(d, 0700); err != nil {
+			return "", "", err
+		}
+	}
+
+	return work, root, nil
+}
+
+// collectFiles returns the files a run left in its working directory.
+func collectFiles(dir, script string) []provider.File {
+	var files []provider.File
+
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || len(files) >= maxFiles {
+			return nil
+		}
+
+		name, _ := filepath.Rel(dir, path)
+
+		if name == script || !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+
+		if err != nil || info.Size() > maxFileSize {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(name))
+
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+
+		files = append(files, provider.File{
+			Name: filepath.ToSlash(name),
+
+			Content:     data,
+			ContentType: contentType,
+		})
+
+		return nil
+	})
+
+	return files
+}
+
+// limitedBuffer keeps the first limit bytes written and notes the rest
+// as truncated.
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := b.limit - b.buf.Len(); n < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(n, 0)])
+	} else {
+		b.buf.Write(p)
+	}
+
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n[output truncated]"
+	}
+
+	return b.buf.String()
+}
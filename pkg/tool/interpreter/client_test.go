@@ -0,0 +1,132 @@
+package interpreter
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, options ...Option) *Client {
+	t.Helper()
+
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox requires linux")
+	}
+
+	// user namespaces may be disabled on the host
+	c, err := New(options...)
+
+	if err != nil {
+		t.Skip(err)
+	}
+
+	return c
+}
+
+func TestRunShell(t *testing.T) {
+	c := newTestClient(t)
+
+	result, err := c.Run(context.Background(), "shell", "echo hello; echo oops >&2; printf 'a,b\\n1,2\\n' > data.csv; exit 3")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.ExitCode != 3 || strings.TrimSpace(result.Stdout) != "hello" || strings.TrimSpace(result.Stderr) != "oops" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if len(result.Files) != 1 || result.Files[0].Name != "data.csv" || string(result.Files[0].Content) != "a,b\n1,2\n" {
+		t.Fatalf("expected data.csv, got %+v", result.Files)
+	}
+
+	parts := c.Result(ToolName, result).Parts
+
+	if len(parts) != 2 || parts[1].File == nil || !strings.Contains(parts[0].Text, "Exit code: 3") {
+		t.Fatalf("unexpected tool result: %+v", parts)
+	}
+}
+
+func TestRunPython(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not installed")
+	}
+
+	c := newTestClient(t)
+
+	result, err := c.Run(context.Background(), "python", "print(sum(range(10)))")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.ExitCode != 0 || strings.TrimSpace(result.Stdout) != "45" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestRunWithoutNetwork(t *testing.T) {
+	c := newTestClient(t)
+
+	result, err := c.Run(context.Background(), "shell", "tail -n +3 /proc/net/dev | cut -d: -f1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.TrimSpace(result.Stdout) != "lo" {
+		t.Fatalf("expected loopback only, got %q", result.Stdout)
+	}
+}
+
+func TestRunIsolated(t *testing.T) {
+	c := newTestClient(t)
+
+	secret := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(secret, []byte("token: secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WINGMAN_TEST_TOKEN", "secret")
+
+	result, err := c.Run(context.Background(), "shell", "cat "+secret+"; env; touch /usr/escaped; cat /proc/1/environ")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(result.Stdout, "secret") {
+		t.Fatalf("expected host files and environment to be hidden, got %q", result.Stdout)
+	}
+
+	if _, err := os.Stat("/usr/escaped"); err == nil {
+		os.Remove("/usr/escaped")
+		t.Fatal("expected system directories to be read-only")
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	c := newTestClient(t, WithTimeout(500*time.Millisecond))
+
+	started := time.Now()
+
+	result, err := c.Run(context.Background(), "shell", "sleep 30 & sleep 30")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.TimedOut {
+		t.Fatalf("expected timeout, got %+v", result)
+	}
+
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected the process group to be killed, took %s", elapsed)
+	}
+}
@@ -0,0 +1,36 @@
+package interpreter
+
+import (
+	"time"
+)
+
+type Option func(*Client)
+
+// WithPython sets the Python executable. Defaults to python3
+func WithPython(path string) Option {
+	return func(c *Client) {
+		c.python = path
+	}
+}
+
+// WithTimeout bounds the wall-clock (and CPU) time of a run. Defaults to 60s
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithMemory caps the address space of a run in bytes. Defaults to 512 MiB
+func WithMemory(bytes int64) Option {
+	return func(c *Client) {
+		c.memory = bytes
+	}
+}
+
+// WithNetwork allows runs to access the network. By default they run in an
+// empty network namespace.
+func WithNetwork(enabled bool) Option {
+	return func(c *Client) {
+		c.network = enabled
+	}
+}
@@ -0,0 +1,341 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// sandboxEnv hands the sandbox of a run to the helper process: the server
+// binary itself, re-executed in fresh namespaces, which builds the private
+// root filesystem and then execs the interpreter.
+const sandboxEnv = "WINGMAN_INTERPRETER_SANDBOX"
+
+// systemMounts are the host paths a run sees, read-only. Everything else,
+// including the server's config, working directory and home, is absent.
+var systemMounts = []string{
+	"/usr",
+	"/bin",
+	"/sbin",
+	"/lib",
+	"/lib32",
+	"/lib64",
+
+	"/etc/alternatives",
+	"/etc/ca-certificates",
+	"/etc/ssl",
+	"/etc/ld.so.cache",
+	"/etc/localtime",
+	"/etc/hosts",
+	"/etc/nsswitch.conf",
+	"/etc/resolv.conf",
+}
+
+var deviceMounts = []string{
+	"/dev/null",
+	"/dev/zero",
+	"/dev/full",
+	"/dev/random",
+	"/dev/urandom",
+}
+
+// statfs flags a bind remount has to keep, see statfs(2)
+var lockedFlags = map[int64]uintptr{
+	0x0002: syscall.MS_NOSUID,
+	0x0004: syscall.MS_NODEV,
+	0x0008: syscall.MS_NOEXEC,
+	0x0400: syscall.MS_NOATIME,
+	0x0800: syscall.MS_NODIRATIME,
+	0x1000: syscall.MS_RELATIME,
+}
+
+type sandboxSpec struct {
+	Path string `json:"path"`
+
+	Root string `json:"root"`
+	Work string `json:"work"`
+
+	Mounts []string `json:"mounts,omitempty"`
+}
+
+func init() {
+	data, ok := os.LookupEnv(sandboxEnv)
+
+	if !ok {
+		return
+	}
+
+	os.Unsetenv(sandboxEnv)
+
+	var spec sandboxSpec
+
+	err := json.Unmarshal([]byte(data), &spec)
+
+	if err == nil {
+		err = enterSandbox(spec)
+	}
+
+	if err == nil {
+		err = syscall.Exec(spec.Path, os.Args, os.Environ())
+	}
+
+	fmt.Fprintln(os.Stderr, "interpreter: sandbox:", err)
+	os.Exit(126)
+}
+
+// checkSandbox runs a no-op in the sandbox, so a host without user, mount
+// and pid namespaces fails at startup instead of running code unconfined.
+func checkSandbox(network bool) error {
+	dir, err := os.MkdirTemp("", "wingman-interpreter-")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(dir)
+
+	work, root, err := workspace(dir)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", "true")
+	cmd.Dir = work
+	cmd.Env = []string{}
+	cmd.Stderr = &stderr
+
+	sandbox(cmd, root, "", network)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("interpreter: sandbox unavailable: %w %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// sandbox runs cmd as root of new user, mount and pid namespaces, and
+// without network in a new network namespace. Its root filesystem is a
+// tmpfs mounted on root holding read-only system directories, a few
+// devices, its own /proc and cmd.Dir as the only writable directory. The
+// process group is killed as a whole on timeout.
+func sandbox(cmd *exec.Cmd, root, python string, network bool) {
+	spec, _ := json.Marshal(sandboxSpec{
+		Path: cmd.Path,
+
+		Root: root,
+		Work: cmd.Dir,
+
+		Mounts: pythonMounts(python),
+	})
+
+	cmd.Path = "/proc/self/exe"
+	cmd.Env = append(cmd.Env, sandboxEnv+"="+string(spec))
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID,
+
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+	}
+
+	if !network {
+		cmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWNET
+	}
+
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// pythonMounts returns the installation prefix of a python given by path,
+// e.g. a virtual environment or a build under /opt, which the sandbox has
+// to provide next to the system directories. Bare names are looked up in
+// the sandbox's PATH.
+func pythonMounts(python string) []string {
+	if !filepath.IsAbs(python) {
+		return nil
+	}
+
+	paths := []string{python}
+
+	if resolved, err := filepath.EvalSymlinks(python); err == nil {
+		paths = append(paths, resolved)
+	}
+
+	var mounts []string
+
+	for _, path := range paths {
+		prefix := filepath.Dir(filepath.Dir(path))
+
+		if prefix == "/" || isSystemPath(prefix) {
+			continue
+		}
+
+		mounts = append(mounts, prefix)
+	}
+
+	return mounts
+}
+
+func isSystemPath(path string) bool {
+	for _, m := range systemMounts {
+		if path == m || strings.HasPrefix(path, m+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enterSandbox builds the root filesystem of a run and pivots into it.
+func enterSandbox(spec sandboxSpec) error {
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return err
+	}
+
+	if err := syscall.Mount("tmpfs", spec.Root, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "size=1m,mode=0755"); err != nil {
+		return err
+	}
+
+	for _, path := range append(systemMounts, spec.Mounts...) {
+		if err := bindMount(spec.Root, path, true); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range deviceMounts {
+		if err := bindMount(spec.Root, path, false); err != nil {
+			return err
+		}
+	}
+
+	if err := bindMount(spec.Root, spec.Work, false); err != nil {
+		return err
+	}
+
+	proc := filepath.Join(spec.Root, "proc")
+
+	if err := os.Mkdir(proc, 0755); err != nil {
+		return err
+	}
+
+	if err := syscall.Mount("proc", proc, "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		return err
+	}
+
+	old := filepath.Join(spec.Root, ".old")
+
+	if err := os.Mkdir(old, 0700); err != nil {
+		return err
+	}
+
+	if err := syscall.PivotRoot(spec.Root, old); err != nil {
+		return err
+	}
+
+	if err := syscall.Chdir("/"); err != nil {
+		return err
+	}
+
+	if err := syscall.Unmount("/.old", syscall.MNT_DETACH); err != nil {
+		return err
+	}
+
+	if err := os.Remove("/.old"); err != nil {
+		return err
+	}
+
+	if err := syscall.Mount("", "/", "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV, ""); err != nil {
+		return err
+	}
+
+	return syscall.Chdir(spec.Work)
+}
+
+// bindMount makes the host path visible at the same path below root.
+// Symlinks to directories, like /bin on merged /usr systems, are recreated
+// as such; missing paths are skipped.
+func bindMount(root, path string, readonly bool) error {
+	info, err := os.Lstat(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(root, path)
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+			link, err := os.Readlink(path)
+
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		}
+
+		if info, err = os.Stat(path); err != nil {
+			return nil
+		}
+	}
+
+	if info.IsDir() {
+		err = os.MkdirAll(target, 0755)
+	} else {
+		err = os.WriteFile(target, nil, 0644)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := syscall.Mount(path, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return err
+	}
+
+	if !readonly {
+		return nil
+	}
+
+	var st syscall.Statfs_t
+
+	if err := syscall.Statfs(target, &st); err != nil {
+		return err
+	}
+
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY | syscall.MS_NOSUID)
+
+	for f, m := range lockedFlags {
+		if int64(st.Flags)&f != 0 {
+			flags |= m
+		}
+	}
+
+	return syscall.Mount("", target, "", flags, "")
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package interpreter
+
+import (
+	"errors"
+	"os/exec"
+)
+
+func checkSandbox(network bool) error {
+	return errors.New("interpreter: sandbox requires linux namespaces")
+}
+
+func sandbox(cmd *exec.Cmd, root, python string, network bool) {
+}
@@ -69,13 +69,13 @@ func BenchmarkExecute_ReusedSession(b *testing.B) {
 
 	ctx := context.Background()
 
-	session, err := c.createSession(ctx)
+	session, release, err := c.createSession(ctx)
 
 	if err != nil {
 		b.Fatal(err)
 	}
 
-	defer session.Close()
+	defer release()
 
 	b.ResetTimer()
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/mcp/stdio"
 	"github.com/adrianliechti/wingman/pkg/provider"
 	"github.com/adrianliechti/wingman/pkg/tool"
 
@@ -23,9 +24,19 @@ var (
 
 type Client struct {
 	transport mcp.Transport
+
+	process *stdio.Process
+
+	sse bool
 }
 
-func New(url string, headers map[string]string, exchanger auth.TokenExchanger) (*Client, error) {
+func New(url string, headers map[string]string, exchanger auth.TokenExchanger, options ...Option) (*Client, error) {
+	c := &Client{}
+
+	for _, option := range options {
+		option(c)
+	}
+
 	hc := &http.Client{
 		Transport: &rt{
 			headers:   headers,
@@ -34,29 +45,50 @@ func New(url string, headers map[string]string, exchanger auth.TokenExchanger) (
 		},
 	}
 
-	var tr mcp.Transport = &mcp.StreamableClientTransport{
+	c.transport = &mcp.StreamableClientTransport{
 		Endpoint: url,
 
 		HTTPClient: hc,
 		MaxRetries: -1,
 	}
 
-	if strings.Contains(strings.ToLower(url), "/sse") {
-		tr = &mcp.SSEClientTransport{
+	if c.sse || strings.Contains(strings.ToLower(url), "/sse") {
+		c.transport = &mcp.SSEClientTransport{
 			Endpoint: url,
 
 			HTTPClient: hc,
 		}
 	}
 
+	return c, nil
+}
+
+// NewCommand talks to a local MCP server over stdio. The process is shared
+// by all calls, up to its concurrency limit.
+func NewCommand(process *stdio.Process, options ...Option) (*Client, error) {
+	if process == nil {
+		return nil, errors.New("mcp: missing process")
+	}
+
 	c := &Client{
-		transport: tr,
+		process: process,
+	}
+
+	for _, option := range options {
+		option(c)
 	}
 
 	return c, nil
 }
 
-func (c *Client) createSession(ctx context.Context) (*mcp.ClientSession, error) {
+// createSession returns a session and the function to call when done with
+// it: remote servers get a session per call, a local process keeps its
+// session.
+func (c *Client) createSession(ctx context.Context) (*mcp.ClientSession, func(), error) {
+	if c.process != nil {
+		return c.process.Session(ctx)
+	}
+
 	impl := &mcp.Implementation{
 		Name:    "wingman",
 		Version: "1.0.0",
@@ -67,17 +99,24 @@ func (c *Client) createSession(ctx context.Context) (*mcp.ClientSession, error)
 	}
 
 	client := mcp.NewClient(impl, opts)
-	return client.Connect(ctx, c.transport, nil)
+
+	session, err := client.Connect(ctx, c.transport, nil)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, func() { session.Close() }, nil
 }
 
 func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
-	session, err := c.createSession(ctx)
+	session, release, err := c.createSession(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
-	defer session.Close()
+	defer release()
 
 	var result []tool.Tool
 
@@ -101,13 +140,13 @@ func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
 }
 
 func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
-	session, err := c.createSession(ctx)
+	session, release, err := c.createSession(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
-	defer session.Close()
+	defer release()
 
 	result, err := session.CallTool(ctx, &mcp.CallToolParams{
 		Name:      name,
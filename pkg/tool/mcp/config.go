@@ -1,3 +1,11 @@
 package mcp
 
 type Option func(*Client)
+
+// WithSSE uses the legacy HTTP+SSE transport instead of streamable HTTP.
+// URLs containing "/sse" select it automatically.
+func WithSSE() Option {
+	return func(c *Client) {
+		c.sse = true
+	}
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+const ToolName = "generate_image"
+
+var (
+	_ tool.Provider = (*Client)(nil)
+	_ tool.Resulter = (*Client)(nil)
+)
+
+// Client generates images from a text prompt with the configured renderer.
+type Client struct {
+	provider provider.Renderer
+}
+
+func New(provider provider.Renderer, options ...Option) (*Client, error) {
+	if provider == nil {
+		return nil, errors.New("render: missing renderer provider")
+	}
+
+	c := &Client{
+		provider: provider,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	return []tool.Tool{
+		{
+			Name:        ToolName,
+			Description: "Generate an image from a detailed text description. The image is returned as a file attachment.",
+
+			Parameters: map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"prompt": map[string]any{
+						"type":        "string",
+						"description": "A detailed description of the image: subject, style, composition, colors and any text it should contain.",
+					},
+					"aspect_ratio": map[string]any{
+						"type":        "string",
+						"description": "The aspect ratio of the image. Defaults to 1:1.",
+						"enum":        []string{"1:1", "2:3", "3:2", "3:4", "4:3", "9:16", "16:9"},
+					},
+				},
+
+				"required": []string{"prompt"},
+			},
+		},
+	}, nil
+}
+
+func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	if name != ToolName {
+		return nil, tool.ErrInvalidTool
+	}
+
+	prompt, _ := parameters["prompt"].(string)
+
+	if strings.TrimSpace(prompt) == "" {
+		return nil, errors.New("render: missing prompt parameter")
+	}
+
+	options := &provider.RenderOptions{}
+
+	if aspect, ok := parameters["aspect_ratio"].(string); ok {
+		options.Aspect = provider.ParseAspect(aspect)
+	}
+
+	return c.provider.Render(ctx, prompt, options)
+}
+
+// Result implements tool.Resulter: a short description and the image as a
+// file part.
+func (c *Client) Result(name string, value any) provider.ToolResult {
+	rendering, ok := value.(*provider.Rendering)
+
+	if !ok {
+		return provider.ToolResult{}
+	}
+
+	file := provider.File{
+		Name: "image",
+
+		Content:     rendering.Content,
+		ContentType: rendering.ContentType,
+	}
+
+	if exts, _ := mime.ExtensionsByType(file.ContentType); len(exts) > 0 {
+		file.Name += exts[0]
+	}
+
+	return provider.ToolResult{
+		Parts: []provider.Part{
+			{Text: fmt.Sprintf("Generated image: %s (%s, %d bytes)", file.Name, file.ContentType, len(file.Content))},
+			{File: &file},
+		},
+	}
+}
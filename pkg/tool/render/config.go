@@ -0,0 +1,3 @@
+package render
+
+type Option func(*Client)
@@ -0,0 +1,231 @@
+package retrieve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+const ToolName = "retrieve"
+
+// candidateFactor widens the index query when a reranker picks the best
+// matches from the candidates.
+const candidateFactor = 4
+
+var (
+	_ tool.Provider = (*Client)(nil)
+	_ tool.Resulter = (*Client)(nil)
+)
+
+// Client searches a configured index and returns the matching chunks with
+// their sources so the model can cite them.
+type Client struct {
+	index    index.Provider
+	reranker provider.Reranker
+
+	limit int
+
+	description string
+}
+
+func New(index index.Provider, options ...Option) (*Client, error) {
+	if index == nil {
+		return nil, errors.New("retrieve: missing index provider")
+	}
+
+	c := &Client{
+		index: index,
+		limit: 5,
+
+		description: "Search the internal knowledge base and return the most relevant document excerpts with their sources. Use it for questions about internal documents, policies or products. Cite the sources you use by their [n] number.",
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	return []tool.Tool{
+		{
+			Name:        ToolName,
+			Description: c.description,
+
+			Parameters: map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"query": map[string]any{
+						"type":        "string",
+						"description": "A natural language description of the information needed.",
+					},
+					"max_results": map[string]any{
+						"type":        "integer",
+						"minimum":     1,
+						"maximum":     20,
+						"description": fmt.Sprintf("Maximum number of excerpts to return. Defaults to %d.", c.limit),
+					},
+				},
+
+				"required": []string{"query"},
+			},
+		},
+	}, nil
+}
+
+func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	if name != ToolName {
+		return nil, tool.ErrInvalidTool
+	}
+
+	query, _ := parameters["query"].(string)
+	query = strings.TrimSpace(query)
+
+	if query == "" {
+		return nil, errors.New("retrieve: missing query parameter")
+	}
+
+	limit := c.limit
+
+	if n, ok := parameters["max_results"].(float64); ok {
+		limit = min(max(int(n), 1), 20)
+	}
+
+	candidates := limit
+
+	if c.reranker != nil {
+		candidates = limit * candidateFactor
+	}
+
+	results, err := c.index.Query(ctx, query, &index.QueryOptions{
+		Limit: &candidates,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.reranker != nil && len(results) > 1 {
+		results, err = c.rerank(ctx, query, results, limit)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return formatResults(results), nil
+}
+
+// Result implements tool.Resulter so the agent chain sees the same markdown
+// the MCP server emits.
+func (c *Client) Result(name string, value any) provider.ToolResult {
+	text, _ := value.(string)
+
+	return provider.ToolResult{
+		Parts: []provider.Part{{Text: text}},
+	}
+}
+
+// rerank orders the results by the reranker's scores. Rankings only carry
+// the text, so they are mapped back to the results by content.
+func (c *Client) rerank(ctx context.Context, query string, results []index.Result, limit int) ([]index.Result, error) {
+	texts := make([]string, len(results))
+	pending := make(map[string][]int)
+
+	for i, r := range results {
+		texts[i] = r.Content
+		pending[r.Content] = append(pending[r.Content], i)
+	}
+
+	rankings, err := c.reranker.Rerank(ctx, query, texts, &provider.RerankOptions{
+		Limit: &limit,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(rankings, func(a, b provider.Ranking) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+
+		return 0
+	})
+
+	reranked := make([]index.Result, 0, len(results))
+
+	for _, r := range rankings {
+		indexes := pending[r.Text]
+
+		if len(indexes) == 0 {
+			continue
+		}
+
+		result := results[indexes[0]]
+		result.Score = float32(r.Score)
+
+		reranked = append(reranked, result)
+		pending[r.Text] = indexes[1:]
+	}
+
+	return reranked, nil
+}
+
+func formatResults(results []index.Result) string {
+	if len(results) == 0 {
+		return "No matching documents."
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Found %d excerpt(s):\n", len(results))
+
+	for i, r := range results {
+		title := r.Title
+
+		if title == "" {
+			title = r.Source
+		}
+
+		if title == "" {
+			title = r.ID
+		}
+
+		fmt.Fprintf(&b, "\n[%d] %s\n", i+1, title)
+
+		if r.Source != "" && r.Source != title {
+			fmt.Fprintf(&b, "Source: %s\n", r.Source)
+		}
+
+		if len(r.Metadata) > 0 {
+			var pairs []string
+
+			for _, key := range slices.Sorted(maps.Keys(r.Metadata)) {
+				pairs = append(pairs, key+"="+r.Metadata[key])
+			}
+
+			fmt.Fprintf(&b, "Metadata: %s\n", strings.Join(pairs, ", "))
+		}
+
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(r.Content))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package retrieve
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type fakeIndex struct {
+	limit   int
+	results []index.Result
+}
+
+func (f *fakeIndex) List(ctx context.Context, options *index.ListOptions) ([]index.Document, error) {
+	return nil, nil
+}
+
+func (f *fakeIndex) Index(ctx context.Context, documents ...index.Document) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeIndex) Delete(ctx context.Context, ids ...string) error {
+	return nil
+}
+
+func (f *fakeIndex) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	f.limit = *options.Limit
+	return f.results, nil
+}
+
+// fakeReranker scores texts by their length
+type fakeReranker struct{}
+
+func (fakeReranker) Rerank(ctx context.Context, query string, texts []string, options *provider.RerankOptions) ([]provider.Ranking, error) {
+	var rankings []provider.Ranking
+
+	for _, t := range texts {
+		rankings = append(rankings, provider.Ranking{Text: t, Score: float64(len(t))})
+	}
+
+	return rankings, nil
+}
+
+func testResults() []index.Result {
+	return []index.Result{
+		{Document: index.Document{ID: "a", Title: "Handbook", Source: "https://intranet/handbook", Content: "short"}, Score: 0.9},
+		{Document: index.Document{ID: "b", Source: "policy.pdf", Content: "a much longer excerpt", Metadata: map[string]string{"page": "3"}}, Score: 0.8},
+	}
+}
+
+func TestNew_RequiresIndex(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Fatal("expected error when index is nil")
+	}
+}
+
+func TestExecute_FormatsCitations(t *testing.T) {
+	idx := &fakeIndex{results: testResults()}
+	c, _ := New(idx)
+
+	result, err := c.Execute(t.Context(), ToolName, map[string]any{"query": "leave policy"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := result.(string)
+
+	for _, want := range []string{"[1] Handbook", "Source: https://intranet/handbook", "[2] policy.pdf", "Metadata: page=3"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result lacks %q:\n%s", want, text)
+		}
+	}
+
+	if idx.limit != 5 {
+		t.Errorf("index limit = %d, want 5", idx.limit)
+	}
+}
+
+func TestExecute_Reranks(t *testing.T) {
+	idx := &fakeIndex{results: testResults()}
+	c, _ := New(idx, WithReranker(fakeReranker{}), WithLimit(1))
+
+	result, err := c.Execute(t.Context(), ToolName, map[string]any{"query": "leave policy"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := result.(string)
+
+	if !strings.Contains(text, "[1] policy.pdf") || strings.Contains(text, "Handbook") {
+		t.Errorf("expected the reranked best match only:\n%s", text)
+	}
+
+	if idx.limit != candidateFactor {
+		t.Errorf("index limit = %d, want %d candidates", idx.limit, candidateFactor)
+	}
+}
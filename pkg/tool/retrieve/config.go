@@ -0,0 +1,31 @@
+package retrieve
+
+import (
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+type Option func(*Client)
+
+// WithReranker reorders the index matches by relevance before the best ones
+// are returned.
+func WithReranker(reranker provider.Reranker) Option {
+	return func(c *Client) {
+		c.reranker = reranker
+	}
+}
+
+func WithLimit(limit int) Option {
+	return func(c *Client) {
+		if limit > 0 {
+			c.limit = limit
+		}
+	}
+}
+
+// WithDescription replaces the tool description, e.g. to tell the model
+// which documents the index holds.
+func WithDescription(description string) Option {
+	return func(c *Client) {
+		c.description = description
+	}
+}
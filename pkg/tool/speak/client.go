@@ -0,0 +1,138 @@
+package speak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/tool"
+)
+
+const ToolName = "generate_speech"
+
+var (
+	_ tool.Provider = (*Client)(nil)
+	_ tool.Resulter = (*Client)(nil)
+)
+
+// Client turns text into spoken audio with the configured synthesizer.
+type Client struct {
+	provider provider.Synthesizer
+
+	voice string
+}
+
+func New(provider provider.Synthesizer, options ...Option) (*Client, error) {
+	if provider == nil {
+		return nil, errors.New("speak: missing synthesizer provider")
+	}
+
+	c := &Client{
+		provider: provider,
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	return []tool.Tool{
+		{
+			Name:        ToolName,
+			Description: "Convert text into spoken audio. Pass the exact words to speak. The audio is returned as a file attachment.",
+
+			Parameters: map[string]any{
+				"type": "object",
+
+				"properties": map[string]any{
+					"text": map[string]any{
+						"type":        "string",
+						"description": "The text to speak, verbatim.",
+					},
+					"voice": map[string]any{
+						"type":        "string",
+						"description": "Optional voice name supported by the speech model. Omit to use the default voice.",
+					},
+					"instructions": map[string]any{
+						"type":        "string",
+						"description": "Optional guidance on tone, pace or accent (e.g. 'calm and slow').",
+					},
+				},
+
+				"required": []string{"text"},
+			},
+		},
+	}, nil
+}
+
+func (c *Client) Execute(ctx context.Context, name string, parameters map[string]any) (any, error) {
+	if name != ToolName {
+		return nil, tool.ErrInvalidTool
+	}
+
+	text, _ := parameters["text"].(string)
+
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("speak: missing text parameter")
+	}
+
+	options := &provider.SynthesizeOptions{
+		Voice: c.voice,
+	}
+
+	if voice, _ := parameters["voice"].(string); strings.TrimSpace(voice) != "" {
+		options.Voice = strings.TrimSpace(voice)
+	}
+
+	if instructions, _ := parameters["instructions"].(string); instructions != "" {
+		options.Instructions = instructions
+	}
+
+	acc := provider.SynthesisAccumulator{}
+
+	for synthesis, err := range c.provider.Synthesize(ctx, text, options) {
+		if err != nil {
+			return nil, err
+		}
+
+		acc.Add(*synthesis)
+	}
+
+	result := acc.Result()
+
+	return &result, nil
+}
+
+// Result implements tool.Resulter: a short description and the audio as a
+// file part.
+func (c *Client) Result(name string, value any) provider.ToolResult {
+	synthesis, ok := value.(*provider.Synthesis)
+
+	if !ok {
+		return provider.ToolResult{}
+	}
+
+	file := provider.File{
+		Name: "speech",
+
+		Content:     synthesis.Content,
+		ContentType: synthesis.ContentType,
+	}
+
+	if exts, _ := mime.ExtensionsByType(file.ContentType); len(exts) > 0 {
+		file.Name += exts[0]
+	}
+
+	return provider.ToolResult{
+		Parts: []provider.Part{
+			{Text: fmt.Sprintf("Generated audio: %s (%s, %d bytes)", file.Name, file.ContentType, len(file.Content))},
+			{File: &file},
+		},
+	}
+}
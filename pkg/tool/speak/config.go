@@ -0,0 +1,10 @@
+package speak
+
+type Option func(*Client)
+
+// WithVoice sets the voice used when the model does not pick one.
+func WithVoice(voice string) Option {
+	return func(c *Client) {
+		c.voice = voice
+	}
+}
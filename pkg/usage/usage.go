@@ -0,0 +1,196 @@
+package usage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+// Default is the process-wide ledger the instrumented completers and
+// embedders book into. It outlives configuration reloads.
+var Default = New(90 * 24 * time.Hour)
+
+// Totals sums the usage and spend of a set of requests.
+type Totals struct {
+	Requests int64
+
+	InputTokens  int64
+	OutputTokens int64
+
+	ReasoningTokens int64
+
+	CacheReadInputTokens     int64
+	CacheCreationInputTokens int64
+
+	Cost float64
+}
+
+func (t *Totals) add(o Totals) {
+	t.Requests += o.Requests
+
+	t.InputTokens += o.InputTokens
+	t.OutputTokens += o.OutputTokens
+
+	t.ReasoningTokens += o.ReasoningTokens
+
+	t.CacheReadInputTokens += o.CacheReadInputTokens
+	t.CacheCreationInputTokens += o.CacheCreationInputTokens
+
+	t.Cost += o.Cost
+}
+
+// Report breaks the totals of a time window down by user, group and model.
+// Requests of users in several groups count towards each of them.
+type Report struct {
+	From time.Time
+	To   time.Time
+
+	Total Totals
+
+	Users  map[string]Totals
+	Groups map[string]Totals
+	Models map[string]Totals
+}
+
+// Ledger aggregates usage in memory into hourly buckets per user, groups
+// and model, and drops buckets older than its retention.
+type Ledger struct {
+	mu sync.Mutex
+
+	retention time.Duration
+	buckets   map[bucketKey]*Totals
+
+	now func() time.Time
+}
+
+type bucketKey struct {
+	hour int64
+
+	user   string
+	groups string
+
+	model string
+}
+
+func New(retention time.Duration) *Ledger {
+	return &Ledger{
+		retention: retention,
+		buckets:   map[bucketKey]*Totals{},
+
+		now: time.Now,
+	}
+}
+
+// Add books one request of model for the user in ctx.
+func (l *Ledger) Add(ctx context.Context, model string, usage *provider.Usage, cost float64) {
+	user, _ := ctx.Value(auth.UserContextKey).(string)
+	groups, _ := ctx.Value(auth.GroupsContextKey).([]string)
+
+	groups = slices.Clone(groups)
+	slices.Sort(groups)
+
+	totals := Totals{
+		Requests: 1,
+
+		Cost: cost,
+	}
+
+	if usage != nil {
+		totals.InputTokens = int64(usage.InputTokens)
+		totals.OutputTokens = int64(usage.OutputTokens)
+
+		totals.ReasoningTokens = int64(usage.ReasoningTokens)
+
+		totals.CacheReadInputTokens = int64(usage.CacheReadInputTokens)
+		totals.CacheCreationInputTokens = int64(usage.CacheCreationInputTokens)
+	}
+
+	now := l.now()
+
+	key := bucketKey{
+		hour: now.Unix() / 3600,
+
+		user:   user,
+		groups: strings.Join(groups, "\n"),
+
+		model: model,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+
+	if !ok {
+		l.prune(now)
+
+		bucket = &Totals{}
+		l.buckets[key] = bucket
+	}
+
+	bucket.add(totals)
+}
+
+// Query reports the usage booked between from and to at hourly
+// granularity. A non-empty user restricts the report to that user.
+func (l *Ledger) Query(from, to time.Time, user string) *Report {
+	report := &Report{
+		From: from,
+		To:   to,
+
+		Users:  map[string]Totals{},
+		Groups: map[string]Totals{},
+		Models: map[string]Totals{},
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, bucket := range l.buckets {
+		if key.hour < from.Unix()/3600 || key.hour > to.Unix()/3600 {
+			continue
+		}
+
+		if user != "" && key.user != user {
+			continue
+		}
+
+		report.Total.add(*bucket)
+
+		merge(report.Users, key.user, *bucket)
+		merge(report.Models, key.model, *bucket)
+
+		if key.groups != "" {
+			for group := range strings.SplitSeq(key.groups, "\n") {
+				merge(report.Groups, group, *bucket)
+			}
+		}
+	}
+
+	return report
+}
+
+func (l *Ledger) prune(now time.Time) {
+	if l.retention <= 0 {
+		return
+	}
+
+	oldest := now.Add(-l.retention).Unix() / 3600
+
+	for key := range l.buckets {
+		if key.hour < oldest {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func merge(m map[string]Totals, key string, totals Totals) {
+	t := m[key]
+	t.add(totals)
+	m[key] = t
+}
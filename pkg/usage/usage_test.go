@@ -0,0 +1,66 @@
+package usage
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+func TestLedger(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	l := New(48 * time.Hour)
+	l.now = func() time.Time { return now }
+
+	alice := context.WithValue(context.Background(), auth.UserContextKey, "alice")
+	alice = context.WithValue(alice, auth.GroupsContextKey, []string{"eng", "ops"})
+
+	bob := context.WithValue(context.Background(), auth.UserContextKey, "bob")
+	bob = context.WithValue(bob, auth.GroupsContextKey, []string{"eng"})
+
+	l.Add(alice, "gpt", &provider.Usage{InputTokens: 100, OutputTokens: 10}, 0.5)
+	l.Add(alice, "claude", &provider.Usage{InputTokens: 200, OutputTokens: 20}, 1.0)
+	l.Add(bob, "gpt", &provider.Usage{InputTokens: 300, OutputTokens: 30}, 1.5)
+
+	report := l.Query(now.Add(-time.Hour), now, "")
+
+	if report.Total.Requests != 3 || report.Total.InputTokens != 600 || math.Abs(report.Total.Cost-3.0) > 1e-9 {
+		t.Fatalf("unexpected total: %+v", report.Total)
+	}
+
+	if got := report.Users["alice"].Cost; math.Abs(got-1.5) > 1e-9 {
+		t.Fatalf("expected alice to spend 1.5, got %v", got)
+	}
+
+	if got := report.Groups["eng"].Requests; got != 3 {
+		t.Fatalf("expected 3 eng requests, got %d", got)
+	}
+
+	if got := report.Groups["ops"].Requests; got != 2 {
+		t.Fatalf("expected 2 ops requests, got %d", got)
+	}
+
+	if got := report.Models["gpt"].OutputTokens; got != 40 {
+		t.Fatalf("expected 40 gpt output tokens, got %d", got)
+	}
+
+	if own := l.Query(now.Add(-time.Hour), now, "bob"); own.Total.Requests != 1 || len(own.Users) != 1 {
+		t.Fatalf("expected only bob's usage, got %+v", own)
+	}
+
+	if earlier := l.Query(now.Add(-24*time.Hour), now.Add(-2*time.Hour), ""); earlier.Total.Requests != 0 {
+		t.Fatalf("expected no usage outside the window, got %+v", earlier.Total)
+	}
+
+	// buckets past the retention are dropped when new ones are created
+	now = now.Add(72 * time.Hour)
+	l.Add(bob, "gpt", nil, 0)
+
+	if all := l.Query(time.Time{}, now, ""); all.Total.Requests != 1 {
+		t.Fatalf("expected expired buckets to be pruned, got %+v", all.Total)
+	}
+}
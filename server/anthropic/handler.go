@@ -26,6 +26,14 @@ func New(cfg *config.Config) *Handler {
 func (h *Handler) Attach(r chi.Router) {
 	r.Post("/messages", h.handleMessages)
 	r.Post("/messages/count_tokens", h.handleCountTokens)
+
+	r.Get("/messages/batches", h.handleBatches)
+	r.Post("/messages/batches", h.handleBatchCreate)
+
+	r.Get("/messages/batches/{id}", h.handleBatch)
+	r.Delete("/messages/batches/{id}", h.handleBatchDelete)
+	r.Post("/messages/batches/{id}/cancel", h.handleBatchCancel)
+	r.Get("/messages/batches/{id}/results", h.handleBatchResults)
 }
 
 func writeJson(w http.ResponseWriter, v any) {
@@ -0,0 +1,569 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/batch"
+	"github.com/adrianliechti/wingman/pkg/store"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var errNoStore = errors.New("message batches require a configured store")
+
+var customIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// runningBatches maps the ids of executing batches to their runningBatch.
+// It outlives handler instances, so a batch started before a config reload
+// can still be canceled afterwards.
+var runningBatches sync.Map
+
+// runningBatch is the state of an executing batch. Its record is only
+// changed and saved with mu held, so a cancel request and the runner never
+// overwrite each other's updates.
+type runningBatch struct {
+	mu     sync.Mutex
+	record *batchRecord
+
+	cancel context.CancelFunc
+}
+
+// batchRecord is the persisted form of a batch.
+type batchRecord struct {
+	MessageBatch
+
+	ResultsFileID string `json:"results_file_id,omitempty"`
+}
+
+func generateBatchID() string {
+	return fmt.Sprintf("msgbatch_%s", generateID(24))
+}
+
+func (h *Handler) handleBatches(w http.ResponseWriter, r *http.Request) {
+	result := MessageBatchList{
+		Data: []MessageBatch{},
+	}
+
+	if h.Store == nil {
+		writeJson(w, result)
+		return
+	}
+
+	records, err := h.listBatches(r.Context())
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// newest first
+	slices.Reverse(records)
+
+	query := r.URL.Query()
+
+	if id := query.Get("after_id"); id != "" {
+		for i, b := range records {
+			if b.ID == id {
+				records = records[i+1:]
+				break
+			}
+		}
+	}
+
+	if id := query.Get("before_id"); id != "" {
+		for i, b := range records {
+			if b.ID == id {
+				records = records[:i]
+				break
+			}
+		}
+	}
+
+	limit := 20
+
+	if val, err := strconv.Atoi(query.Get("limit")); err == nil && val > 0 {
+		limit = min(val, 1000)
+	}
+
+	if len(records) > limit {
+		records = records[:limit]
+		result.HasMore = true
+	}
+
+	for _, b := range records {
+		result.Data = append(result.Data, b.toBatch(r))
+	}
+
+	if len(result.Data) > 0 {
+		result.FirstID = &result.Data[0].ID
+		result.LastID = &result.Data[len(result.Data)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleBatchCreate(w http.ResponseWriter, r *http.Request) {
+	if h.Store == nil {
+		writeError(w, http.StatusNotImplemented, errNoStore)
+		return
+	}
+
+	var req MessageBatchRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := validateBatchRequest(req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now().UTC()
+
+	record := &batchRecord{
+		MessageBatch: MessageBatch{
+			ID:   generateBatchID(),
+			Type: "message_batch",
+
+			ProcessingStatus: MessageBatchStatusInProgress,
+
+			RequestCounts: MessageBatchCounts{
+				Processing: len(req.Requests),
+			},
+
+			CreatedAt: now,
+			ExpiresAt: now.Add(24 * time.Hour),
+		},
+	}
+
+	if err := h.saveBatch(r.Context(), record); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := record.toBatch(r)
+
+	h.startBatch(r.Context(), record, req.Requests)
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleBatch(w http.ResponseWriter, r *http.Request) {
+	record, ok := h.loadBatch(w, r)
+
+	if !ok {
+		return
+	}
+
+	writeJson(w, record.toBatch(r))
+}
+
+func (h *Handler) handleBatchCancel(w http.ResponseWriter, r *http.Request) {
+	record, ok := h.loadBatch(w, r)
+
+	if !ok {
+		return
+	}
+
+	if record.ProcessingStatus != MessageBatchStatusInProgress {
+		writeError(w, http.StatusConflict, fmt.Errorf("cannot cancel batch with status %s", record.ProcessingStatus))
+		return
+	}
+
+	// The runner ends the batch once in-flight requests return; the cancel
+	// is saved before the runner is stopped, so only the runner writes the
+	// final state. Batches without a runner (e.g. after a restart) end right
+	// away.
+	if value, ok := runningBatches.Load(record.ID); ok {
+		run := value.(*runningBatch)
+
+		run.mu.Lock()
+
+		if run.record.ProcessingStatus != MessageBatchStatusInProgress {
+			run.mu.Unlock()
+
+			writeError(w, http.StatusConflict, fmt.Errorf("cannot cancel batch with status %s", run.record.ProcessingStatus))
+			return
+		}
+
+		run.record.ProcessingStatus = MessageBatchStatusCanceling
+		run.record.CancelInitiatedAt = new(time.Now().UTC())
+
+		err := h.saveBatch(r.Context(), run.record)
+		result := run.record.toBatch(r)
+
+		run.mu.Unlock()
+
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		run.cancel()
+
+		writeJson(w, result)
+		return
+	}
+
+	record.ProcessingStatus = MessageBatchStatusEnded
+	record.CancelInitiatedAt = new(time.Now().UTC())
+	record.EndedAt = record.CancelInitiatedAt
+
+	record.RequestCounts.Canceled += record.RequestCounts.Processing
+	record.RequestCounts.Processing = 0
+
+	if err := h.saveBatch(r.Context(), record); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, record.toBatch(r))
+}
+
+func (h *Handler) handleBatchResults(w http.ResponseWriter, r *http.Request) {
+	record, ok := h.loadBatch(w, r)
+
+	if !ok {
+		return
+	}
+
+	if record.ResultsFileID == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("results for batch %s are not available yet", record.ID))
+		return
+	}
+
+	content, err := h.Store.GetFileContent(r.Context(), record.ResultsFileID)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-jsonl")
+	w.Write(content)
+}
+
+func (h *Handler) handleBatchDelete(w http.ResponseWriter, r *http.Request) {
+	record, ok := h.loadBatch(w, r)
+
+	if !ok {
+		return
+	}
+
+	if record.ProcessingStatus != MessageBatchStatusEnded {
+		writeError(w, http.StatusConflict, fmt.Errorf("batch %s must be ended before it can be deleted", record.ID))
+		return
+	}
+
+	if record.ResultsFileID != "" {
+		if err := h.Store.DeleteFile(r.Context(), record.ResultsFileID); err != nil && !errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if err := h.Store.DeleteBatch(r.Context(), record.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, MessageBatchDeleted{
+		ID:   record.ID,
+		Type: "message_batch_deleted",
+	})
+}
+
+func validateBatchRequest(req MessageBatchRequest) error {
+	if len(req.Requests) == 0 {
+		return errors.New("requests: at least one request is required")
+	}
+
+	if len(req.Requests) > 100000 {
+		return errors.New("requests: a batch is limited to 100000 requests")
+	}
+
+	seen := map[string]bool{}
+
+	for i, item := range req.Requests {
+		if !customIDPattern.MatchString(item.CustomID) {
+			return fmt.Errorf("requests.%d.custom_id: must be 1-64 letters, digits, underscores or hyphens", i)
+		}
+
+		if seen[item.CustomID] {
+			return fmt.Errorf("requests.%d.custom_id: duplicate custom_id %q", i, item.CustomID)
+		}
+
+		seen[item.CustomID] = true
+
+		if len(item.Params) == 0 || item.Params[0] != '{' {
+			return fmt.Errorf("requests.%d.params: must be an object", i)
+		}
+	}
+
+	return nil
+}
+
+// startBatch runs the requests in the background through the regular
+// messages handler, so every model a completer resolves to can be batched.
+// They run with the creator's identity but outlive the create request.
+func (h *Handler) startBatch(ctx context.Context, record *batchRecord, items []MessageBatchRequestItem) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	run := &runningBatch{
+		record: record,
+		cancel: cancel,
+	}
+
+	runningBatches.Store(record.ID, run)
+
+	go func() {
+		defer runningBatches.Delete(record.ID)
+		defer cancel()
+
+		if err := h.runBatch(ctx, run, items); err != nil {
+			slog.Error("message batch failed", "batch", record.ID, "error", err)
+		}
+	}()
+}
+
+func (h *Handler) runBatch(ctx context.Context, run *runningBatch, items []MessageBatchRequestItem) error {
+	record := run.record
+
+	requests := make([]batch.Request, len(items))
+
+	for i, item := range items {
+		requests[i] = batch.Request{
+			ID: item.CustomID,
+
+			Method: http.MethodPost,
+			Path:   "/messages",
+
+			Body: batch.WithoutStream(item.Params),
+		}
+	}
+
+	results := make([]MessageBatchResult, len(items))
+
+	batch.Run(ctx, http.HandlerFunc(h.handleMessages), requests, batch.DefaultConcurrency, func(i int, response *batch.Response, err error) {
+		result := MessageBatchResult{
+			CustomID: items[i].CustomID,
+			Result:   toBatchResult(response, err),
+		}
+
+		run.mu.Lock()
+		defer run.mu.Unlock()
+
+		results[i] = result
+
+		record.RequestCounts.Processing--
+
+		switch result.Result.Type {
+		case "succeeded":
+			record.RequestCounts.Succeeded++
+		case "canceled":
+			record.RequestCounts.Canceled++
+		default:
+			record.RequestCounts.Errored++
+		}
+
+		h.saveBatch(ctx, record)
+	})
+
+	// Use a fresh context: ending the batch must not be skipped by the
+	// very cancellation it reports.
+	ctx = context.WithoutCancel(ctx)
+
+	var data bytes.Buffer
+
+	for _, result := range results {
+		line, _ := json.Marshal(result)
+
+		data.Write(line)
+		data.WriteByte('\n')
+	}
+
+	f := &store.File{
+		ID:    "file-" + uuid.NewString(),
+		Owner: store.Owner(ctx),
+
+		Name:    record.ID + "_results.jsonl",
+		Purpose: "batch_output",
+
+		Size: int64(data.Len()),
+
+		CreatedAt: time.Now(),
+	}
+
+	if err := h.Store.SaveFile(ctx, f, data.Bytes()); err != nil {
+		return err
+	}
+
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	record.ResultsFileID = f.ID
+
+	record.ProcessingStatus = MessageBatchStatusEnded
+	record.EndedAt = new(time.Now().UTC())
+
+	return h.saveBatch(ctx, record)
+}
+
+func toBatchResult(response *batch.Response, err error) MessageBatchResultItem {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return MessageBatchResultItem{Type: "canceled"}
+		}
+
+		return MessageBatchResultItem{
+			Type: "errored",
+
+			Error: &ErrorResponse{
+				Type:  "error",
+				Error: Error{Type: "api_error", Message: err.Error()},
+			},
+		}
+	}
+
+	if response.StatusCode == http.StatusOK {
+		return MessageBatchResultItem{
+			Type:    "succeeded",
+			Message: json.RawMessage(bytes.TrimSpace(response.Body)),
+		}
+	}
+
+	var result ErrorResponse
+
+	if err := json.Unmarshal(response.Body, &result); err != nil || result.Error.Type == "" {
+		result = ErrorResponse{
+			Type: "error",
+
+			Error: Error{
+				Type:    errorTypeForStatus(response.StatusCode),
+				Message: string(response.Body),
+			},
+		}
+	}
+
+	return MessageBatchResultItem{
+		Type:  "errored",
+		Error: &result,
+	}
+}
+
+// loadBatch reads the batch of the request path. Batches of other users are
+// reported as not found.
+func (h *Handler) loadBatch(w http.ResponseWriter, r *http.Request) (*batchRecord, bool) {
+	id := chi.URLParam(r, "id")
+
+	if h.Store == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("batch %s not found", id))
+		return nil, false
+	}
+
+	b, err := h.Store.GetBatch(r.Context(), id)
+
+	if err == nil && (b.Type != "anthropic" || !store.IsOwner(r.Context(), b.Owner)) {
+		err = store.ErrNotFound
+	}
+
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("batch %s not found", id))
+			return nil, false
+		}
+
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	var record batchRecord
+
+	if err := json.Unmarshal(b.Data, &record); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	return &record, true
+}
+
+// listBatches returns the batches of the calling user.
+func (h *Handler) listBatches(ctx context.Context) ([]batchRecord, error) {
+	items, err := h.Store.ListBatches(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []batchRecord
+
+	for _, item := range items {
+		if item.Type != "anthropic" || !store.IsOwner(ctx, item.Owner) {
+			continue
+		}
+
+		var record batchRecord
+
+		if err := json.Unmarshal(item.Data, &record); err != nil {
+			return nil, err
+		}
+
+		result = append(result, record)
+	}
+
+	return result, nil
+}
+
+func (h *Handler) saveBatch(ctx context.Context, record *batchRecord) error {
+	data, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	return h.Store.SaveBatch(ctx, &store.Batch{
+		ID:   record.ID,
+		Type: "anthropic",
+
+		Owner: store.Owner(ctx),
+
+		Data: data,
+
+		CreatedAt: record.CreatedAt,
+	})
+}
+
+// toBatch adds the absolute results URL once results are available.
+func (b batchRecord) toBatch(r *http.Request) MessageBatch {
+	batch := b.MessageBatch
+
+	if b.ResultsFileID != "" {
+		scheme := "http"
+
+		if r.TLS != nil {
+			scheme = "https"
+		}
+
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = proto
+		}
+
+		batch.ResultsURL = new(scheme + "://" + r.Host + "/v1/messages/batches/" + b.ID + "/results")
+	}
+
+	return batch
+}
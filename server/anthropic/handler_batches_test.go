@@ -0,0 +1,261 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/store/memory"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const batchTestModel = "batch-test-model"
+
+type echoCompleter struct{}
+
+func (echoCompleter) Complete(_ context.Context, messages []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		yield(&provider.Completion{
+			Status: provider.CompletionStatusCompleted,
+			Message: &provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: []provider.Content{provider.TextContent("echo: " + messages[len(messages)-1].Text())},
+			},
+		}, nil)
+	}
+}
+
+// blockingCompleter answers once its request is canceled.
+type blockingCompleter struct{}
+
+func (blockingCompleter) Complete(ctx context.Context, _ []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		<-ctx.Done()
+		yield(nil, ctx.Err())
+	}
+}
+
+func newBatchRouter(t *testing.T) chi.Router {
+	t.Helper()
+
+	return newBatchRouterWith(t, echoCompleter{})
+}
+
+func newBatchRouterWith(t *testing.T, completer provider.Completer) chi.Router {
+	t.Helper()
+
+	cfg := &config.Config{Policy: noop.New(), Store: memory.New()}
+	cfg.RegisterCompleter(batchTestModel, completer)
+
+	r := chi.NewRouter()
+	New(cfg).Attach(r)
+
+	return r
+}
+
+func serveBatch(t *testing.T, r chi.Router, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func decodeMessageBatch(t *testing.T, rec *httptest.ResponseRecorder) MessageBatch {
+	t.Helper()
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var batch MessageBatch
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &batch); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	return batch
+}
+
+func TestMessageBatch(t *testing.T) {
+	r := newBatchRouter(t)
+
+	created := decodeMessageBatch(t, serveBatch(t, r, http.MethodPost, "/messages/batches", `{"requests":[
+		{"custom_id":"a","params":{"model":"`+batchTestModel+`","max_tokens":16,"messages":[{"role":"user","content":"one"}]}},
+		{"custom_id":"b","params":{"model":"`+batchTestModel+`","max_tokens":16,"stream":true,"messages":[{"role":"user","content":"two"}]}},
+		{"custom_id":"c","params":{"model":"unknown","max_tokens":16,"messages":[{"role":"user","content":"three"}]}}
+	]}`))
+
+	if created.ProcessingStatus != MessageBatchStatusInProgress || created.RequestCounts.Processing != 3 {
+		t.Fatalf("unexpected created batch: %+v", created)
+	}
+
+	var batch MessageBatch
+
+	for range 200 {
+		batch = decodeMessageBatch(t, serveBatch(t, r, http.MethodGet, "/messages/batches/"+created.ID, ""))
+
+		if batch.ProcessingStatus == MessageBatchStatusEnded {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if batch.ProcessingStatus != MessageBatchStatusEnded || batch.ResultsURL == nil {
+		t.Fatalf("expected ended batch with results, got %+v", batch)
+	}
+
+	if batch.RequestCounts.Succeeded != 2 || batch.RequestCounts.Errored != 1 {
+		t.Fatalf("unexpected counts: %+v", batch.RequestCounts)
+	}
+
+	rec := serveBatch(t, r, http.MethodGet, "/messages/batches/"+created.ID+"/results", "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(lines))
+	}
+
+	want := []struct{ id, typ string }{{"a", "succeeded"}, {"b", "succeeded"}, {"c", "errored"}}
+
+	for i, line := range lines {
+		var result MessageBatchResult
+
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("unmarshal result: %v", err)
+		}
+
+		if result.CustomID != want[i].id || result.Result.Type != want[i].typ {
+			t.Fatalf("unexpected result %d: %s", i, line)
+		}
+	}
+
+	if !strings.Contains(lines[1], `"echo: two"`) {
+		t.Fatalf("expected buffered message, got %s", lines[1])
+	}
+
+	list := serveBatch(t, r, http.MethodGet, "/messages/batches", "")
+
+	if !strings.Contains(list.Body.String(), created.ID) {
+		t.Fatalf("expected batch in list, got %s", list.Body.String())
+	}
+
+	if rec := serveBatch(t, r, http.MethodDelete, "/messages/batches/"+created.ID, ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on delete, got %d", rec.Code)
+	}
+
+	if rec := serveBatch(t, r, http.MethodGet, "/messages/batches/"+created.ID, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestMessageBatchCancel(t *testing.T) {
+	r := newBatchRouterWith(t, blockingCompleter{})
+
+	created := decodeMessageBatch(t, serveBatch(t, r, http.MethodPost, "/messages/batches", `{"requests":[
+		{"custom_id":"a","params":{"model":"`+batchTestModel+`","max_tokens":16,"messages":[{"role":"user","content":"one"}]}}
+	]}`))
+
+	canceled := decodeMessageBatch(t, serveBatch(t, r, http.MethodPost, "/messages/batches/"+created.ID+"/cancel", ""))
+
+	if canceled.ProcessingStatus != MessageBatchStatusCanceling || canceled.CancelInitiatedAt == nil {
+		t.Fatalf("expected canceling batch, got %+v", canceled)
+	}
+
+	var batch MessageBatch
+
+	for range 200 {
+		batch = decodeMessageBatch(t, serveBatch(t, r, http.MethodGet, "/messages/batches/"+created.ID, ""))
+
+		if batch.ProcessingStatus == MessageBatchStatusEnded {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if batch.ProcessingStatus != MessageBatchStatusEnded || batch.ResultsURL == nil || batch.CancelInitiatedAt == nil {
+		t.Fatalf("expected ended batch with results, got %+v", batch)
+	}
+
+	if batch.RequestCounts.Canceled != 1 || batch.RequestCounts.Processing != 0 {
+		t.Fatalf("unexpected counts: %+v", batch.RequestCounts)
+	}
+
+	if rec := serveBatch(t, r, http.MethodPost, "/messages/batches/"+created.ID+"/cancel", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an ended batch, got %d", rec.Code)
+	}
+}
+
+func TestMessageBatchValidation(t *testing.T) {
+	r := newBatchRouter(t)
+
+	for _, body := range []string{
+		`{"requests":[]}`,
+		`{"requests":[{"custom_id":"a b","params":{}}]}`,
+		`{"requests":[{"custom_id":"a","params":{}},{"custom_id":"a","params":{}}]}`,
+	} {
+		if rec := serveBatch(t, r, http.MethodPost, "/messages/batches", body); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestMessageBatchesAreScopedToOwner(t *testing.T) {
+	r := newBatchRouter(t)
+
+	as := func(user, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserContextKey, user))
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	created := decodeMessageBatch(t, as("alice", http.MethodPost, "/messages/batches", `{"requests":[
+		{"custom_id":"a","params":{"model":"`+batchTestModel+`","max_tokens":16,"messages":[{"role":"user","content":"one"}]}}
+	]}`))
+
+	for range 200 {
+		if batch := decodeMessageBatch(t, as("alice", http.MethodGet, "/messages/batches/"+created.ID, "")); batch.ProcessingStatus == MessageBatchStatusEnded {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, path := range []string{"/messages/batches/" + created.ID, "/messages/batches/" + created.ID + "/results"} {
+		if rec := as("bob", http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404 for another user, got %d", path, rec.Code)
+		}
+	}
+
+	if list := as("bob", http.MethodGet, "/messages/batches", ""); strings.Contains(list.Body.String(), created.ID) {
+		t.Fatalf("expected no batches of other users, got %s", list.Body.String())
+	}
+
+	if rec := as("alice", http.MethodGet, "/messages/batches/"+created.ID+"/results", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected the owner to read the results, got %d", rec.Code)
+	}
+}
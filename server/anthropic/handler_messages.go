@@ -66,6 +66,16 @@ func (h *Handler) handleMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := policy.Review(r.Context(), h.Policy, req.Model, messages, options); err != nil {
+		writeError(w, policy.CodeFromError(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	if err := h.CheckCapabilities(req.Model, messages, options); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	if req.Stream {
 		h.handleMessagesStream(w, r, req, completer, messages, options)
 	} else {
@@ -85,6 +95,7 @@ func toCompleteOptions(req MessageRequest) (*provider.CompleteOptions, error) {
 
 		Stop:        req.StopSequences,
 		Temperature: req.Temperature,
+		TopP:        req.TopP,
 	}
 
 	if req.ToolChoice != nil {
@@ -0,0 +1,109 @@
+package anthropic
+
+import (
+	"cmp"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+// IsClient reports whether a request comes from an Anthropic client. The
+// model list shares its route with the OpenAI API; Anthropic clients always
+// send the anthropic-version header.
+func IsClient(r *http.Request) bool {
+	return r.Header.Get("anthropic-version") != ""
+}
+
+// HandleModels serves the model list in the Anthropic format.
+func (h *Handler) HandleModels(w http.ResponseWriter, r *http.Request) {
+	result := ModelList{
+		Data: []ModelInfo{},
+	}
+
+	var models []provider.Model
+
+	for _, m := range h.Models() {
+		if h.Policy.Verify(r.Context(), policy.ResourceModel, m.ID, policy.ActionAccess) != nil {
+			continue
+		}
+
+		models = append(models, m)
+	}
+
+	query := r.URL.Query()
+
+	if id := query.Get("after_id"); id != "" {
+		for i, m := range models {
+			if m.ID == id {
+				models = models[i+1:]
+				break
+			}
+		}
+	}
+
+	if id := query.Get("before_id"); id != "" {
+		for i, m := range models {
+			if m.ID == id {
+				models = models[:i]
+				break
+			}
+		}
+	}
+
+	limit := 20
+
+	if val, err := strconv.Atoi(query.Get("limit")); err == nil && val > 0 {
+		limit = min(val, 1000)
+	}
+
+	if len(models) > limit {
+		models = models[:limit]
+		result.HasMore = true
+	}
+
+	for _, m := range models {
+		result.Data = append(result.Data, toModelInfo(m))
+	}
+
+	if len(result.Data) > 0 {
+		result.FirstID = &result.Data[0].ID
+		result.LastID = &result.Data[len(result.Data)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+// HandleModel serves a model in the Anthropic format.
+func (h *Handler) HandleModel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if err := h.Policy.Verify(r.Context(), policy.ResourceModel, id, policy.ActionAccess); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	model, err := h.Model(id)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJson(w, toModelInfo(*model))
+}
+
+func toModelInfo(m provider.Model) ModelInfo {
+	return ModelInfo{
+		Type: "model",
+
+		ID:          m.ID,
+		DisplayName: cmp.Or(m.Name, m.ID),
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+
+		MaxInputTokens: m.Capabilities.ContextWindow,
+		MaxTokens:      m.Capabilities.MaxOutputTokens,
+	}
+}
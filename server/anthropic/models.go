@@ -2,6 +2,7 @@ package anthropic
 
 import (
 	"encoding/json"
+	"time"
 )
 
 // Request types
@@ -392,3 +393,92 @@ func parseSystemContent(system any) (string, error) {
 		return "", nil
 	}
 }
+
+// Message Batches
+
+type MessageBatchRequest struct {
+	Requests []MessageBatchRequestItem `json:"requests"`
+}
+
+type MessageBatchRequestItem struct {
+	CustomID string          `json:"custom_id"`
+	Params   json.RawMessage `json:"params"`
+}
+
+type MessageBatchStatus string
+
+const (
+	MessageBatchStatusInProgress MessageBatchStatus = "in_progress"
+	MessageBatchStatusCanceling  MessageBatchStatus = "canceling"
+	MessageBatchStatusEnded      MessageBatchStatus = "ended"
+)
+
+type MessageBatch struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "message_batch"
+
+	ProcessingStatus MessageBatchStatus `json:"processing_status"`
+	RequestCounts    MessageBatchCounts `json:"request_counts"`
+
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	EndedAt           *time.Time `json:"ended_at"`
+	ArchivedAt        *time.Time `json:"archived_at"`
+	CancelInitiatedAt *time.Time `json:"cancel_initiated_at"`
+
+	ResultsURL *string `json:"results_url"`
+}
+
+type MessageBatchCounts struct {
+	Processing int `json:"processing"`
+	Succeeded  int `json:"succeeded"`
+	Errored    int `json:"errored"`
+	Canceled   int `json:"canceled"`
+	Expired    int `json:"expired"`
+}
+
+type MessageBatchList struct {
+	Data []MessageBatch `json:"data"`
+
+	HasMore bool    `json:"has_more"`
+	FirstID *string `json:"first_id"`
+	LastID  *string `json:"last_id"`
+}
+
+type MessageBatchDeleted struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // "message_batch_deleted"
+}
+
+type MessageBatchResult struct {
+	CustomID string                 `json:"custom_id"`
+	Result   MessageBatchResultItem `json:"result"`
+}
+
+type MessageBatchResultItem struct {
+	Type string `json:"type"` // "succeeded", "errored", "canceled", "expired"
+
+	Message json.RawMessage `json:"message,omitempty"`
+	Error   *ErrorResponse  `json:"error,omitempty"`
+}
+
+// Models
+
+type ModelInfo struct {
+	Type string `json:"type"` // "model"
+
+	ID          string    `json:"id"`
+	DisplayName string    `json:"display_name"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	MaxInputTokens int `json:"max_input_tokens,omitempty"`
+	MaxTokens      int `json:"max_tokens,omitempty"`
+}
+
+type ModelList struct {
+	Data []ModelInfo `json:"data"`
+
+	HasMore bool    `json:"has_more"`
+	FirstID *string `json:"first_id"`
+	LastID  *string `json:"last_id"`
+}
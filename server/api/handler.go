@@ -23,8 +23,12 @@ func New(cfg *config.Config) *Handler {
 
 func (h *Handler) Attach(r chi.Router) {
 	r.Get("/token", h.handleToken)
+	r.Get("/usage", h.handleUsage)
 
 	r.Post("/extract", h.handleExtract)
+
+	r.Post("/ingest", h.handleIngest)
+	r.Get("/ingest/{id}", h.handleIngestJob)
 	r.Post("/render", h.handleRender)
 
 	r.Post("/search", h.handleSearch)
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/pipeline"
+	"github.com/adrianliechti/wingman/pkg/policy"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ingestJob struct {
+	Object string `json:"object"`
+
+	ID string `json:"id"`
+
+	Pipeline string `json:"pipeline"`
+	Status   string `json:"status"`
+
+	Document string `json:"document,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Chunks   int    `json:"chunks"`
+
+	Error string `json:"error,omitempty"`
+
+	CreatedAt   int64  `json:"created_at"`
+	CompletedAt *int64 `json:"completed_at,omitempty"`
+}
+
+// handleIngest queues a file or URL on a configured pipeline, which extracts,
+// segments and indexes it in the background. Poll the returned job for its
+// status.
+func (h *Handler) handleIngest(w http.ResponseWriter, r *http.Request) {
+	id := r.FormValue("pipeline")
+
+	if id == "" {
+		if pipelines := h.Pipelines(); len(pipelines) == 1 {
+			id = pipelines[0]
+		}
+	}
+
+	p, err := h.Pipeline(id)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if err := h.Policy.Verify(r.Context(), policy.ResourceIndex, h.PipelineIndex(id), policy.ActionAccess); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if err := h.Policy.Verify(r.Context(), policy.ResourceIndex, h.PipelineIndex(id), policy.ActionWrite); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	input := pipeline.Input{
+		ID:    r.FormValue("id"),
+		URL:   valueURL(r),
+		Title: r.FormValue("title"),
+	}
+
+	if val := r.FormValue("metadata"); val != "" {
+		if err := json.Unmarshal([]byte(val), &input.Metadata); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid metadata: "+err.Error()))
+			return
+		}
+	}
+
+	if input.URL == "" {
+		file, err := readFile(r)
+
+		if err != nil || len(file.Content) == 0 {
+			writeError(w, http.StatusBadRequest, errors.New("missing file or url"))
+			return
+		}
+
+		input.File = file
+	}
+
+	job := pipeline.DefaultJobs.Start(context.WithoutCancel(r.Context()), id, p, input)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	writeJson(w, toIngestJob(job))
+}
+
+func (h *Handler) handleIngestJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := pipeline.DefaultJobs.Get(chi.URLParam(r, "id"))
+
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("job not found"))
+		return
+	}
+
+	if err := h.Policy.Verify(r.Context(), policy.ResourceIndex, h.PipelineIndex(job.Pipeline), policy.ActionAccess); err != nil {
+		writeError(w, http.StatusNotFound, errors.New("job not found"))
+		return
+	}
+
+	writeJson(w, toIngestJob(*job))
+}
+
+func toIngestJob(job pipeline.Job) ingestJob {
+	result := ingestJob{
+		Object: "ingest.job",
+
+		ID: job.ID,
+
+		Pipeline: job.Pipeline,
+		Status:   string(job.Status),
+
+		Document: job.Document,
+		Source:   job.Source,
+		Chunks:   job.Chunks,
+
+		Error: job.Error,
+
+		CreatedAt: job.CreatedAt.Unix(),
+	}
+
+	if job.CompletedAt != nil {
+		result.CompletedAt = new(job.CompletedAt.Unix())
+	}
+
+	return result
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/extractor"
+	"github.com/adrianliechti/wingman/pkg/index/memory"
+	"github.com/adrianliechti/wingman/pkg/pipeline"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/segmenter/text"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) Embed(ctx context.Context, texts []string, options *provider.EmbedOptions) (*provider.Embedding, error) {
+	result := &provider.Embedding{}
+
+	for range texts {
+		result.Embeddings = append(result.Embeddings, []float32{1, 0})
+	}
+
+	return result, nil
+}
+
+type fakeExtractor struct{}
+
+func (fakeExtractor) Extract(ctx context.Context, file extractor.File, options *extractor.ExtractOptions) (*extractor.Document, error) {
+	return &extractor.Document{Text: "Vacation is 25 days."}, nil
+}
+
+func newIngestRouter(t *testing.T) chi.Router {
+	t.Helper()
+
+	idx, err := memory.New(fakeEmbedder{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	segmenter, _ := text.New()
+
+	p, err := pipeline.New(idx, fakeExtractor{}, segmenter)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Policy: noop.New()}
+	cfg.RegisterPipeline("docs", p)
+
+	r := chi.NewRouter()
+	New(cfg).Attach(r)
+
+	return r
+}
+
+func TestIngest(t *testing.T) {
+	r := newIngestRouter(t)
+
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+
+	part, _ := form.CreateFormFile("file", "handbook.txt")
+	part.Write([]byte("Vacation is 25 days."))
+
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/ingest", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	var job ingestJob
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	if job.ID == "" || job.Pipeline != "docs" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	for range 200 {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ingest/"+job.ID, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if err := json.Unmarshal(rec.Body.Bytes(), &job); err != nil {
+			t.Fatal(err)
+		}
+
+		if job.Status == string(pipeline.StatusCompleted) || job.Status == string(pipeline.StatusFailed) {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Status != string(pipeline.StatusCompleted) || job.Chunks == 0 || job.CompletedAt == nil {
+		t.Fatalf("expected completed job, got %+v", job)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ingest/unknown", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown job, got %d", rec.Code)
+	}
+}
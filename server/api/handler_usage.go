@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/usage"
+)
+
+type usageReport struct {
+	Object string `json:"object"`
+
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+
+	Total usageTotals `json:"total"`
+
+	Users  []usageTotals `json:"users"`
+	Groups []usageTotals `json:"groups"`
+	Models []usageTotals `json:"models"`
+}
+
+type usageTotals struct {
+	Key string `json:"key,omitempty"`
+
+	Requests int64 `json:"requests"`
+
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+
+	ReasoningTokens int64 `json:"reasoning_tokens"`
+
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+
+	Cost float64 `json:"cost"`
+}
+
+// handleUsage reports token usage and spend per user, group and model. The
+// window is given by from/to (RFC 3339 or unix seconds) or by a window
+// duration ending now (default 24h).
+func (h *Handler) handleUsage(w http.ResponseWriter, r *http.Request) {
+	from, to, err := usageWindow(r)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var user string
+
+	if err := h.Policy.Verify(r.Context(), policy.ResourceUsage, "", policy.ActionAccess); err != nil {
+		user, _ = r.Context().Value(auth.UserContextKey).(string)
+
+		if user == "" {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+
+	report := usage.Default.Query(from, to, user)
+
+	result := usageReport{
+		Object: "usage.report",
+
+		From: from.Unix(),
+		To:   to.Unix(),
+
+		Total: toUsageTotals("", report.Total),
+
+		Users:  toUsageList(report.Users),
+		Groups: toUsageList(report.Groups),
+		Models: toUsageList(report.Models),
+	}
+
+	writeJson(w, result)
+}
+
+func usageWindow(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+
+	to := time.Now()
+
+	if val := query.Get("to"); val != "" {
+		t, err := parseTime(val)
+
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to: " + val)
+		}
+
+		to = t
+	}
+
+	if val := query.Get("from"); val != "" {
+		from, err := parseTime(val)
+
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from: " + val)
+		}
+
+		return from, to, nil
+	}
+
+	window := 24 * time.Hour
+
+	if val := query.Get("window"); val != "" {
+		d, err := time.ParseDuration(val)
+
+		if err != nil || d <= 0 {
+			return time.Time{}, time.Time{}, errors.New("invalid window: " + val)
+		}
+
+		window = d
+	}
+
+	return to.Add(-window), to, nil
+}
+
+func parseTime(val string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		return t, nil
+	}
+
+	unix, err := strconv.ParseInt(val, 10, 64)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(unix, 0), nil
+}
+
+func toUsageList(m map[string]usage.Totals) []usageTotals {
+	result := []usageTotals{}
+
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		result = append(result, toUsageTotals(key, m[key]))
+	}
+
+	return result
+}
+
+func toUsageTotals(key string, t usage.Totals) usageTotals {
+	return usageTotals{
+		Key: key,
+
+		Requests: t.Requests,
+
+		InputTokens:  t.InputTokens,
+		OutputTokens: t.OutputTokens,
+
+		ReasoningTokens: t.ReasoningTokens,
+
+		CacheReadInputTokens:     t.CacheReadInputTokens,
+		CacheCreationInputTokens: t.CacheCreationInputTokens,
+
+		Cost: t.Cost,
+	}
+}
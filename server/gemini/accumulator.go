@@ -62,6 +62,8 @@ func (s *StreamingAccumulator) Add(c provider.Completion) error {
 					{
 						Content: content,
 						Index:   0,
+
+						LogprobsResult: toLogprobsResult(c.Logprobs),
 					},
 				}
 			}
@@ -392,3 +392,31 @@ func generateID(length int) string {
 
 	return hex.EncodeToString(bytes)[:length]
 }
+
+func toLogprobsResult(logprobs []provider.Logprob) *LogprobsResult {
+	if len(logprobs) == 0 {
+		return nil
+	}
+
+	result := &LogprobsResult{}
+
+	for _, l := range logprobs {
+		result.ChosenCandidates = append(result.ChosenCandidates, &LogprobsCandidate{
+			Token:          l.Token,
+			LogProbability: l.Logprob,
+		})
+
+		top := &TopCandidates{}
+
+		for _, c := range l.TopLogprobs {
+			top.Candidates = append(top.Candidates, &LogprobsCandidate{
+				Token:          c.Token,
+				LogProbability: c.Logprob,
+			})
+		}
+
+		result.TopCandidates = append(result.TopCandidates, top)
+	}
+
+	return result
+}
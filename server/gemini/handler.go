@@ -22,6 +22,9 @@ func New(cfg *config.Config) *Handler {
 }
 
 func (h *Handler) Attach(r chi.Router) {
+	r.Get("/models", h.handleModels)
+	r.Get("/models/{model}", h.handleModel)
+
 	r.Post("/models/{model}:generateContent", h.handleGenerateContent)
 	r.Post("/models/{model}:streamGenerateContent", h.handleStreamGenerateContent)
 	r.Post("/models/{model}:countTokens", h.handleCountTokens)
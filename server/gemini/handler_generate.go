@@ -15,7 +15,7 @@ func (h *Handler) handleGenerateContent(w http.ResponseWriter, r *http.Request)
 	completer, messages, options, err := h.parseGenerateRequest(r)
 
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, policy.CodeFromError(err, http.StatusBadRequest), err)
 		return
 	}
 
@@ -52,6 +52,8 @@ func (h *Handler) handleGenerateContent(w http.ResponseWriter, r *http.Request)
 				Content:      content,
 				FinishReason: finishReason,
 				Index:        0,
+
+				LogprobsResult: toLogprobsResult(completion.Logprobs),
 			},
 		}
 	}
@@ -65,7 +67,7 @@ func (h *Handler) handleStreamGenerateContent(w http.ResponseWriter, r *http.Req
 	completer, messages, options, err := h.parseGenerateRequest(r)
 
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err)
+		writeError(w, policy.CodeFromError(err, http.StatusBadRequest), err)
 		return
 	}
 
@@ -200,6 +202,20 @@ func (h *Handler) parseGenerateRequest(r *http.Request) (provider.Completer, []p
 		options.Temperature = req.GenerationConfig.Temperature
 		options.MaxTokens = req.GenerationConfig.MaxOutputTokens
 
+		options.TopP = req.GenerationConfig.TopP
+		options.Seed = req.GenerationConfig.Seed
+
+		options.PresencePenalty = req.GenerationConfig.PresencePenalty
+		options.FrequencyPenalty = req.GenerationConfig.FrequencyPenalty
+
+		if req.GenerationConfig.ResponseLogprobs {
+			options.LogprobsOptions = &provider.LogprobsOptions{}
+
+			if req.GenerationConfig.Logprobs != nil {
+				options.LogprobsOptions.TopLogprobs = *req.GenerationConfig.Logprobs
+			}
+		}
+
 		// Handle structured output via responseJsonSchema or responseSchema
 		strict := true
 
@@ -256,6 +272,14 @@ func (h *Handler) parseGenerateRequest(r *http.Request) (provider.Completer, []p
 		}
 	}
 
+	if err := policy.Review(r.Context(), h.Policy, model, messages, options); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := h.CheckCapabilities(model, messages, options); err != nil {
+		return nil, nil, nil, err
+	}
+
 	return completer, messages, options, nil
 }
 
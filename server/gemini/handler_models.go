@@ -0,0 +1,97 @@
+package gemini
+
+import (
+	"cmp"
+	"net/http"
+	"strconv"
+
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+// handleModels lists the models that can generate content. The page token is
+// the id of the first model of the next page.
+func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
+	result := ListModelsResponse{
+		Models: []Model{},
+	}
+
+	var models []provider.Model
+
+	for _, m := range h.Models() {
+		if _, err := h.Completer(m.ID); err != nil {
+			continue
+		}
+
+		if h.Policy.Verify(r.Context(), policy.ResourceModel, m.ID, policy.ActionAccess) != nil {
+			continue
+		}
+
+		models = append(models, m)
+	}
+
+	query := r.URL.Query()
+
+	if token := query.Get("pageToken"); token != "" {
+		for i, m := range models {
+			if m.ID == token {
+				models = models[i:]
+				break
+			}
+		}
+	}
+
+	size := 50
+
+	if val, err := strconv.Atoi(query.Get("pageSize")); err == nil && val > 0 {
+		size = min(val, 1000)
+	}
+
+	if len(models) > size {
+		result.NextPageToken = models[size].ID
+		models = models[:size]
+	}
+
+	for _, m := range models {
+		result.Models = append(result.Models, toModel(m))
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleModel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("model")
+
+	if err := h.Policy.Verify(r.Context(), policy.ResourceModel, id, policy.ActionAccess); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	model, err := h.Model(id)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJson(w, toModel(*model))
+}
+
+func toModel(m provider.Model) Model {
+	c := m.Capabilities
+
+	return Model{
+		Name:        "models/" + m.ID,
+		BaseModelID: m.ID,
+
+		DisplayName: cmp.Or(m.Name, m.ID),
+		Description: m.Description,
+
+		InputTokenLimit:  c.ContextWindow,
+		OutputTokenLimit: c.MaxOutputTokens,
+
+		SupportedGenerationMethods: []string{"generateContent", "streamGenerateContent", "countTokens"},
+
+		Thinking: c.Reasoning != nil && *c.Reasoning,
+	}
+}
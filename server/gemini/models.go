@@ -161,6 +161,25 @@ type Candidate struct {
 	Index         int             `json:"index,omitempty"`
 	SafetyRatings []*SafetyRating `json:"safetyRatings,omitempty"`
 	TokenCount    int             `json:"tokenCount,omitempty"`
+
+	LogprobsResult *LogprobsResult `json:"logprobsResult,omitempty"`
+}
+
+// LogprobsResult holds the log probabilities of the generated tokens
+type LogprobsResult struct {
+	TopCandidates    []*TopCandidates     `json:"topCandidates,omitempty"`
+	ChosenCandidates []*LogprobsCandidate `json:"chosenCandidates,omitempty"`
+}
+
+// TopCandidates lists the most likely tokens of a decoding step
+type TopCandidates struct {
+	Candidates []*LogprobsCandidate `json:"candidates,omitempty"`
+}
+
+// LogprobsCandidate is a token and its log probability
+type LogprobsCandidate struct {
+	Token          string  `json:"token,omitempty"`
+	LogProbability float64 `json:"logProbability"`
 }
 
 // SafetyRating represents a safety evaluation
@@ -209,6 +228,29 @@ type CountTokensResponse struct {
 	TotalTokens int `json:"totalTokens,omitempty"`
 }
 
+// Model describes a model in the models list
+type Model struct {
+	Name        string `json:"name"`
+	BaseModelID string `json:"baseModelId,omitempty"`
+	Version     string `json:"version,omitempty"`
+
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	InputTokenLimit  int `json:"inputTokenLimit,omitempty"`
+	OutputTokenLimit int `json:"outputTokenLimit,omitempty"`
+
+	SupportedGenerationMethods []string `json:"supportedGenerationMethods,omitempty"`
+
+	Thinking bool `json:"thinking,omitempty"`
+}
+
+// ListModelsResponse is the response from models.list
+type ListModelsResponse struct {
+	Models        []Model `json:"models"`
+	NextPageToken string  `json:"nextPageToken,omitempty"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error *APIError `json:"error,omitempty"`
@@ -0,0 +1,46 @@
+package batches
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/server/openai/shared"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var errNoStore = errors.New("batches require a configured store")
+
+type Handler struct {
+	*config.Config
+
+	// handler serves the batched requests, e.g. /chat/completions.
+	handler http.Handler
+}
+
+func New(cfg *config.Config, handler http.Handler) *Handler {
+	h := &Handler{
+		Config: cfg,
+
+		handler: handler,
+	}
+
+	return h
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Get("/batches", h.handleBatches)
+	r.Post("/batches", h.handleBatchCreate)
+
+	r.Get("/batches/{id}", h.handleBatch)
+	r.Post("/batches/{id}/cancel", h.handleBatchCancel)
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	shared.WriteJson(w, v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	shared.WriteError(w, code, err)
+}
@@ -0,0 +1,361 @@
+package batches
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/store"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+var endpoints = []string{
+	"/v1/chat/completions",
+	"/v1/responses",
+	"/v1/embeddings",
+}
+
+func (h *Handler) handleBatches(w http.ResponseWriter, r *http.Request) {
+	if h.Store == nil {
+		writeJson(w, BatchList{Object: "list", Data: []Batch{}})
+		return
+	}
+
+	batches, err := h.listBatches(r)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// newest first, like OpenAI
+	slices.Reverse(batches)
+
+	query := r.URL.Query()
+
+	if after := query.Get("after"); after != "" {
+		for i, b := range batches {
+			if b.ID == after {
+				batches = batches[i+1:]
+				break
+			}
+		}
+	}
+
+	limit := 20
+
+	if val, err := strconv.Atoi(query.Get("limit")); err == nil && val > 0 {
+		limit = min(val, 100)
+	}
+
+	hasMore := len(batches) > limit
+
+	if hasMore {
+		batches = batches[:limit]
+	}
+
+	result := BatchList{
+		Object: "list",
+		Data:   batches,
+
+		HasMore: hasMore,
+	}
+
+	if len(batches) > 0 {
+		result.FirstID = batches[0].ID
+		result.LastID = batches[len(batches)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleBatchCreate(w http.ResponseWriter, r *http.Request) {
+	if h.Store == nil {
+		writeError(w, http.StatusNotImplemented, errNoStore)
+		return
+	}
+
+	var req BatchRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !slices.Contains(endpoints, req.Endpoint) {
+		writeError(w, http.StatusBadRequest, errors.New("unsupported endpoint: "+req.Endpoint))
+		return
+	}
+
+	f, err := h.Store.GetFile(r.Context(), req.InputFileID)
+
+	if err == nil && !store.IsOwner(r.Context(), f.Owner) {
+		err = store.ErrNotFound
+	}
+
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errors.New("file not found: "+req.InputFileID))
+			return
+		}
+
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	content, err := h.Store.GetFileContent(r.Context(), req.InputFileID)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+
+	batch := &Batch{
+		Object: "batch",
+
+		ID:       "batch_" + uuid.NewString(),
+		Endpoint: req.Endpoint,
+
+		InputFileID:      req.InputFileID,
+		CompletionWindow: req.CompletionWindow,
+
+		Status: BatchStatusValidating,
+
+		CreatedAt: now.Unix(),
+
+		Metadata: req.Metadata,
+	}
+
+	if batch.CompletionWindow == "" {
+		batch.CompletionWindow = "24h"
+	}
+
+	inputs, errs := parseInput(content, req.Endpoint)
+
+	if len(errs) > 0 {
+		batch.Status = BatchStatusFailed
+		batch.FailedAt = new(now.Unix())
+
+		batch.Errors = &BatchErrors{
+			Object: "list",
+			Data:   errs,
+		}
+	} else {
+		batch.Status = BatchStatusInProgress
+		batch.InProgressAt = new(now.Unix())
+
+		batch.RequestCounts.Total = len(inputs)
+	}
+
+	if err := h.saveBatch(r.Context(), batch); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if batch.Status == BatchStatusInProgress {
+		h.start(r.Context(), *batch, inputs)
+	}
+
+	writeJson(w, batch)
+}
+
+func (h *Handler) handleBatch(w http.ResponseWriter, r *http.Request) {
+	batch, ok := h.loadBatch(w, r)
+
+	if !ok {
+		return
+	}
+
+	writeJson(w, batch)
+}
+
+func (h *Handler) handleBatchCancel(w http.ResponseWriter, r *http.Request) {
+	batch, ok := h.loadBatch(w, r)
+
+	if !ok {
+		return
+	}
+
+	if batch.Status != BatchStatusInProgress && batch.Status != BatchStatusValidating {
+		writeError(w, http.StatusConflict, fmt.Errorf("cannot cancel batch with status %s", batch.Status))
+		return
+	}
+
+	// The runner finalizes the batch as cancelled once in-flight requests
+	// return; the cancel is saved before the runner is stopped, so only the
+	// runner writes the final state. Batches without a runner (e.g. after a
+	// restart) are cancelled right away.
+	if value, ok := running.Load(batch.ID); ok {
+		run := value.(*runningBatch)
+
+		run.mu.Lock()
+
+		if status := run.batch.Status; status != BatchStatusInProgress && status != BatchStatusValidating {
+			run.mu.Unlock()
+
+			writeError(w, http.StatusConflict, fmt.Errorf("cannot cancel batch with status %s", status))
+			return
+		}
+
+		run.batch.Status = BatchStatusCancelling
+		run.batch.CancellingAt = new(time.Now().Unix())
+
+		err := h.saveBatch(r.Context(), run.batch)
+		result := *run.batch
+
+		run.mu.Unlock()
+
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		run.cancel()
+
+		writeJson(w, result)
+		return
+	}
+
+	batch.Status = BatchStatusCancelled
+	batch.CancelledAt = new(time.Now().Unix())
+
+	if err := h.saveBatch(r.Context(), batch); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, batch)
+}
+
+func (h *Handler) loadBatch(w http.ResponseWriter, r *http.Request) (*Batch, bool) {
+	id := chi.URLParam(r, "id")
+
+	if h.Store == nil {
+		writeError(w, http.StatusNotFound, errors.New("batch not found: "+id))
+		return nil, false
+	}
+
+	b, err := h.Store.GetBatch(r.Context(), id)
+
+	if err == nil && (b.Type != "openai" || !store.IsOwner(r.Context(), b.Owner)) {
+		err = store.ErrNotFound
+	}
+
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errors.New("batch not found: "+id))
+			return nil, false
+		}
+
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	var batch Batch
+
+	if err := json.Unmarshal(b.Data, &batch); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	return &batch, true
+}
+
+func (h *Handler) listBatches(r *http.Request) ([]Batch, error) {
+	items, err := h.Store.ListBatches(r.Context())
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Batch{}
+
+	for _, item := range items {
+		if item.Type != "openai" || !store.IsOwner(r.Context(), item.Owner) {
+			continue
+		}
+
+		var batch Batch
+
+		if err := json.Unmarshal(item.Data, &batch); err != nil {
+			return nil, err
+		}
+
+		result = append(result, batch)
+	}
+
+	return result, nil
+}
+
+// parseInput reads the JSONL input file. Every line must target the batch
+// endpoint and carry a unique custom_id.
+func parseInput(content []byte, endpoint string) ([]BatchInput, []BatchError) {
+	var inputs []BatchInput
+	var errs []BatchError
+
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 64<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" {
+			continue
+		}
+
+		fail := func(code, message string) {
+			errs = append(errs, BatchError{Code: code, Message: message, Line: new(line)})
+		}
+
+		var input BatchInput
+
+		if err := json.Unmarshal([]byte(text), &input); err != nil {
+			fail("invalid_json_line", err.Error())
+			continue
+		}
+
+		switch {
+		case input.CustomID == "":
+			fail("missing_required_parameter", "custom_id is required")
+
+		case seen[input.CustomID]:
+			fail("duplicate_custom_id", "duplicate custom_id: "+input.CustomID)
+
+		case !strings.EqualFold(input.Method, http.MethodPost):
+			fail("invalid_method", "method must be POST")
+
+		case input.URL != endpoint:
+			fail("mismatched_endpoint", "url must match the batch endpoint "+endpoint)
+
+		case len(input.Body) == 0 || input.Body[0] != '{':
+			fail("invalid_request", "body must be a JSON object")
+
+		default:
+			seen[input.CustomID] = true
+			inputs = append(inputs, input)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, BatchError{Code: "invalid_file", Message: err.Error()})
+	}
+
+	if len(inputs) == 0 && len(errs) == 0 {
+		errs = append(errs, BatchError{Code: "empty_file", Message: "the input file contains no requests"})
+	}
+
+	return inputs, errs
+}
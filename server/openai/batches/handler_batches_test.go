@@ -0,0 +1,317 @@
+package batches
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/auth"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/store"
+	"github.com/adrianliechti/wingman/pkg/store/memory"
+	"github.com/adrianliechti/wingman/server/openai/chat"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const testModel = "batch-test-model"
+
+type echoCompleter struct{}
+
+func (echoCompleter) Complete(_ context.Context, messages []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		yield(&provider.Completion{
+			Status: provider.CompletionStatusCompleted,
+			Message: &provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: []provider.Content{provider.TextContent("echo: " + messages[len(messages)-1].Text())},
+			},
+		}, nil)
+	}
+}
+
+// blockingCompleter answers once its request is cancelled.
+type blockingCompleter struct{}
+
+func (blockingCompleter) Complete(ctx context.Context, _ []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		<-ctx.Done()
+		yield(nil, ctx.Err())
+	}
+}
+
+func newTestRouter(t *testing.T) (*config.Config, chi.Router) {
+	t.Helper()
+
+	return newTestRouterWith(t, echoCompleter{})
+}
+
+func newTestRouterWith(t *testing.T, completer provider.Completer) (*config.Config, chi.Router) {
+	t.Helper()
+
+	cfg := &config.Config{Policy: noop.New(), Store: memory.New()}
+	cfg.RegisterCompleter(testModel, completer)
+
+	mux := chi.NewRouter()
+	chat.New(cfg).Attach(mux)
+
+	r := chi.NewRouter()
+	New(cfg, mux).Attach(r)
+
+	return cfg, r
+}
+
+func uploadInput(t *testing.T, cfg *config.Config, lines ...string) string {
+	t.Helper()
+
+	content := []byte(strings.Join(lines, "\n"))
+
+	f := &store.File{
+		ID:        "file-input",
+		Name:      "input.jsonl",
+		Purpose:   "batch",
+		Size:      int64(len(content)),
+		CreatedAt: time.Now(),
+	}
+
+	if err := cfg.Store.SaveFile(context.Background(), f, content); err != nil {
+		t.Fatalf("save file: %v", err)
+	}
+
+	return f.ID
+}
+
+func serve(t *testing.T, r chi.Router, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func decodeBatch(t *testing.T, rec *httptest.ResponseRecorder) Batch {
+	t.Helper()
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var batch Batch
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &batch); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	return batch
+}
+
+func waitBatch(t *testing.T, r chi.Router, id string) Batch {
+	t.Helper()
+
+	for range 200 {
+		batch := decodeBatch(t, serve(t, r, http.MethodGet, "/batches/"+id, ""))
+
+		if batch.Status == BatchStatusCompleted || batch.Status == BatchStatusCancelled || batch.Status == BatchStatusFailed {
+			return batch
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("batch %s did not finish", id)
+	return Batch{}
+}
+
+func TestBatchCompletes(t *testing.T) {
+	cfg, r := newTestRouter(t)
+
+	input := uploadInput(t, cfg,
+		`{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"`+testModel+`","messages":[{"role":"user","content":"one"}]}}`,
+		`{"custom_id":"b","method":"POST","url":"/v1/chat/completions","body":{"model":"`+testModel+`","stream":true,"messages":[{"role":"user","content":"two"}]}}`,
+		`{"custom_id":"c","method":"POST","url":"/v1/chat/completions","body":{"model":"unknown","messages":[{"role":"user","content":"three"}]}}`,
+	)
+
+	created := decodeBatch(t, serve(t, r, http.MethodPost, "/batches", `{"input_file_id":"`+input+`","endpoint":"/v1/chat/completions","completion_window":"24h"}`))
+
+	batch := waitBatch(t, r, created.ID)
+
+	if batch.Status != BatchStatusCompleted {
+		t.Fatalf("expected completed, got %s", batch.Status)
+	}
+
+	if batch.RequestCounts.Total != 3 || batch.RequestCounts.Completed != 2 || batch.RequestCounts.Failed != 1 {
+		t.Fatalf("unexpected counts: %+v", batch.RequestCounts)
+	}
+
+	content, err := cfg.Store.GetFileContent(context.Background(), batch.OutputFileID)
+
+	if err != nil {
+		t.Fatalf("output file: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 output lines, got %d: %s", len(lines), content)
+	}
+
+	for i, want := range []string{"a", "b"} {
+		var output BatchOutput
+
+		if err := json.Unmarshal([]byte(lines[i]), &output); err != nil {
+			t.Fatalf("unmarshal output: %v", err)
+		}
+
+		if output.CustomID != want || output.Response == nil || output.Response.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected output line %d: %s", i, lines[i])
+		}
+
+		if !strings.Contains(string(output.Response.Body), `"chat.completion"`) {
+			t.Fatalf("expected a buffered chat completion, got %s", output.Response.Body)
+		}
+	}
+
+	errors, err := cfg.Store.GetFileContent(context.Background(), batch.ErrorFileID)
+
+	if err != nil {
+		t.Fatalf("error file: %v", err)
+	}
+
+	if !strings.Contains(string(errors), `"custom_id":"c"`) {
+		t.Fatalf("expected failed request in error file, got %s", errors)
+	}
+}
+
+func TestBatchValidation(t *testing.T) {
+	cfg, r := newTestRouter(t)
+
+	input := uploadInput(t, cfg,
+		`{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"`+testModel+`"}}`,
+		`{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"`+testModel+`"}}`,
+		`{"custom_id":"b","method":"POST","url":"/v1/embeddings","body":{"model":"`+testModel+`"}}`,
+	)
+
+	batch := decodeBatch(t, serve(t, r, http.MethodPost, "/batches", `{"input_file_id":"`+input+`","endpoint":"/v1/chat/completions"}`))
+
+	if batch.Status != BatchStatusFailed || batch.Errors == nil || len(batch.Errors.Data) != 2 {
+		t.Fatalf("expected failed batch with 2 errors, got %+v", batch)
+	}
+
+	if line := batch.Errors.Data[0].Line; line == nil || *line != 2 {
+		t.Fatalf("expected error on line 2, got %v", line)
+	}
+}
+
+func TestBatchCancelFinished(t *testing.T) {
+	cfg, r := newTestRouter(t)
+
+	input := uploadInput(t, cfg,
+		`{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"`+testModel+`","messages":[{"role":"user","content":"one"}]}}`,
+	)
+
+	created := decodeBatch(t, serve(t, r, http.MethodPost, "/batches", `{"input_file_id":"`+input+`","endpoint":"/v1/chat/completions"}`))
+	waitBatch(t, r, created.ID)
+
+	if rec := serve(t, r, http.MethodPost, "/batches/"+created.ID+"/cancel", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestBatchCancel(t *testing.T) {
+	cfg, r := newTestRouterWith(t, blockingCompleter{})
+
+	input := uploadInput(t, cfg,
+		`{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"`+testModel+`","messages":[{"role":"user","content":"one"}]}}`,
+	)
+
+	created := decodeBatch(t, serve(t, r, http.MethodPost, "/batches", `{"input_file_id":"`+input+`","endpoint":"/v1/chat/completions"}`))
+
+	cancelling := decodeBatch(t, serve(t, r, http.MethodPost, "/batches/"+created.ID+"/cancel", ""))
+
+	if cancelling.Status != BatchStatusCancelling || cancelling.CancellingAt == nil {
+		t.Fatalf("expected cancelling batch, got %+v", cancelling)
+	}
+
+	batch := waitBatch(t, r, created.ID)
+
+	if batch.Status != BatchStatusCancelled || batch.CancelledAt == nil || batch.CancellingAt == nil {
+		t.Fatalf("expected cancelled batch, got %+v", batch)
+	}
+
+	if batch.ErrorFileID == "" || batch.RequestCounts.Failed != 1 {
+		t.Fatalf("expected the cancelled request in the error file, got %+v", batch)
+	}
+}
+
+func TestBatchesWithoutStore(t *testing.T) {
+	cfg := &config.Config{Policy: noop.New()}
+
+	r := chi.NewRouter()
+	New(cfg, chi.NewRouter()).Attach(r)
+
+	if rec := serve(t, r, http.MethodPost, "/batches", `{"input_file_id":"file-x","endpoint":"/v1/chat/completions"}`); rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected 501, got %d", rec.Code)
+	}
+}
+
+func TestBatchesAreScopedToOwner(t *testing.T) {
+	cfg, r := newTestRouter(t)
+
+	content := []byte(`{"custom_id":"a","method":"POST","url":"/v1/chat/completions","body":{"model":"` + testModel + `","messages":[{"role":"user","content":"one"}]}}`)
+
+	cfg.Store.SaveFile(context.Background(), &store.File{ID: "file-alice", Owner: "alice", Purpose: "batch", Size: int64(len(content)), CreatedAt: time.Now()}, content)
+
+	as := func(user, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserContextKey, user))
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	if rec := as("bob", http.MethodPost, "/batches", `{"input_file_id":"file-alice","endpoint":"/v1/chat/completions"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another user's file, got %d", rec.Code)
+	}
+
+	created := decodeBatch(t, as("alice", http.MethodPost, "/batches", `{"input_file_id":"file-alice","endpoint":"/v1/chat/completions"}`))
+
+	var batch Batch
+
+	for range 200 {
+		if batch = decodeBatch(t, as("alice", http.MethodGet, "/batches/"+created.ID, "")); batch.Status == BatchStatusCompleted {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if rec := as("bob", http.MethodGet, "/batches/"+batch.ID, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for another user's batch, got %d", rec.Code)
+	}
+
+	var list BatchList
+
+	json.Unmarshal(as("bob", http.MethodGet, "/batches", "").Body.Bytes(), &list)
+
+	if len(list.Data) != 0 {
+		t.Fatalf("expected no batches of other users, got %+v", list.Data)
+	}
+
+	output, err := cfg.Store.GetFile(context.Background(), batch.OutputFileID)
+
+	if err != nil || output.Owner != "alice" {
+		t.Fatalf("expected the output file to belong to the batch owner, got %+v, %v", output, err)
+	}
+}
@@ -0,0 +1,119 @@
+package batches
+
+import (
+	"encoding/json"
+)
+
+type BatchStatus string
+
+const (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// https://platform.openai.com/docs/api-reference/batch/create
+type BatchRequest struct {
+	InputFileID string `json:"input_file_id"`
+
+	Endpoint         string `json:"endpoint"`
+	CompletionWindow string `json:"completion_window"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/object
+type Batch struct {
+	Object string `json:"object"` // "batch"
+
+	ID       string `json:"id"`
+	Endpoint string `json:"endpoint"`
+
+	Errors *BatchErrors `json:"errors,omitempty"`
+
+	InputFileID      string `json:"input_file_id"`
+	CompletionWindow string `json:"completion_window"`
+
+	Status BatchStatus `json:"status"`
+
+	OutputFileID string `json:"output_file_id,omitempty"`
+	ErrorFileID  string `json:"error_file_id,omitempty"`
+
+	CreatedAt    int64  `json:"created_at"`
+	InProgressAt *int64 `json:"in_progress_at,omitempty"`
+	FinalizingAt *int64 `json:"finalizing_at,omitempty"`
+	CompletedAt  *int64 `json:"completed_at,omitempty"`
+	FailedAt     *int64 `json:"failed_at,omitempty"`
+	CancellingAt *int64 `json:"cancelling_at,omitempty"`
+	CancelledAt  *int64 `json:"cancelled_at,omitempty"`
+
+	RequestCounts BatchRequestCounts `json:"request_counts"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type BatchErrors struct {
+	Object string `json:"object"` // "list"
+
+	Data []BatchError `json:"data"`
+}
+
+type BatchError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	Line *int `json:"line,omitempty"`
+}
+
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/list
+type BatchList struct {
+	Object string `json:"object"` // "list"
+
+	Data []Batch `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+
+	HasMore bool `json:"has_more"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/request-input
+type BatchInput struct {
+	CustomID string `json:"custom_id"`
+
+	Method string `json:"method"`
+	URL    string `json:"url"`
+
+	Body json.RawMessage `json:"body"`
+}
+
+// https://platform.openai.com/docs/api-reference/batch/request-output
+type BatchOutput struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+
+	Response *BatchOutputResponse `json:"response"`
+	Error    *BatchOutputError    `json:"error"`
+}
+
+type BatchOutputResponse struct {
+	StatusCode int    `json:"status_code"`
+	RequestID  string `json:"request_id"`
+
+	Body json.RawMessage `json:"body"`
+}
+
+type BatchOutputError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,214 @@
+package batches
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/batch"
+	"github.com/adrianliechti/wingman/pkg/store"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// running maps the ids of executing batches to their runningBatch. It is
+// shared across handler instances, so a batch started before a config reload
+// can still be cancelled afterwards.
+var running sync.Map
+
+// runningBatch is the state of an executing batch. Its batch is only changed
+// and saved with mu held, so a cancel request and the runner never overwrite
+// each other's updates.
+type runningBatch struct {
+	mu    sync.Mutex
+	batch *Batch
+
+	cancel context.CancelFunc
+}
+
+// start executes the batch in the background. The requests run with the
+// creator's identity but outlive the create request itself.
+func (h *Handler) start(ctx context.Context, b Batch, inputs []BatchInput) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	// drop the routing state of the create request, the batched requests
+	// are routed from scratch
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, nil)
+
+	run := &runningBatch{
+		batch:  &b,
+		cancel: cancel,
+	}
+
+	running.Store(b.ID, run)
+
+	go func() {
+		defer running.Delete(b.ID)
+		defer cancel()
+
+		if err := h.run(ctx, run, inputs); err != nil {
+			slog.Error("batch failed", "batch", b.ID, "error", err)
+		}
+	}()
+}
+
+func (h *Handler) run(ctx context.Context, run *runningBatch, inputs []BatchInput) error {
+	b := run.batch
+
+	requests := make([]batch.Request, len(inputs))
+
+	for i, input := range inputs {
+		requests[i] = batch.Request{
+			ID: input.CustomID,
+
+			Method: input.Method,
+			Path:   strings.TrimPrefix(input.URL, "/v1"),
+
+			Body: batch.WithoutStream(input.Body),
+		}
+	}
+
+	outputs := make([]BatchOutput, len(inputs))
+
+	batch.Run(ctx, h.handler, requests, batch.DefaultConcurrency, func(i int, response *batch.Response, err error) {
+		output := BatchOutput{
+			ID:       "batch_req_" + uuid.NewString(),
+			CustomID: inputs[i].CustomID,
+		}
+
+		if err != nil {
+			code := "request_failed"
+
+			if errors.Is(err, context.Canceled) {
+				code = "batch_cancelled"
+			}
+
+			output.Error = &BatchOutputError{Code: code, Message: err.Error()}
+		} else {
+			output.Response = &BatchOutputResponse{
+				StatusCode: response.StatusCode,
+				RequestID:  "req_" + uuid.NewString(),
+
+				Body: json.RawMessage(response.Body),
+			}
+
+			if !json.Valid(response.Body) {
+				output.Response.Body, _ = json.Marshal(string(response.Body))
+			}
+		}
+
+		run.mu.Lock()
+		defer run.mu.Unlock()
+
+		outputs[i] = output
+
+		if output.Error == nil && output.Response.StatusCode < 400 {
+			b.RequestCounts.Completed++
+		} else {
+			b.RequestCounts.Failed++
+		}
+
+		h.saveBatch(ctx, b)
+	})
+
+	cancelled := ctx.Err() != nil
+
+	// Use a fresh context: finalizing must not be skipped by the very
+	// cancellation it reports.
+	ctx = context.WithoutCancel(ctx)
+
+	// a finalizing batch can no longer be cancelled, so from here on the
+	// runner is its only writer
+	run.mu.Lock()
+
+	b.Status = BatchStatusFinalizing
+	b.FinalizingAt = new(time.Now().Unix())
+
+	err := h.saveBatch(ctx, b)
+
+	run.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	var results, errs bytes.Buffer
+
+	for _, output := range outputs {
+		target := &results
+
+		if output.Error != nil || output.Response.StatusCode >= 400 {
+			target = &errs
+		}
+
+		data, _ := json.Marshal(output)
+
+		target.Write(data)
+		target.WriteByte('\n')
+	}
+
+	if b.OutputFileID, err = h.saveFile(ctx, b.ID+"_output.jsonl", results.Bytes()); err != nil {
+		return err
+	}
+
+	if b.ErrorFileID, err = h.saveFile(ctx, b.ID+"_error.jsonl", errs.Bytes()); err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	if cancelled {
+		b.Status = BatchStatusCancelled
+		b.CancelledAt = new(now)
+	} else {
+		b.Status = BatchStatusCompleted
+		b.CompletedAt = new(now)
+	}
+
+	return h.saveBatch(ctx, b)
+}
+
+func (h *Handler) saveFile(ctx context.Context, name string, content []byte) (string, error) {
+	if len(content) == 0 {
+		return "", nil
+	}
+
+	f := &store.File{
+		ID:    "file-" + uuid.NewString(),
+		Owner: store.Owner(ctx),
+
+		Name:    name,
+		Purpose: "batch_output",
+
+		Size: int64(len(content)),
+
+		CreatedAt: time.Now(),
+	}
+
+	return f.ID, h.Store.SaveFile(ctx, f, content)
+}
+
+func (h *Handler) saveBatch(ctx context.Context, b *Batch) error {
+	data, err := json.Marshal(b)
+
+	if err != nil {
+		return err
+	}
+
+	return h.Store.SaveBatch(ctx, &store.Batch{
+		ID:   b.ID,
+		Type: "openai",
+
+		Owner: store.Owner(ctx),
+
+		Data: data,
+
+		CreatedAt: time.Unix(b.CreatedAt, 0),
+	})
+}
@@ -169,6 +169,10 @@ func (s *StreamingAccumulator) Add(c provider.Completion) error {
 				Delta: message,
 			},
 		}
+
+		if len(c.Logprobs) > 0 {
+			chunk.Choices[0].Logprobs = oaiLogprobs(c.Logprobs)
+		}
 	}
 
 	// Add role on first chunk
@@ -279,18 +283,7 @@ func (s *StreamingAccumulator) Complete(includeUsage bool) error {
 			Model:   result.Model,
 			Created: 0, // Will be set by handler
 			Choices: []ChatCompletionChoice{},
-			Usage: &Usage{
-				PromptTokens:     result.Usage.InputTokens,
-				CompletionTokens: result.Usage.OutputTokens,
-				TotalTokens:      result.Usage.InputTokens + result.Usage.OutputTokens,
-				PromptTokensDetails: &PromptTokensDetails{
-					CachedTokens:     result.Usage.CacheReadInputTokens,
-					CacheWriteTokens: result.Usage.CacheCreationInputTokens,
-				},
-				CompletionTokensDetails: &CompletionTokensDetails{
-					ReasoningTokens: result.Usage.ReasoningTokens,
-				},
-			},
+			Usage:   oaiUsage(result.Usage),
 		}
 
 		if usageChunk.ID == "" {
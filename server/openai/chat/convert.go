@@ -23,6 +23,51 @@ func streamUsage(req ChatCompletionRequest) bool {
 	return *req.StreamOptions.IncludeUsage
 }
 
+// choiceCount returns the number of choices to generate.
+func choiceCount(req ChatCompletionRequest) int {
+	if req.N == nil {
+		return 1
+	}
+
+	return *req.N
+}
+
+func validateSampling(req ChatCompletionRequest) error {
+	if n := choiceCount(req); n < 1 || n > 128 {
+		return &shared.Error{
+			Param:   "n",
+			Message: fmt.Sprintf("Invalid 'n': expected a value between 1 and 128, but got %d instead.", n),
+		}
+	}
+
+	if req.TopLogprobs != nil {
+		if !req.Logprobs {
+			return &shared.Error{
+				Param:   "top_logprobs",
+				Message: "Invalid 'top_logprobs': 'logprobs' must be set to true when 'top_logprobs' is specified.",
+			}
+		}
+
+		if *req.TopLogprobs < 0 || *req.TopLogprobs > 20 {
+			return &shared.Error{
+				Param:   "top_logprobs",
+				Message: fmt.Sprintf("Invalid 'top_logprobs': expected a value between 0 and 20, but got %d instead.", *req.TopLogprobs),
+			}
+		}
+	}
+
+	for token, bias := range req.LogitBias {
+		if bias < -100 || bias > 100 {
+			return &shared.Error{
+				Param:   "logit_bias",
+				Message: fmt.Sprintf("Invalid 'logit_bias': bias of token %s must be between -100 and 100, but got %d instead.", token, bias),
+			}
+		}
+	}
+
+	return nil
+}
+
 func toToolOptions(v *ToolChoice) *provider.ToolOptions {
 	if v == nil {
 		return nil
@@ -250,3 +295,60 @@ func oaiToolCalls(content []provider.Content) []ToolCall {
 
 	return result
 }
+
+func oaiLogprobs(logprobs []provider.Logprob) *ChoiceLogprobs {
+	result := &ChoiceLogprobs{
+		Content: []TokenLogprob{},
+	}
+
+	for _, l := range logprobs {
+		token := TokenLogprob{
+			Token:   l.Token,
+			Bytes:   tokenBytes(l.Token),
+			Logprob: l.Logprob,
+
+			TopLogprobs: []TopLogprob{},
+		}
+
+		for _, top := range l.TopLogprobs {
+			token.TopLogprobs = append(token.TopLogprobs, TopLogprob{
+				Token:   top.Token,
+				Bytes:   tokenBytes(top.Token),
+				Logprob: top.Logprob,
+			})
+		}
+
+		result.Content = append(result.Content, token)
+	}
+
+	return result
+}
+
+func tokenBytes(token string) []int {
+	result := make([]int, len(token))
+
+	for i, b := range []byte(token) {
+		result[i] = int(b)
+	}
+
+	return result
+}
+
+func oaiUsage(usage *provider.Usage) *Usage {
+	if usage == nil {
+		return nil
+	}
+
+	return &Usage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.InputTokens + usage.OutputTokens,
+		PromptTokensDetails: &PromptTokensDetails{
+			CachedTokens:     usage.CacheReadInputTokens,
+			CacheWriteTokens: usage.CacheCreationInputTokens,
+		},
+		CompletionTokensDetails: &CompletionTokensDetails{
+			ReasoningTokens: usage.ReasoningTokens,
+		},
+	}
+}
@@ -0,0 +1,270 @@
+package chat
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/provider/adapter/cache"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxParallelChoices bounds the completions a fanned-out request runs at
+// once.
+const maxParallelChoices = 4
+
+// handleChatCompletionChoices generates n > 1 choices, see runChoices.
+func (h *Handler) handleChatCompletionChoices(w http.ResponseWriter, r *http.Request, req ChatCompletionRequest, n int, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions) {
+	accumulators := make([]provider.CompletionAccumulator, n)
+
+	err := h.runChoices(r.Context(), req.Model, n, completer, messages, options, func(i int, completion provider.Completion) error {
+		accumulators[i].Add(completion)
+		return nil
+	})
+
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	completions := make([]*provider.Completion, n)
+
+	for i := range accumulators {
+		completions[i] = accumulators[i].Result()
+	}
+
+	result := ChatCompletion{
+		Object: "chat.completion",
+
+		ID: "chatcmpl-" + uuid.NewString(),
+
+		Model:   cmp.Or(completions[0].Model, req.Model),
+		Created: time.Now().Unix(),
+
+		Choices: []ChatCompletionChoice{},
+
+		ServiceTier: "default",
+	}
+
+	var usage *provider.Usage
+
+	for i, completion := range completions {
+		result.Choices = append(result.Choices, toChoice(req, i, completion))
+		usage = addUsage(usage, completion.Usage)
+	}
+
+	result.Usage = oaiUsage(usage)
+
+	writeJson(w, result)
+}
+
+// handleChatCompletionChoicesStream streams n > 1 choices, each chunk tagged
+// with the index of its choice. Usage, if requested, covers all choices and
+// follows once every choice finished.
+func (h *Handler) handleChatCompletionChoicesStream(w http.ResponseWriter, r *http.Request, req ChatCompletionRequest, n int, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions) {
+	id := "chatcmpl-" + uuid.NewString()
+	created := time.Now().Unix()
+
+	var mu sync.Mutex
+
+	headersSent := false
+
+	sendHeaders := func() {
+		if !headersSent {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			headersSent = true
+		}
+	}
+
+	accumulators := make([]*StreamingAccumulator, n)
+
+	for i := range accumulators {
+		accumulators[i] = NewStreamingAccumulator(req.Model, func(event StreamEvent) error {
+			if event.Type != StreamEventChunk && event.Type != StreamEventFinish {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			sendHeaders()
+
+			event.Chunk.ID = id
+			event.Chunk.Created = created
+			event.Chunk.Choices[0].Index = i
+
+			return writeEvent(w, event.Chunk)
+		})
+	}
+
+	err := h.runChoices(r.Context(), req.Model, n, completer, messages, options, func(i int, completion provider.Completion) error {
+		return accumulators[i].Add(completion)
+	})
+
+	if err == nil {
+		for _, accumulator := range accumulators {
+			if err = accumulator.Complete(false); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		if !headersSent {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+
+		writeErrorEvent(w, err)
+		return
+	}
+
+	sendHeaders()
+
+	model := req.Model
+
+	var usage *provider.Usage
+
+	for _, accumulator := range accumulators {
+		result := accumulator.Result()
+
+		model = cmp.Or(result.Model, model)
+		usage = addUsage(usage, result.Usage)
+	}
+
+	if streamUsage(req) && usage != nil {
+		if err := writeEvent(w, &ChatCompletion{
+			Object: "chat.completion.chunk",
+
+			ID: id,
+
+			Model:   model,
+			Created: created,
+
+			Choices: []ChatCompletionChoice{},
+
+			Usage: oaiUsage(usage),
+		}); err != nil {
+			return
+		}
+	}
+
+	_, _ = w.Write([]byte("data: [DONE]\n\n"))
+
+	if rc := http.NewResponseController(w); rc != nil {
+		rc.Flush()
+	}
+}
+
+// runChoices generates n choices and hands each chunk to add along with the
+// index of its choice. Models with the choices capability answer all of
+// them in one call; choices they leave out, like every choice of other
+// models, run as separate completions, see fanOutChoices.
+func (h *Handler) runChoices(ctx context.Context, model string, n int, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions, add func(i int, completion provider.Completion) error) error {
+	choices := make([]int, 0, n)
+
+	for i := range n {
+		choices = append(choices, i)
+	}
+
+	if m, err := h.Model(model); err == nil && m.Capabilities.Choices != nil && *m.Capabilities.Choices {
+		o := *options
+		o.Choices = n
+
+		answered := make([]bool, n)
+
+		for completion, err := range completer.Complete(ctx, messages, &o) {
+			if err != nil {
+				return err
+			}
+
+			i := completion.Choice
+
+			if i < 0 || i >= n {
+				continue
+			}
+
+			answered[i] = true
+			completion.Choice = 0
+
+			if err := add(i, *completion); err != nil {
+				return err
+			}
+		}
+
+		choices = slices.DeleteFunc(choices, func(i int) bool {
+			return answered[i]
+		})
+	}
+
+	return fanOutChoices(ctx, choices, completer, messages, options, add)
+}
+
+// fanOutChoices runs the given choices as separate completions, at most
+// maxParallelChoices at once and past the response cache, which would
+// answer every one of them alike. add is called concurrently, though never
+// concurrently for the same choice.
+func fanOutChoices(ctx context.Context, choices []int, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions, add func(i int, completion provider.Completion) error) error {
+	group, ctx := errgroup.WithContext(cache.Bypass(ctx))
+	group.SetLimit(maxParallelChoices)
+
+	for _, i := range choices {
+		group.Go(func() error {
+			for completion, err := range completer.Complete(ctx, messages, choiceOptions(options, i)) {
+				if err != nil {
+					return err
+				}
+
+				if err := add(i, *completion); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
+
+// choiceOptions returns the options of the i-th choice. A seed is offset per
+// choice, so seeded requests stay reproducible without repeating one answer
+// n times.
+func choiceOptions(options *provider.CompleteOptions, i int) *provider.CompleteOptions {
+	if options.Seed == nil || i == 0 {
+		return options
+	}
+
+	o := *options
+	o.Seed = new(*options.Seed + i)
+
+	return &o
+}
+
+func addUsage(total, usage *provider.Usage) *provider.Usage {
+	if usage == nil {
+		return total
+	}
+
+	if total == nil {
+		total = &provider.Usage{}
+	}
+
+	total.InputTokens += usage.InputTokens
+	total.OutputTokens += usage.OutputTokens
+
+	total.ReasoningTokens += usage.ReasoningTokens
+
+	total.CacheReadInputTokens += usage.CacheReadInputTokens
+	total.CacheCreationInputTokens += usage.CacheCreationInputTokens
+
+	return total
+}
@@ -0,0 +1,332 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/provider"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const choicesTestModel = "choices-test-model"
+
+// seedCompleter answers with the seed it was asked to sample with.
+type seedCompleter struct{}
+
+func (seedCompleter) Complete(_ context.Context, _ []provider.Message, options *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		text := "seed " + strconv.Itoa(*options.Seed)
+
+		yield(&provider.Completion{
+			Message: &provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: []provider.Content{provider.TextContent(text)},
+			},
+
+			Logprobs: []provider.Logprob{{Token: text, Logprob: -0.5}},
+
+			Usage: &provider.Usage{InputTokens: 10, OutputTokens: 2},
+		}, nil)
+	}
+}
+
+// nativeCompleter answers all choices of a request in one call.
+type nativeCompleter struct {
+	calls atomic.Int32
+}
+
+func (c *nativeCompleter) Complete(_ context.Context, _ []provider.Message, options *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		c.calls.Add(1)
+
+		for i := range options.Choices {
+			text := "choice " + strconv.Itoa(i)
+
+			if !yield(&provider.Completion{
+				Choice: i,
+
+				Message: &provider.Message{
+					Role:    provider.MessageRoleAssistant,
+					Content: []provider.Content{provider.TextContent(text)},
+				},
+			}, nil) {
+				return
+			}
+		}
+
+		yield(&provider.Completion{
+			Usage: &provider.Usage{InputTokens: 10, OutputTokens: 6},
+		}, nil)
+	}
+}
+
+// concurrencyCompleter records how many of its completions run at once.
+type concurrencyCompleter struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (c *concurrencyCompleter) Complete(_ context.Context, _ []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		n := c.running.Add(1)
+		defer c.running.Add(-1)
+
+		for {
+			peak := c.peak.Load()
+
+			if n <= peak || c.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		yield(&provider.Completion{
+			Message: &provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: []provider.Content{provider.TextContent("ok")},
+			},
+		}, nil)
+	}
+}
+
+func postChoices(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	cfg := &config.Config{Policy: noop.New()}
+	cfg.RegisterCompleter(choicesTestModel, seedCompleter{})
+
+	return serveChoices(cfg, body)
+}
+
+func serveChoices(cfg *config.Config, body string) *httptest.ResponseRecorder {
+
+	r := chi.NewRouter()
+	New(cfg).Attach(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/chat/completions", strings.NewReader(body)))
+
+	return rec
+}
+
+func TestChatCompletionChoices(t *testing.T) {
+	rec := postChoices(t, `{"model":"`+choicesTestModel+`","n":3,"seed":7,"logprobs":true,"messages":[{"role":"user","content":"hi"}]}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result ChatCompletion
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Choices) != 3 {
+		t.Fatalf("choices = %d", len(result.Choices))
+	}
+
+	for i, choice := range result.Choices {
+		want := "seed " + strconv.Itoa(7+i)
+
+		if choice.Index != i || choice.Message.Content == nil || *choice.Message.Content != want {
+			t.Errorf("choice %d: got %+v", i, choice.Message)
+		}
+
+		if choice.Logprobs == nil || len(choice.Logprobs.Content) != 1 || choice.Logprobs.Content[0].Token != want {
+			t.Errorf("choice %d logprobs: got %+v", i, choice.Logprobs)
+		}
+	}
+
+	if result.Usage == nil || result.Usage.PromptTokens != 30 || result.Usage.CompletionTokens != 6 {
+		t.Errorf("usage = %+v", result.Usage)
+	}
+}
+
+func TestChatCompletionChoicesStream(t *testing.T) {
+	rec := postChoices(t, `{"model":"`+choicesTestModel+`","n":2,"seed":1,"stream":true,"stream_options":{"include_usage":true},"messages":[{"role":"user","content":"hi"}]}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	content := map[int]string{}
+	finished := map[int]bool{}
+
+	var ids []string
+	var usage *Usage
+
+	for line := range strings.Lines(rec.Body.String()) {
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+
+		if !ok || data == "[DONE]" {
+			continue
+		}
+
+		var chunk ChatCompletion
+
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, chunk.ID)
+
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Delta != nil && choice.Delta.Content != nil {
+				content[choice.Index] += *choice.Delta.Content
+			}
+
+			if choice.FinishReason != nil {
+				finished[choice.Index] = true
+			}
+		}
+	}
+
+	if content[0] != "seed 1" || content[1] != "seed 2" || !finished[0] || !finished[1] {
+		t.Errorf("choices: content %v, finished %v", content, finished)
+	}
+
+	for _, id := range ids {
+		if id != ids[0] {
+			t.Errorf("chunk ids differ: %v", ids)
+			break
+		}
+	}
+
+	if usage == nil || usage.PromptTokens != 20 || usage.CompletionTokens != 4 {
+		t.Errorf("usage = %+v", usage)
+	}
+
+	if !strings.HasSuffix(rec.Body.String(), "data: [DONE]\n\n") {
+		t.Errorf("stream not terminated: %s", rec.Body.String())
+	}
+}
+
+func TestChatCompletionNativeChoices(t *testing.T) {
+	completer := &nativeCompleter{}
+
+	cfg := &config.Config{Policy: noop.New()}
+	cfg.RegisterCompleter(choicesTestModel, completer)
+
+	cfg.DescribeModel(provider.Model{
+		ID: choicesTestModel,
+
+		Capabilities: provider.ModelCapabilities{
+			Choices: new(true),
+		},
+	})
+
+	rec := serveChoices(cfg, `{"model":"`+choicesTestModel+`","n":3,"messages":[{"role":"user","content":"hi"}]}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result ChatCompletion
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := completer.calls.Load(); calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	if len(result.Choices) != 3 {
+		t.Fatalf("choices = %d", len(result.Choices))
+	}
+
+	for i, choice := range result.Choices {
+		if want := "choice " + strconv.Itoa(i); choice.Message.Content == nil || *choice.Message.Content != want {
+			t.Errorf("choice %d: got %+v", i, choice.Message)
+		}
+	}
+
+	if result.Usage == nil || result.Usage.PromptTokens != 10 || result.Usage.CompletionTokens != 6 {
+		t.Errorf("usage = %+v", result.Usage)
+	}
+}
+
+func TestChatCompletionNativeChoicesFallback(t *testing.T) {
+	cfg := &config.Config{Policy: noop.New()}
+	cfg.RegisterCompleter(choicesTestModel, seedCompleter{})
+
+	// the backend ignores n and answers the first choice only
+	cfg.DescribeModel(provider.Model{
+		ID: choicesTestModel,
+
+		Capabilities: provider.ModelCapabilities{
+			Choices: new(true),
+		},
+	})
+
+	rec := serveChoices(cfg, `{"model":"`+choicesTestModel+`","n":3,"seed":7,"messages":[{"role":"user","content":"hi"}]}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result ChatCompletion
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Choices) != 3 {
+		t.Fatalf("choices = %d", len(result.Choices))
+	}
+
+	for i, choice := range result.Choices {
+		if want := "seed " + strconv.Itoa(7+i); choice.Message.Content == nil || *choice.Message.Content != want {
+			t.Errorf("choice %d: got %+v", i, choice.Message)
+		}
+	}
+}
+
+func TestChatCompletionChoicesConcurrency(t *testing.T) {
+	completer := &concurrencyCompleter{}
+
+	cfg := &config.Config{Policy: noop.New()}
+	cfg.RegisterCompleter(choicesTestModel, completer)
+
+	rec := serveChoices(cfg, `{"model":"`+choicesTestModel+`","n":12,"messages":[{"role":"user","content":"hi"}]}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if peak := completer.peak.Load(); peak > maxParallelChoices {
+		t.Errorf("peak concurrency = %d, want at most %d", peak, maxParallelChoices)
+	}
+}
+
+func TestChatCompletionSamplingValidation(t *testing.T) {
+	for body, param := range map[string]string{
+		`"n":0`:                             "n",
+		`"top_logprobs":2`:                  "top_logprobs",
+		`"logprobs":true,"top_logprobs":21`: "top_logprobs",
+		`"logit_bias":{"42":200}`:           "logit_bias",
+	} {
+		rec := postChoices(t, `{"model":"`+choicesTestModel+`",`+body+`,"messages":[{"role":"user","content":"hi"}]}`)
+
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"param":"`+param+`"`) {
+			t.Errorf("%s: status = %d: %s", body, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -45,8 +45,33 @@ func (h *Handler) handleChatCompletion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validateSampling(req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	options := toCompleteOptions(req, tools)
 
+	if err := policy.Review(r.Context(), h.Policy, req.Model, messages, options); err != nil {
+		writeError(w, policy.CodeFromError(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	if err := h.CheckCapabilities(req.Model, messages, options); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if n := choiceCount(req); n > 1 {
+		if req.Stream {
+			h.handleChatCompletionChoicesStream(w, r, req, n, completer, messages, options)
+		} else {
+			h.handleChatCompletionChoices(w, r, req, n, completer, messages, options)
+		}
+
+		return
+	}
+
 	if req.Stream {
 		h.handleChatCompletionStream(w, r, req, completer, messages, options)
 	} else {
@@ -86,6 +111,22 @@ func toCompleteOptions(req ChatCompletionRequest, tools []provider.Tool) *provid
 
 		MaxTokens:   maxTokens,
 		Temperature: req.Temperature,
+		TopP:        req.TopP,
+
+		Seed: req.Seed,
+
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+
+		LogitBias: req.LogitBias,
+	}
+
+	if req.Logprobs {
+		options.LogprobsOptions = &provider.LogprobsOptions{}
+
+		if req.TopLogprobs != nil {
+			options.LogprobsOptions.TopLogprobs = *req.TopLogprobs
+		}
 	}
 
 	if req.ParallelToolCalls != nil && !*req.ParallelToolCalls {
@@ -181,8 +222,6 @@ func (h *Handler) handleChatCompletionComplete(w http.ResponseWriter, r *http.Re
 
 	completion := acc.Result()
 
-	role := MessageRoleAssistant
-
 	result := ChatCompletion{
 		Object: "chat.completion",
 
@@ -205,60 +244,59 @@ func (h *Handler) handleChatCompletionComplete(w http.ResponseWriter, r *http.Re
 	}
 
 	if completion.Message != nil {
-		message := &ChatCompletionMessage{
-			Role:        role,
-			Annotations: []any{},
+		result.Choices = []ChatCompletionChoice{
+			toChoice(req, 0, completion),
 		}
+	}
 
-		if content := completion.Message.Text(); content != "" {
-			message.Content = &content
-		}
+	result.Usage = oaiUsage(completion.Usage)
 
-		if refusal := completion.Message.Refusal(); refusal != "" {
-			message.Refusal = &refusal
-		}
+	writeJson(w, result)
+}
+
+func toChoice(req ChatCompletionRequest, index int, completion *provider.Completion) ChatCompletionChoice {
+	message := &ChatCompletionMessage{
+		Role:        MessageRoleAssistant,
+		Annotations: []any{},
+	}
+
+	if content := completion.Message.Text(); content != "" {
+		message.Content = &content
+	}
 
-		calls := oaiToolCalls(completion.Message.Content)
+	if refusal := completion.Message.Refusal(); refusal != "" {
+		message.Refusal = &refusal
+	}
+
+	calls := oaiToolCalls(completion.Message.Content)
+	if len(calls) > 0 {
+		message.ToolCalls = calls
+	}
+
+	reason := FinishReasonStop
+	switch completion.Status {
+	case provider.CompletionStatusIncomplete:
+		reason = FinishReasonLength
+	case provider.CompletionStatusRefused:
+		reason = FinishReasonContentFilter
+	default:
 		if len(calls) > 0 {
-			message.ToolCalls = calls
+			reason = FinishReasonToolCalls
 		}
+	}
 
-		reason := FinishReasonStop
-		switch completion.Status {
-		case provider.CompletionStatusIncomplete:
-			reason = FinishReasonLength
-		case provider.CompletionStatusRefused:
-			reason = FinishReasonContentFilter
-		default:
-			if len(calls) > 0 {
-				reason = FinishReasonToolCalls
-			}
-		}
+	choice := ChatCompletionChoice{
+		Index: index,
 
-		result.Choices = []ChatCompletionChoice{
-			{
-				Message:      message,
-				FinishReason: &reason,
-			},
-		}
+		Message:      message,
+		FinishReason: &reason,
 	}
 
-	if completion.Usage != nil {
-		result.Usage = &Usage{
-			PromptTokens:     completion.Usage.InputTokens,
-			CompletionTokens: completion.Usage.OutputTokens,
-			TotalTokens:      completion.Usage.InputTokens + completion.Usage.OutputTokens,
-			PromptTokensDetails: &PromptTokensDetails{
-				CachedTokens:     completion.Usage.CacheReadInputTokens,
-				CacheWriteTokens: completion.Usage.CacheCreationInputTokens,
-			},
-			CompletionTokensDetails: &CompletionTokensDetails{
-				ReasoningTokens: completion.Usage.ReasoningTokens,
-			},
-		}
+	if req.Logprobs {
+		choice.Logprobs = oaiLogprobs(completion.Logprobs)
 	}
 
-	writeJson(w, result)
+	return choice
 }
 
 func (h *Handler) handleChatCompletionStream(w http.ResponseWriter, r *http.Request, req ChatCompletionRequest, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions) {
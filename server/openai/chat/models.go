@@ -54,22 +54,21 @@ type ChatCompletionRequest struct {
 	MaxCompletionTokens *int     `json:"max_completion_tokens,omitempty"`
 	MaxTokens           *int     `json:"max_tokens,omitempty"` // deprecated alias of max_completion_tokens
 
-	ResponseFormat *ChatCompletionResponseFormat `json:"response_format,omitempty"`
-
-	StreamOptions *ChatCompletionStreamOptions `json:"stream_options,omitempty"`
+	N    *int     `json:"n,omitempty"`
+	Seed *int     `json:"seed,omitempty"`
+	TopP *float32 `json:"top_p,omitempty"`
 
-	// frequency_penalty *float32
-	// presence_penalty *float32
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
 
-	// logit_bias
-	// logprobs *bool
-	// top_logprobs *int
+	LogitBias map[string]int `json:"logit_bias,omitempty"`
 
-	// n *int
+	Logprobs    bool `json:"logprobs,omitempty"`
+	TopLogprobs *int `json:"top_logprobs,omitempty"`
 
-	// seed *int
+	ResponseFormat *ChatCompletionResponseFormat `json:"response_format,omitempty"`
 
-	// top_p *float32
+	StreamOptions *ChatCompletionStreamOptions `json:"stream_options,omitempty"`
 
 	// user string
 }
@@ -138,6 +137,27 @@ type ChatCompletionChoice struct {
 	Message *ChatCompletionMessage `json:"message,omitempty"`
 
 	FinishReason *FinishReason `json:"finish_reason"`
+
+	Logprobs *ChoiceLogprobs `json:"logprobs,omitempty"`
+}
+
+// https://platform.openai.com/docs/api-reference/chat/object
+type ChoiceLogprobs struct {
+	Content []TokenLogprob `json:"content"`
+}
+
+type TokenLogprob struct {
+	Token   string  `json:"token"`
+	Bytes   []int   `json:"bytes"`
+	Logprob float64 `json:"logprob"`
+
+	TopLogprobs []TopLogprob `json:"top_logprobs"`
+}
+
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Bytes   []int   `json:"bytes"`
+	Logprob float64 `json:"logprob"`
 }
 
 // https://platform.openai.com/docs/api-reference/chat/object
@@ -77,3 +77,38 @@ func TestToCompleteOptions_ReasoningEffort(t *testing.T) {
 		t.Errorf("absent reasoning_effort: expected nil ReasoningOptions, got %+v", options.ReasoningOptions)
 	}
 }
+
+func TestToCompleteOptions_Sampling(t *testing.T) {
+	options := toCompleteOptions(ChatCompletionRequest{
+		TopP: new(float32(0.9)),
+		Seed: new(42),
+
+		PresencePenalty:  new(float32(0.5)),
+		FrequencyPenalty: new(float32(-0.5)),
+
+		LogitBias: map[string]int{"50256": -100},
+
+		Logprobs:    true,
+		TopLogprobs: new(5),
+	}, nil)
+
+	if options.TopP == nil || *options.TopP != 0.9 || options.Seed == nil || *options.Seed != 42 {
+		t.Fatalf("top_p/seed: got %v/%v", options.TopP, options.Seed)
+	}
+
+	if options.PresencePenalty == nil || *options.PresencePenalty != 0.5 || options.FrequencyPenalty == nil || *options.FrequencyPenalty != -0.5 {
+		t.Fatalf("penalties: got %v/%v", options.PresencePenalty, options.FrequencyPenalty)
+	}
+
+	if options.LogitBias["50256"] != -100 {
+		t.Fatalf("logit_bias: got %v", options.LogitBias)
+	}
+
+	if options.LogprobsOptions == nil || options.LogprobsOptions.TopLogprobs != 5 {
+		t.Fatalf("logprobs: got %+v", options.LogprobsOptions)
+	}
+
+	if options := toCompleteOptions(ChatCompletionRequest{}, nil); options.LogprobsOptions != nil {
+		t.Fatalf("expected no logprobs, got %+v", options.LogprobsOptions)
+	}
+}
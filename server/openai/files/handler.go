@@ -0,0 +1,42 @@
+package files
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/server/openai/shared"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var errNoStore = errors.New("files require a configured store")
+
+type Handler struct {
+	*config.Config
+}
+
+func New(cfg *config.Config) *Handler {
+	h := &Handler{
+		Config: cfg,
+	}
+
+	return h
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Get("/files", h.handleFiles)
+	r.Post("/files", h.handleFileUpload)
+
+	r.Get("/files/{id}", h.handleFile)
+	r.Get("/files/{id}/content", h.handleFileContent)
+	r.Delete("/files/{id}", h.handleFileDelete)
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	shared.WriteJson(w, v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	shared.WriteError(w, code, err)
+}
@@ -0,0 +1,215 @@
+package files
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/store"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) handleFiles(w http.ResponseWriter, r *http.Request) {
+	if h.Store == nil {
+		writeJson(w, FileList{Object: "list", Data: []File{}})
+		return
+	}
+
+	files, err := h.Store.ListFiles(r.Context())
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	files = slices.DeleteFunc(files, func(f store.File) bool {
+		return !store.IsOwner(r.Context(), f.Owner)
+	})
+
+	query := r.URL.Query()
+
+	if purpose := query.Get("purpose"); purpose != "" {
+		files = slices.DeleteFunc(files, func(f store.File) bool {
+			return f.Purpose != purpose
+		})
+	}
+
+	// OpenAI lists files newest first unless order=asc
+	if query.Get("order") != "asc" {
+		slices.Reverse(files)
+	}
+
+	if after := query.Get("after"); after != "" {
+		for i, f := range files {
+			if f.ID == after {
+				files = files[i+1:]
+				break
+			}
+		}
+	}
+
+	limit := 10000
+
+	if val, err := strconv.Atoi(query.Get("limit")); err == nil && val > 0 {
+		limit = min(val, 10000)
+	}
+
+	hasMore := len(files) > limit
+
+	if hasMore {
+		files = files[:limit]
+	}
+
+	result := FileList{
+		Object: "list",
+		Data:   []File{},
+
+		HasMore: hasMore,
+	}
+
+	for _, f := range files {
+		result.Data = append(result.Data, toFile(f))
+	}
+
+	if len(files) > 0 {
+		result.FirstID = files[0].ID
+		result.LastID = files[len(files)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleFileUpload(w http.ResponseWriter, r *http.Request) {
+	if h.Store == nil {
+		writeError(w, http.StatusNotImplemented, errNoStore)
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	purpose := r.FormValue("purpose")
+
+	if purpose == "" {
+		writeError(w, http.StatusBadRequest, errors.New("purpose is required"))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	f := &store.File{
+		ID:    "file-" + uuid.NewString(),
+		Owner: store.Owner(r.Context()),
+
+		Name:    header.Filename,
+		Purpose: purpose,
+
+		Size: int64(len(content)),
+
+		CreatedAt: time.Now(),
+	}
+
+	if err := h.Store.SaveFile(r.Context(), f, content); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, toFile(*f))
+}
+
+func (h *Handler) handleFile(w http.ResponseWriter, r *http.Request) {
+	f, ok := h.loadFile(w, r)
+
+	if !ok {
+		return
+	}
+
+	writeJson(w, toFile(*f))
+}
+
+func (h *Handler) handleFileContent(w http.ResponseWriter, r *http.Request) {
+	f, ok := h.loadFile(w, r)
+
+	if !ok {
+		return
+	}
+
+	content, err := h.Store.GetFileContent(r.Context(), f.ID)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(content)
+}
+
+func (h *Handler) handleFileDelete(w http.ResponseWriter, r *http.Request) {
+	f, ok := h.loadFile(w, r)
+
+	if !ok {
+		return
+	}
+
+	if err := h.Store.DeleteFile(r.Context(), f.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, FileDeleted{
+		ID:      f.ID,
+		Object:  "file",
+		Deleted: true,
+	})
+}
+
+// loadFile reads the file of the request path. Files of other users are
+// reported as not found.
+func (h *Handler) loadFile(w http.ResponseWriter, r *http.Request) (*store.File, bool) {
+	id := chi.URLParam(r, "id")
+
+	if h.Store == nil {
+		writeError(w, http.StatusNotFound, errors.New("file not found: "+id))
+		return nil, false
+	}
+
+	f, err := h.Store.GetFile(r.Context(), id)
+
+	if err == nil && !store.IsOwner(r.Context(), f.Owner) {
+		err = store.ErrNotFound
+	}
+
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errors.New("file not found: "+id))
+			return nil, false
+		}
+
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	return f, true
+}
@@ -0,0 +1,53 @@
+package files
+
+import (
+	"github.com/adrianliechti/wingman/pkg/store"
+)
+
+// https://platform.openai.com/docs/api-reference/files/object
+type File struct {
+	Object string `json:"object"` // "file"
+
+	ID        string `json:"id"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+
+	Filename string `json:"filename"`
+	Purpose  string `json:"purpose"`
+
+	Status string `json:"status"` // "processed"
+}
+
+// https://platform.openai.com/docs/api-reference/files/list
+type FileList struct {
+	Object string `json:"object"` // "list"
+
+	Data []File `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+
+	HasMore bool `json:"has_more"`
+}
+
+// https://platform.openai.com/docs/api-reference/files/delete
+type FileDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"` // "file"
+	Deleted bool   `json:"deleted"`
+}
+
+func toFile(f store.File) File {
+	return File{
+		Object: "file",
+
+		ID:        f.ID,
+		Bytes:     f.Size,
+		CreatedAt: f.CreatedAt.Unix(),
+
+		Filename: f.Name,
+		Purpose:  f.Purpose,
+
+		Status: "processed",
+	}
+}
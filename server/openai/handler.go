@@ -1,14 +1,19 @@
 package openai
 
 import (
+	"net/http"
+
 	"github.com/adrianliechti/wingman/config"
 	"github.com/adrianliechti/wingman/server/openai/audio"
+	"github.com/adrianliechti/wingman/server/openai/batches"
 	"github.com/adrianliechti/wingman/server/openai/chat"
 	"github.com/adrianliechti/wingman/server/openai/embeddings"
+	"github.com/adrianliechti/wingman/server/openai/files"
 	"github.com/adrianliechti/wingman/server/openai/image"
 	"github.com/adrianliechti/wingman/server/openai/models"
 	"github.com/adrianliechti/wingman/server/openai/realtime"
 	"github.com/adrianliechti/wingman/server/openai/responses"
+	"github.com/adrianliechti/wingman/server/openai/vectorstores"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -25,21 +30,42 @@ type Handler struct {
 	responses  *responses.Handler
 	embeddings *embeddings.Handler
 
+	vectorstores *vectorstores.Handler
+
+	files   *files.Handler
+	batches *batches.Handler
+
 	realtime *realtime.Handler
 }
 
-func New(cfg *config.Config) *Handler {
+func New(cfg *config.Config, background *responses.Background) *Handler {
+	chat := chat.New(cfg)
+	responses := responses.New(cfg, background)
+	embeddings := embeddings.New(cfg)
+
+	// batched requests are replayed against the same handlers
+	mux := chi.NewRouter()
+
+	chat.Attach(mux)
+	responses.Attach(mux)
+	embeddings.Attach(mux)
+
 	return &Handler{
 		Config: cfg,
 
 		models: models.New(cfg),
 
-		chat:  chat.New(cfg),
+		chat:  chat,
 		audio: audio.New(cfg),
 		image: image.New(cfg),
 
-		responses:  responses.New(cfg),
-		embeddings: embeddings.New(cfg),
+		responses:  responses,
+		embeddings: embeddings,
+
+		vectorstores: vectorstores.New(cfg),
+
+		files:   files.New(cfg),
+		batches: batches.New(cfg, mux),
 
 		realtime: realtime.New(),
 	}
@@ -55,7 +81,22 @@ func (h *Handler) Attach(r chi.Router) {
 	h.responses.Attach(r)
 	h.embeddings.Attach(r)
 
+	h.vectorstores.Attach(r)
+
+	h.files.Attach(r)
+	h.batches.Attach(r)
+
 	if h.realtime != nil {
 		h.realtime.Attach(r)
 	}
 }
+
+// HandleModels and HandleModel serve the model list in the OpenAI format. The
+// server routes them itself, as the route is shared with the Anthropic API.
+func (h *Handler) HandleModels(w http.ResponseWriter, r *http.Request) {
+	h.models.HandleModels(w, r)
+}
+
+func (h *Handler) HandleModel(w http.ResponseWriter, r *http.Request) {
+	h.models.HandleModel(w, r)
+}
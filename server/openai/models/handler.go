@@ -22,8 +22,8 @@ func New(cfg *config.Config) *Handler {
 }
 
 func (h *Handler) Attach(r chi.Router) {
-	r.Get("/models", h.handleModels)
-	r.Get("/models/{id}", h.handleModel)
+	r.Get("/models", h.HandleModels)
+	r.Get("/models/{id}", h.HandleModel)
 }
 
 func writeJson(w http.ResponseWriter, v any) {
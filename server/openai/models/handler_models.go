@@ -5,9 +5,10 @@ import (
 	"time"
 
 	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/provider"
 )
 
-func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) HandleModels(w http.ResponseWriter, r *http.Request) {
 	result := &ModelList{
 		Object: "list",
 	}
@@ -17,19 +18,13 @@ func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		result.Models = append(result.Models, Model{
-			Object: "model",
-
-			ID:      m.ID,
-			Created: time.Now().Unix(),
-			OwnedBy: "openai",
-		})
+		result.Models = append(result.Models, toModel(m))
 	}
 
 	writeJson(w, result)
 }
 
-func (h *Handler) handleModel(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) HandleModel(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
 	if err := h.Policy.Verify(r.Context(), policy.ResourceModel, id, policy.ActionAccess); err != nil {
@@ -44,13 +39,45 @@ func (h *Handler) handleModel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := &Model{
+	writeJson(w, toModel(*model))
+}
+
+func toModel(m provider.Model) Model {
+	c := m.Capabilities
+
+	result := Model{
 		Object: "model",
 
-		ID:      model.ID,
+		ID:      m.ID,
 		Created: time.Now().Unix(),
 		OwnedBy: "openai",
+
+		Name:        m.Name,
+		Description: m.Description,
+
+		ContextWindow:   c.ContextWindow,
+		MaxOutputTokens: c.MaxOutputTokens,
 	}
 
-	writeJson(w, result)
+	for _, m := range c.InputModalities {
+		result.InputModalities = append(result.InputModalities, string(m))
+	}
+
+	for _, m := range c.OutputModalities {
+		result.OutputModalities = append(result.OutputModalities, string(m))
+	}
+
+	if c.Tools != nil || c.Reasoning != nil || c.StructuredOutput != nil {
+		result.Capabilities = &ModelCapabilities{
+			Tools:            c.Tools,
+			Reasoning:        c.Reasoning,
+			StructuredOutput: c.StructuredOutput,
+		}
+	}
+
+	if c.DeprecationDate != nil {
+		result.DeprecationDate = c.DeprecationDate.Format(time.DateOnly)
+	}
+
+	return result
 }
@@ -1,12 +1,33 @@
 package models
 
 // https://platform.openai.com/docs/api-reference/models/object
+//
+// The fields after owned_by are wingman extensions describing the model.
 type Model struct {
 	Object string `json:"object"` // "model"
 
 	ID      string `json:"id"`
 	Created int64  `json:"created"`
 	OwnedBy string `json:"owned_by"`
+
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	ContextWindow   int `json:"context_window,omitempty"`
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+
+	InputModalities  []string `json:"input_modalities,omitempty"`
+	OutputModalities []string `json:"output_modalities,omitempty"`
+
+	Capabilities *ModelCapabilities `json:"capabilities,omitempty"`
+
+	DeprecationDate string `json:"deprecation_date,omitempty"`
+}
+
+type ModelCapabilities struct {
+	Tools            *bool `json:"tools,omitempty"`
+	Reasoning        *bool `json:"reasoning,omitempty"`
+	StructuredOutput *bool `json:"structured_output,omitempty"`
 }
 
 // https://platform.openai.com/docs/api-reference/models
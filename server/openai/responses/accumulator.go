@@ -55,6 +55,11 @@ const (
 	// Compaction events
 	StreamEventCompactionItemAdded StreamEventType = "compaction_item.added"
 	StreamEventCompactionItemDone  StreamEventType = "compaction_item.done"
+
+	// Hosted call events (tools the gateway runs itself)
+	StreamEventHostedCallAdded    StreamEventType = "hosted_call.added"
+	StreamEventHostedCallProgress StreamEventType = "hosted_call.progress"
+	StreamEventHostedCallDone     StreamEventType = "hosted_call.done"
 )
 
 // StreamEvent represents a streaming event with its data
@@ -95,6 +100,10 @@ type StreamEvent struct {
 	CompactionContent          string
 	CompactionEncryptedContent string
 
+	// For hosted call events
+	HostedItem  *ResponseOutput
+	HostedPhase string // e.g. in_progress, searching, completed
+
 	// For error events
 	Error error
 
@@ -995,6 +1004,67 @@ func (s *StreamingAccumulator) Add(c provider.Completion) error {
 	return nil
 }
 
+// AddHostedCall emits the output item of a tool call the gateway runs itself,
+// followed by its progress phases, and returns the item's output index.
+// Reasoning in flight is closed first; a message already streaming stays
+// open and continues after the call.
+func (s *StreamingAccumulator) AddHostedCall(item *ResponseOutput, phases ...string) (int, error) {
+	if err := s.start(); err != nil {
+		return 0, err
+	}
+
+	if err := s.closeCompaction(); err != nil {
+		return 0, err
+	}
+
+	if err := s.closeReasoning(); err != nil {
+		return 0, err
+	}
+
+	index := s.reserveOutputIndex()
+
+	if err := s.emitEvent(StreamEvent{
+		Type:        StreamEventHostedCallAdded,
+		HostedItem:  item,
+		OutputIndex: index,
+	}); err != nil {
+		return index, err
+	}
+
+	for _, phase := range phases {
+		if err := s.emitEvent(StreamEvent{
+			Type:        StreamEventHostedCallProgress,
+			HostedItem:  item,
+			HostedPhase: phase,
+			OutputIndex: index,
+		}); err != nil {
+			return index, err
+		}
+	}
+
+	return index, nil
+}
+
+// HostedCallDone emits the final phase and output item of a hosted call.
+func (s *StreamingAccumulator) HostedCallDone(index int, item *ResponseOutput, phase string) error {
+	if phase != "" {
+		if err := s.emitEvent(StreamEvent{
+			Type:        StreamEventHostedCallProgress,
+			HostedItem:  item,
+			HostedPhase: phase,
+			OutputIndex: index,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return s.emitEvent(StreamEvent{
+		Type:        StreamEventHostedCallDone,
+		HostedItem:  item,
+		OutputIndex: index,
+	})
+}
+
 // Complete signals that streaming is done and emits final events.
 func (s *StreamingAccumulator) Complete() error {
 	if err := s.start(); err != nil {
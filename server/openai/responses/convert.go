@@ -661,7 +661,13 @@ func toTools(tools []Tool) ([]provider.Tool, error) {
 			// web_search is hosted by OpenAI and is unavailable on BYOK
 			// backends such as Azure. Codex advertises it even when using a
 			// custom provider, so accept and omit it instead of rejecting the
-			// entire request.
+			// entire request. Models with a searcher bound run it in the
+			// gateway instead (see hostedTools).
+			continue
+
+		case ToolTypeFileSearch, ToolTypeCodeInterpreter, ToolTypeImageGeneration:
+			// Run by the gateway itself (see hostedTools), which rejects the
+			// request when it cannot back them.
 			continue
 
 		default:
@@ -15,11 +15,15 @@ import (
 
 type Handler struct {
 	*config.Config
+
+	background *Background
 }
 
-func New(cfg *config.Config) *Handler {
+func New(cfg *config.Config, background *Background) *Handler {
 	h := &Handler{
 		Config: cfg,
+
+		background: background,
 	}
 
 	return h
@@ -28,6 +32,11 @@ func New(cfg *config.Config) *Handler {
 func (h *Handler) Attach(r chi.Router) {
 	r.Post("/responses", h.handleResponses)
 	r.Post("/responses/input_tokens", h.handleInputTokens)
+
+	r.Get("/responses/{id}", h.handleResponseGet)
+	r.Delete("/responses/{id}", h.handleResponseDelete)
+	r.Post("/responses/{id}/cancel", h.handleResponseCancel)
+	r.Get("/responses/{id}/input_items", h.handleResponseInputItems)
 }
 
 func writeJson(w http.ResponseWriter, v any) {
@@ -0,0 +1,675 @@
+package responses
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/store"
+	"github.com/adrianliechti/wingman/server/openai/shared"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// stopTimeout bounds how long Shutdown waits for cancelled jobs to save
+// their state.
+const stopTimeout = 5 * time.Second
+
+// Background runs background responses. One instance outlives the handlers
+// of successive configurations: a response started before a config reload
+// can still be polled and cancelled afterwards, and it saves its state to
+// the store of the latest configuration.
+type Background struct {
+	mu    sync.Mutex
+	store store.Provider
+
+	jobs sync.Map
+	wg   sync.WaitGroup
+}
+
+func NewBackground() *Background {
+	return &Background{}
+}
+
+// UseStore makes jobs save to the store of a new configuration. Without a
+// store, they keep saving to the previous one.
+func (b *Background) UseStore(s store.Provider) {
+	if s == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.store = s
+	b.mu.Unlock()
+}
+
+// Shutdown waits for the running jobs to finish until ctx is done, then
+// cancels the remaining ones and waits for them to save their state.
+func (b *Background) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+	}
+
+	b.jobs.Range(func(_, job any) bool {
+		job.(*backgroundJob).stop()
+		return true
+	})
+
+	select {
+	case <-done:
+		return nil
+
+	case <-time.After(stopTimeout):
+		return ctx.Err()
+	}
+}
+
+// job returns the running job of a response of the caller.
+func (b *Background) job(r *http.Request, id string) (*backgroundJob, bool) {
+	value, ok := b.jobs.Load(id)
+
+	if !ok {
+		return nil, false
+	}
+
+	job := value.(*backgroundJob)
+
+	if !store.IsOwner(r.Context(), job.owner) {
+		return nil, false
+	}
+
+	return job, true
+}
+
+// latest returns the store jobs save to, initially the one of the handler
+// that started the first job.
+func (b *Background) latest(initial store.Provider) store.Provider {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.store == nil {
+		b.store = initial
+	}
+
+	return b.store
+}
+
+// backgroundJob is a background response in flight. It stands in for the
+// client connection of the streaming handler and records the events it
+// writes, so they can be streamed to any number of readers.
+type backgroundJob struct {
+	owner  string
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+
+	events   []backgroundEvent
+	response json.RawMessage
+
+	// status and body hold a plain error reply of the streaming handler
+	status int
+	body   bytes.Buffer
+
+	pending   []byte
+	cancelled bool
+
+	changed chan struct{}
+	done    chan struct{}
+}
+
+type backgroundEvent struct {
+	Type           string `json:"type"`
+	SequenceNumber int    `json:"sequence_number"`
+
+	Response json.RawMessage `json:"response,omitempty"`
+
+	data json.RawMessage
+}
+
+func newBackgroundJob(response json.RawMessage) *backgroundJob {
+	return &backgroundJob{
+		response: response,
+
+		changed: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// handleResponsesBackground stores the turn as queued and runs it detached
+// from the request. The reply is the queued response, or its event stream
+// when the client asked to stream.
+func (h *Handler) handleResponsesBackground(w http.ResponseWriter, r *http.Request, req ResponsesRequest, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions, hosted *hostedTools) {
+	queued := &Response{
+		ID:        "resp_" + uuid.NewString(),
+		CreatedAt: time.Now().Unix(),
+		Status:    "queued",
+		Model:     req.Model,
+	}
+
+	responseDefaults(queued, req)
+
+	if err := h.saveResponse(r, req, queued); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	data, err := json.Marshal(queued)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+
+	job := newBackgroundJob(data)
+	job.owner = store.Owner(r.Context())
+	job.cancel = cancel
+
+	// the job follows the store across config reloads
+	b := h.background
+	b.latest(h.Store)
+
+	cfg := *h.Config
+	cfg.Store = &latestStore{b}
+
+	jh := &Handler{Config: &cfg, background: b}
+
+	b.jobs.Store(queued.ID, job)
+	b.wg.Add(1)
+
+	go func() {
+		defer b.wg.Done()
+		defer cancel()
+
+		jh.handleResponsesStream(job, r.WithContext(ctx), req, queued.ID, completer, messages, options, hosted)
+
+		if err := jh.finishBackground(context.WithoutCancel(ctx), queued, job); err != nil {
+			slog.Error("background response failed", "response", queued.ID, "error", err)
+		}
+
+		b.jobs.Delete(queued.ID)
+		job.close()
+	}()
+
+	if !req.Stream {
+		writeJson(w, queued)
+		return
+	}
+
+	h.streamBackground(w, r, job, -1)
+}
+
+// finishBackground persists the final state of a background response along
+// with its events. Completed and incomplete turns were already saved by the
+// streaming handler; failed and cancelled ones are saved here. A store
+// swapped in by a config reload lacks the queued turn, which is then saved
+// without its input.
+func (h *Handler) finishBackground(ctx context.Context, queued *Response, job *backgroundJob) error {
+	stored, err := h.Store.GetResponse(ctx, queued.ID)
+
+	if errors.Is(err, store.ErrNotFound) {
+		stored, err = &store.Response{
+			ID:    queued.ID,
+			Owner: job.owner,
+
+			Model: queued.Model,
+			Data:  job.snapshot(),
+
+			CreatedAt: time.Unix(queued.CreatedAt, 0),
+		}, nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	var last struct {
+		Status string `json:"status"`
+	}
+
+	json.Unmarshal(job.response, &last)
+
+	switch {
+	case job.cancelled && last.Status != "completed" && last.Status != "incomplete":
+		resp := *queued
+		resp.Status = "cancelled"
+
+		if stored.Data, err = json.Marshal(resp); err != nil {
+			return err
+		}
+
+	case job.status >= http.StatusBadRequest:
+		var reply shared.ErrorResponse
+
+		json.Unmarshal(job.body.Bytes(), &reply)
+
+		resp := *queued
+		resp.Status = "failed"
+		resp.Error = &ResponseError{
+			Code:    "server_error",
+			Message: cmp.Or(reply.Error.Message, strings.TrimSpace(job.body.String())),
+		}
+
+		if stored.Data, err = json.Marshal(resp); err != nil {
+			return err
+		}
+
+		// the upstream failed before the first event, so readers of the
+		// stream learn about it from a response.failed event of our own
+		event := ResponseFailedEvent{
+			Type:           "response.failed",
+			SequenceNumber: len(job.events),
+			Response:       &resp,
+		}
+
+		data, err := json.Marshal(event)
+
+		if err != nil {
+			return err
+		}
+
+		job.events = append(job.events, backgroundEvent{
+			Type:           event.Type,
+			SequenceNumber: event.SequenceNumber,
+
+			data: data,
+		})
+
+	case last.Status == "failed":
+		stored.Data = job.response
+	}
+
+	events := make([]json.RawMessage, len(job.events))
+
+	for i, e := range job.events {
+		events[i] = e.data
+	}
+
+	if stored.Events, err = json.Marshal(events); err != nil {
+		return err
+	}
+
+	job.response = stored.Data
+
+	return h.Store.SaveResponse(ctx, stored)
+}
+
+// streamBackground writes the events of a running job after the given
+// sequence number until the job finished or the client went away.
+func (h *Handler) streamBackground(w http.ResponseWriter, r *http.Request, job *backgroundJob, after int) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for next := 0; ; {
+		events, changed, done := job.since(next)
+
+		for _, e := range events {
+			next++
+
+			if e.SequenceNumber <= after {
+				continue
+			}
+
+			if err := writeEvent(w, e.Type, e.data); err != nil {
+				return
+			}
+		}
+
+		if done {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleResponseStream resumes the event stream of a background response
+// after the sequence number in starting_after.
+func (h *Handler) handleResponseStream(w http.ResponseWriter, r *http.Request, id string) {
+	after := -1
+
+	if val := r.URL.Query().Get("starting_after"); val != "" {
+		n, err := strconv.Atoi(val)
+
+		if err != nil {
+			writeError(w, http.StatusBadRequest, &shared.Error{
+				Param:   "starting_after",
+				Message: "Invalid 'starting_after': expected an integer.",
+			})
+			return
+		}
+
+		after = n
+	}
+
+	if job, ok := h.background.job(r, id); ok {
+		h.streamBackground(w, r, job, after)
+		return
+	}
+
+	response, ok := h.loadResponse(w, r)
+
+	if !ok {
+		return
+	}
+
+	if len(response.Events) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("only responses created with background=true can be streamed"))
+		return
+	}
+
+	var events []json.RawMessage
+
+	if err := json.Unmarshal(response.Events, &events); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	job := newBackgroundJob(response.Data)
+
+	for _, data := range events {
+		job.record(data)
+	}
+
+	job.close()
+
+	h.streamBackground(w, r, job, after)
+}
+
+// handleResponseCancel stops a background response. Cancelling a cancelled
+// response is a no-op; finished ones cannot be cancelled.
+func (h *Handler) handleResponseCancel(w http.ResponseWriter, r *http.Request) {
+	if job, ok := h.background.job(r, chi.URLParam(r, "id")); ok {
+		job.stop()
+
+		select {
+		case <-job.done:
+		case <-r.Context().Done():
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(job.snapshot())
+		return
+	}
+
+	response, ok := h.loadResponse(w, r)
+
+	if !ok {
+		return
+	}
+
+	var state struct {
+		Status     string `json:"status"`
+		Background bool   `json:"background"`
+	}
+
+	if err := json.Unmarshal(response.Data, &state); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !state.Background {
+		writeError(w, http.StatusBadRequest, errors.New("only responses created with background=true can be cancelled"))
+		return
+	}
+
+	switch state.Status {
+	case "cancelled":
+
+	case "queued", "in_progress":
+		// The job is gone (e.g. after a restart) and never finished
+		var data map[string]any
+
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		data["status"] = "cancelled"
+
+		d, err := json.Marshal(data)
+
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		response.Data = d
+
+		if err := h.Store.SaveResponse(r.Context(), response); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("cannot cancel response with status %s", state.Status))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(response.Data)
+}
+
+// Header, Write and WriteHeader let the job take the place of the client
+// connection of the streaming handler.
+func (j *backgroundJob) Header() http.Header {
+	return http.Header{}
+}
+
+func (j *backgroundJob) WriteHeader(status int) {
+	j.mu.Lock()
+	j.status = status
+	j.mu.Unlock()
+}
+
+func (j *backgroundJob) Write(p []byte) (int, error) {
+	j.mu.Lock()
+
+	if j.status >= http.StatusBadRequest {
+		j.body.Write(p)
+		j.mu.Unlock()
+
+		return len(p), nil
+	}
+
+	j.pending = append(j.pending, p...)
+
+	var blocks [][]byte
+
+	for {
+		block, rest, ok := bytes.Cut(j.pending, []byte("\n\n"))
+
+		if !ok {
+			break
+		}
+
+		blocks = append(blocks, block)
+		j.pending = rest
+	}
+
+	j.mu.Unlock()
+
+	for _, block := range blocks {
+		for line := range strings.Lines(string(block)) {
+			if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+				j.record(json.RawMessage(data))
+			}
+		}
+	}
+
+	return len(p), nil
+}
+
+func (j *backgroundJob) Flush() {
+}
+
+// record adds an event and wakes up the readers. Events arriving after the
+// job was cancelled are dropped.
+func (j *backgroundJob) record(data json.RawMessage) {
+	var e backgroundEvent
+
+	if err := json.Unmarshal(data, &e); err != nil {
+		return
+	}
+
+	e.data = data
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.cancelled {
+		return
+	}
+
+	j.events = append(j.events, e)
+
+	if len(e.Response) > 0 {
+		j.response = e.Response
+	}
+
+	if j.isDone() {
+		return
+	}
+
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+func (j *backgroundJob) isDone() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// since returns the events from the given position, a channel closed on the
+// next event and whether the job finished.
+func (j *backgroundJob) since(next int) ([]backgroundEvent, <-chan struct{}, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var events []backgroundEvent
+
+	if next < len(j.events) {
+		events = j.events[next:]
+	}
+
+	return events, j.changed, j.isDone()
+}
+
+// snapshot returns the latest state of the response.
+func (j *backgroundJob) snapshot() json.RawMessage {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.response
+}
+
+func (j *backgroundJob) stop() {
+	j.mu.Lock()
+	j.cancelled = true
+	j.mu.Unlock()
+
+	if j.cancel != nil {
+		j.cancel()
+	}
+}
+
+func (j *backgroundJob) close() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	close(j.done)
+	close(j.changed)
+}
+
+// latestStore is the store of a background job. It forwards to the store of
+// the latest configuration, which may change while the job runs.
+type latestStore struct {
+	b *Background
+}
+
+func (s *latestStore) current() store.Provider {
+	return s.b.latest(nil)
+}
+
+func (s *latestStore) GetResponse(ctx context.Context, id string) (*store.Response, error) {
+	return s.current().GetResponse(ctx, id)
+}
+
+func (s *latestStore) SaveResponse(ctx context.Context, response *store.Response) error {
+	return s.current().SaveResponse(ctx, response)
+}
+
+func (s *latestStore) DeleteResponse(ctx context.Context, id string) error {
+	return s.current().DeleteResponse(ctx, id)
+}
+
+func (s *latestStore) ListFiles(ctx context.Context) ([]store.File, error) {
+	return s.current().ListFiles(ctx)
+}
+
+func (s *latestStore) GetFile(ctx context.Context, id string) (*store.File, error) {
+	return s.current().GetFile(ctx, id)
+}
+
+func (s *latestStore) GetFileContent(ctx context.Context, id string) ([]byte, error) {
+	return s.current().GetFileContent(ctx, id)
+}
+
+func (s *latestStore) SaveFile(ctx context.Context, file *store.File, content []byte) error {
+	return s.current().SaveFile(ctx, file, content)
+}
+
+func (s *latestStore) DeleteFile(ctx context.Context, id string) error {
+	return s.current().DeleteFile(ctx, id)
+}
+
+func (s *latestStore) ListBatches(ctx context.Context) ([]store.Batch, error) {
+	return s.current().ListBatches(ctx)
+}
+
+func (s *latestStore) GetBatch(ctx context.Context, id string) (*store.Batch, error) {
+	return s.current().GetBatch(ctx, id)
+}
+
+func (s *latestStore) SaveBatch(ctx context.Context, batch *store.Batch) error {
+	return s.current().SaveBatch(ctx, batch)
+}
+
+func (s *latestStore) DeleteBatch(ctx context.Context, id string) error {
+	return s.current().DeleteBatch(ctx, id)
+}
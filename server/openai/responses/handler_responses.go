@@ -2,6 +2,7 @@ package responses
 
 import (
 	"encoding/json"
+	"errors"
 	"maps"
 	"net/http"
 	"slices"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/adrianliechti/wingman/pkg/policy"
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/store"
 	"github.com/adrianliechti/wingman/server/openai/shared"
 
 	"github.com/google/uuid"
@@ -35,14 +37,47 @@ func (h *Handler) handleResponses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	messages, err := toMessages(req.Input.Items, req.Instructions)
+	// Without a store there is no state to chain from, so the id is ignored
+	if h.Store == nil {
+		req.PreviousResponseID = ""
+	}
+
+	req.Store = new(h.storeEnabled(req))
+
+	if req.Background && !*req.Store {
+		writeError(w, http.StatusBadRequest, &shared.Error{
+			Param:   "background",
+			Message: "Background mode requires store=true and a configured store.",
+		})
+		return
+	}
+
+	items := req.Input.Items
+
+	if req.PreviousResponseID != "" {
+		history, err := h.historyItems(r, req.PreviousResponseID)
+
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				writeError(w, http.StatusNotFound, errors.New("previous response not found: "+req.PreviousResponseID))
+				return
+			}
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		items = append(history, items...)
+	}
+
+	messages, err := toMessages(items, req.Instructions)
 
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	req.Tools = requestTools(req.Tools, req.Input.Items)
+	req.Tools = requestTools(req.Tools, items)
 
 	tools, err := toTools(req.Tools)
 	if err != nil {
@@ -56,6 +91,7 @@ func (h *Handler) handleResponses(w http.ResponseWriter, r *http.Request) {
 
 		MaxTokens:   req.MaxOutputTokens,
 		Temperature: req.Temperature,
+		TopP:        req.TopP,
 	}
 
 	if req.ParallelToolCalls != nil && !*req.ParallelToolCalls {
@@ -183,10 +219,62 @@ func (h *Handler) handleResponses(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	hosted, err := h.hostedTools(r.Context(), req)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// the policy reviews the hosted tools next to the request's own
+	if hosted != nil {
+		options.Tools = slices.Concat(options.Tools, hosted.definitions)
+	}
+
+	if err := policy.Review(r.Context(), h.Policy, req.Model, messages, options); err != nil {
+		writeError(w, policy.CodeFromError(err, http.StatusInternalServerError), err)
+		return
+	}
+
+	if hosted != nil {
+		var ok bool
+
+		if options.Tools, ok = hosted.keep(options.Tools); !ok {
+			hosted = nil
+		}
+	}
+
+	// the model sees the hosted tools next to the request's own
+	checked := options
+
+	if hosted != nil {
+		checked = new(*options)
+		checked.Tools = slices.Concat(options.Tools, hosted.definitions)
+	}
+
+	if err := h.CheckCapabilities(req.Model, messages, checked); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if hosted != nil {
+		completer, err = hosted.completer(req.Model, completer)
+
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if req.Background {
+		h.handleResponsesBackground(w, r, req, completer, messages, options, hosted)
+		return
+	}
+
 	if req.Stream {
-		h.handleResponsesStream(w, r, req, completer, messages, options)
+		h.handleResponsesStream(w, r, req, "resp_"+uuid.NewString(), completer, messages, options, hosted)
 	} else {
-		h.handleResponsesComplete(w, r, req, completer, messages, options)
+		h.handleResponsesComplete(w, r, req, completer, messages, options, hosted)
 	}
 }
 
@@ -241,8 +329,8 @@ func responseUsage(usage *provider.Usage) *Usage {
 // responseDefaults populates the OpenAI-compatible default fields on a Response.
 func responseDefaults(resp *Response, req ResponsesRequest) {
 	resp.Object = "response"
-	resp.Background = false
-	resp.Store = false
+	resp.Background = req.Background
+	resp.Store = req.Store != nil && *req.Store
 	resp.ServiceTier = "default"
 
 	resp.ParallelToolCalls = true
@@ -256,6 +344,10 @@ func responseDefaults(resp *Response, req ResponsesRequest) {
 	}
 
 	resp.TopP = 1
+	if req.TopP != nil {
+		resp.TopP = *req.TopP
+	}
+
 	resp.TopLogprobs = 0
 	if req.Truncation != "" {
 		resp.Truncation = req.Truncation
@@ -269,6 +361,10 @@ func responseDefaults(resp *Response, req ResponsesRequest) {
 
 	resp.MaxOutputTokens = req.MaxOutputTokens
 
+	if req.PreviousResponseID != "" {
+		resp.PreviousResponseID = &req.PreviousResponseID
+	}
+
 	if resp.Status == "incomplete" && resp.IncompleteDetails == nil {
 		resp.IncompleteDetails = &IncompleteDetails{Reason: "max_output_tokens"}
 	}
@@ -592,10 +688,18 @@ func setOutputStatus(o *ResponseOutput, status string) {
 		o.ShellCallItem.Status = status
 	case o.ToolSearchCallItem != nil:
 		o.ToolSearchCallItem.Status = status
+	case o.WebSearchCallItem != nil:
+		o.WebSearchCallItem.Status = status
+	case o.FileSearchCallItem != nil:
+		o.FileSearchCallItem.Status = status
+	case o.CodeInterpreterCallItem != nil:
+		o.CodeInterpreterCallItem.Status = status
+	case o.ImageGenerationCallItem != nil:
+		o.ImageGenerationCallItem.Status = status
 	}
 }
 
-func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request, req ResponsesRequest, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions) {
+func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request, req ResponsesRequest, responseID string, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions, hosted *hostedTools) {
 	headersSent := false
 
 	sendHeaders := func() {
@@ -609,7 +713,6 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 
 	createdAt := time.Now().Unix()
 
-	responseID := "resp_" + uuid.NewString()
 	messageID := "msg_" + uuid.NewString()
 
 	seqNum := 0
@@ -644,10 +747,16 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 	accumulator := NewStreamingAccumulator(func(event StreamEvent) error {
 		switch event.Type {
 		case StreamEventResponseCreated:
+			status := "in_progress"
+
+			if req.Background {
+				status = "queued"
+			}
+
 			return writeEvent(w, "response.created", ResponseCreatedEvent{
 				Type:           "response.created",
 				SequenceNumber: nextSeq(),
-				Response:       createResponse("in_progress", []ResponseOutput{}),
+				Response:       createResponse(status, []ResponseOutput{}),
 			})
 
 		case StreamEventResponseInProgress:
@@ -719,7 +828,7 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 				Part: &OutputContent{
 					Type:        "output_text",
 					Text:        event.Text,
-					Annotations: []any{},
+					Annotations: hosted.citations(event.Text),
 					Logprobs:    []any{},
 				},
 			})
@@ -1201,7 +1310,7 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 				content = append(content, OutputContent{
 					Type:        "output_text",
 					Text:        event.Text,
-					Annotations: []any{},
+					Annotations: hosted.citations(event.Text),
 					Logprobs:    []any{},
 				})
 			}
@@ -1226,6 +1335,30 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 				},
 			})
 
+		case StreamEventHostedCallAdded, StreamEventHostedCallDone:
+			eventType := "response.output_item.added"
+
+			if event.Type == StreamEventHostedCallDone {
+				eventType = "response.output_item.done"
+			}
+
+			return writeEvent(w, eventType, HostedCallOutputItemEvent{
+				Type:           eventType,
+				SequenceNumber: nextSeq(),
+				OutputIndex:    event.OutputIndex,
+				Item:           event.HostedItem,
+			})
+
+		case StreamEventHostedCallProgress:
+			eventType := "response." + string(event.HostedItem.Type) + "." + event.HostedPhase
+
+			return writeEvent(w, eventType, HostedCallProgressEvent{
+				Type:           eventType,
+				SequenceNumber: nextSeq(),
+				OutputIndex:    event.OutputIndex,
+				ItemID:         hostedItemID(event.HostedItem),
+			})
+
 		case StreamEventResponseCompleted:
 			now := time.Now().Unix()
 			response := &Response{
@@ -1234,11 +1367,15 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 				CompletedAt: &now,
 				Status:      "completed",
 				Model:       responseModel(event.Completion, req.Model),
-				Output:      responseOutputs(event.Completion.Message, messageID, "completed", outputOpts),
+				Output:      hosted.outputs(responseOutputs(event.Completion.Message, messageID, "completed", outputOpts)),
 				Usage:       responseUsage(event.Completion.Usage),
 			}
 			responseDefaults(response, req)
 
+			if err := h.saveResponse(r, req, response); err != nil {
+				return err
+			}
+
 			return writeEvent(w, "response.completed", ResponseCompletedEvent{
 				Type:           "response.completed",
 				SequenceNumber: nextSeq(),
@@ -1251,11 +1388,15 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 				CreatedAt: createdAt,
 				Status:    "incomplete",
 				Model:     responseModel(event.Completion, req.Model),
-				Output:    responseOutputs(event.Completion.Message, messageID, "incomplete", outputOpts),
+				Output:    hosted.outputs(responseOutputs(event.Completion.Message, messageID, "incomplete", outputOpts)),
 				Usage:     responseUsage(event.Completion.Usage),
 			}
 			responseDefaults(response, req)
 
+			if err := h.saveResponse(r, req, response); err != nil {
+				return err
+			}
+
 			return writeEvent(w, "response.incomplete", ResponseIncompleteEvent{
 				Type:           "response.incomplete",
 				SequenceNumber: nextSeq(),
@@ -1301,6 +1442,20 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 	accumulator.ReasoningAsSummary = outputOpts.IncludeSummary
 	accumulator.SuppressReasoning = !outputOpts.IncludeReasoning
 
+	if hosted != nil {
+		hosted.started = func(call *hostedCall) {
+			sendHeaders()
+
+			phases, _ := hostedPhases(call.Item)
+			call.OutputIndex, _ = accumulator.AddHostedCall(call.Item, phases...)
+		}
+
+		hosted.done = func(call *hostedCall) {
+			_, phase := hostedPhases(call.Item)
+			accumulator.HostedCallDone(call.OutputIndex, call.Item, phase)
+		}
+	}
+
 	failed := false
 
 	// Iterate over completions from the provider
@@ -1337,7 +1492,7 @@ func (h *Handler) handleResponsesStream(w http.ResponseWriter, r *http.Request,
 	http.NewResponseController(w).Flush()
 }
 
-func (h *Handler) handleResponsesComplete(w http.ResponseWriter, r *http.Request, req ResponsesRequest, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions) {
+func (h *Handler) handleResponsesComplete(w http.ResponseWriter, r *http.Request, req ResponsesRequest, completer provider.Completer, messages []provider.Message, options *provider.CompleteOptions, hosted *hostedTools) {
 	acc := provider.CompletionAccumulator{}
 
 	for c, err := range completer.Complete(r.Context(), messages, options) {
@@ -1364,11 +1519,11 @@ func (h *Handler) handleResponsesComplete(w http.ResponseWriter, r *http.Request
 		CreatedAt: now,
 		Status:    responseStatus(completion.Status),
 		Model:     responseModel(completion, req.Model),
-		Output: responseOutputs(completion.Message, "msg_"+uuid.NewString(), responseStatus(completion.Status), responseOutputOptions{
+		Output: hosted.outputs(responseOutputs(completion.Message, "msg_"+uuid.NewString(), responseStatus(completion.Status), responseOutputOptions{
 			IncludeSummary:   options.ReasoningOptions != nil && options.ReasoningOptions.IncludeSummary,
 			IncludeReasoning: reasoningRequested(req),
 			Tools:            req.Tools,
-		}),
+		})),
 		Usage: responseUsage(completion.Usage),
 	}
 
@@ -1378,5 +1533,10 @@ func (h *Handler) handleResponsesComplete(w http.ResponseWriter, r *http.Request
 
 	responseDefaults(&result, req)
 
+	if err := h.saveResponse(r, req, &result); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
 	writeJson(w, result)
 }
@@ -268,7 +268,7 @@ func TestCodexCustomApplyPatchHTTPRoundTrip(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected HTTP 200, got %d: %s", rec.Code, rec.Body.String())
@@ -0,0 +1,212 @@
+package responses
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/store/memory"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const blockingTestModel = "blocking-test-model"
+
+// blockingCompleter never answers until its context is cancelled.
+type blockingCompleter struct{}
+
+func (blockingCompleter) Complete(ctx context.Context, _ []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		<-ctx.Done()
+		yield(nil, ctx.Err())
+	}
+}
+
+func newBackgroundHandler(t *testing.T) chi.Router {
+	t.Helper()
+
+	cfg := &config.Config{Policy: noop.New(), Store: memory.New()}
+	cfg.RegisterCompleter(storeTestModel, echoCompleter{})
+	cfg.RegisterCompleter(blockingTestModel, blockingCompleter{})
+
+	r := chi.NewRouter()
+	New(cfg, NewBackground()).Attach(r)
+
+	return r
+}
+
+// awaitStatus polls a response until it reaches the status.
+func awaitStatus(t *testing.T, r chi.Router, id, status string) map[string]any {
+	t.Helper()
+
+	for range 100 {
+		resp := decodeStoreResponse(t, serveStore(t, r, http.MethodGet, "/responses/"+id, ""))
+
+		if resp["status"] == status {
+			return resp
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("response %s never reached status %s", id, status)
+	return nil
+}
+
+func TestBackgroundResponseIsPolled(t *testing.T) {
+	r := newBackgroundHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","background":true,"input":"hello"}`))
+
+	if resp["status"] != "queued" || resp["background"] != true {
+		t.Fatalf("expected queued background response, got %v", resp)
+	}
+
+	done := awaitStatus(t, r, resp["id"].(string), "completed")
+
+	if output, _ := done["output"].([]any); len(output) != 1 || done["background"] != true {
+		t.Fatalf("unexpected completed response: %v", done)
+	}
+}
+
+func TestBackgroundStreamResumes(t *testing.T) {
+	r := newBackgroundHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","background":true,"input":"hello"}`))
+	id := resp["id"].(string)
+
+	awaitStatus(t, r, id, "completed")
+
+	full := serveStore(t, r, http.MethodGet, "/responses/"+id+"?stream=true", "").Body.String()
+
+	if !strings.Contains(full, `"type":"response.created"`) || !strings.Contains(full, `"status":"queued"`) || !strings.Contains(full, "event: response.completed") {
+		t.Fatalf("unexpected stream: %s", full)
+	}
+
+	resumed := serveStore(t, r, http.MethodGet, "/responses/"+id+"?stream=true&starting_after=2", "").Body.String()
+
+	if strings.Contains(resumed, `"sequence_number":2,`) || !strings.Contains(resumed, `"sequence_number":3,`) || !strings.Contains(resumed, "event: response.completed") {
+		t.Fatalf("unexpected resumed stream: %s", resumed)
+	}
+}
+
+func TestBackgroundResponseIsCancelled(t *testing.T) {
+	r := newBackgroundHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+blockingTestModel+`","background":true,"input":"hello"}`))
+	id := resp["id"].(string)
+
+	cancelled := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses/"+id+"/cancel", ""))
+
+	if cancelled["status"] != "cancelled" {
+		t.Fatalf("expected cancelled, got %v", cancelled["status"])
+	}
+
+	awaitStatus(t, r, id, "cancelled")
+
+	// cancelling again is a no-op
+	if again := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses/"+id+"/cancel", "")); again["status"] != "cancelled" {
+		t.Fatalf("expected cancelled, got %v", again["status"])
+	}
+}
+
+func TestCancelRejectsForegroundResponse(t *testing.T) {
+	r := newBackgroundHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","input":"hello"}`))
+
+	if rec := serveStore(t, r, http.MethodPost, "/responses/"+resp["id"].(string)+"/cancel", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestBackgroundRequiresStore(t *testing.T) {
+	h := newStoreHandler(t)
+
+	rec := postResponses(t, h, `{"model":"`+storeTestModel+`","background":true,"input":"hello"}`)
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"param":"background"`) {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestBackgroundResponseIsScopedToOwner(t *testing.T) {
+	r := newBackgroundHandler(t)
+
+	resp := decodeStoreResponse(t, serveStoreAs(t, r, "alice", http.MethodPost, "/responses", `{"model":"`+blockingTestModel+`","background":true,"input":"hello"}`))
+	id := resp["id"].(string)
+
+	t.Cleanup(func() { serveStoreAs(t, r, "alice", http.MethodPost, "/responses/"+id+"/cancel", "") })
+
+	for _, path := range []string{"/responses/" + id, "/responses/" + id + "?stream=true"} {
+		if rec := serveStoreAs(t, r, "bob", http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("GET %s: expected 404, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+	}
+
+	if got := decodeStoreResponse(t, serveStoreAs(t, r, "alice", http.MethodGet, "/responses/"+id, "")); got["status"] != "queued" {
+		t.Fatalf("expected queued, got %v", got["status"])
+	}
+}
+
+func TestBackgroundResponseFollowsStore(t *testing.T) {
+	background := NewBackground()
+
+	handler := func(s *memory.Store) chi.Router {
+		cfg := &config.Config{Policy: noop.New(), Store: s}
+		cfg.RegisterCompleter(blockingTestModel, blockingCompleter{})
+
+		r := chi.NewRouter()
+		New(cfg, background).Attach(r)
+
+		return r
+	}
+
+	before := handler(memory.New())
+
+	resp := decodeStoreResponse(t, serveStore(t, before, http.MethodPost, "/responses", `{"model":"`+blockingTestModel+`","background":true,"input":"hello"}`))
+	id := resp["id"].(string)
+
+	// a reload swaps in a new store while the response runs
+	latest := memory.New()
+	background.UseStore(latest)
+
+	after := handler(latest)
+
+	if got := decodeStoreResponse(t, serveStore(t, after, http.MethodGet, "/responses/"+id, "")); got["status"] != "queued" {
+		t.Fatalf("expected queued, got %v", got["status"])
+	}
+
+	decodeStoreResponse(t, serveStore(t, after, http.MethodPost, "/responses/"+id+"/cancel", ""))
+
+	awaitStatus(t, after, id, "cancelled")
+}
+
+func TestBackgroundShutdownCancelsJobs(t *testing.T) {
+	background := NewBackground()
+
+	cfg := &config.Config{Policy: noop.New(), Store: memory.New()}
+	cfg.RegisterCompleter(blockingTestModel, blockingCompleter{})
+
+	r := chi.NewRouter()
+	New(cfg, background).Attach(r)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+blockingTestModel+`","background":true,"input":"hello"}`))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := background.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := decodeStoreResponse(t, serveStore(t, r, http.MethodGet, "/responses/"+resp["id"].(string), "")); got["status"] != "cancelled" {
+		t.Fatalf("expected cancelled, got %v", got["status"])
+	}
+}
@@ -78,7 +78,7 @@ func TestCustomToolCallStreamsInputDeltas(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -138,7 +138,7 @@ func TestApplyPatchAsCustomToolSuppressesArgDeltas(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -181,7 +181,7 @@ func TestApplyPatchNativeStreamsAddedAndDone(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -234,7 +234,7 @@ func TestFunctionToolStillUsesFunctionEvents(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -310,7 +310,7 @@ func TestNamespacedCustomToolCallCarriesNamespace(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -371,7 +371,7 @@ func TestCompactionTriggerInputItemAccepted(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -51,7 +51,7 @@ func TestResponseErrorPrecedesResponseFailed(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	stream := rec.Body.String()
 
@@ -96,7 +96,7 @@ func TestResponseErrorOnUpfrontFailureReturnsHTTPError(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusBadGateway {
 		t.Fatalf("expected 502, got %d: %s", rec.Code, rec.Body.String())
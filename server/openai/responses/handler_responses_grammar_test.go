@@ -65,7 +65,7 @@ func TestCustomFreeformGrammarToolPassesThroughToProvider(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -115,7 +115,7 @@ func TestApplyPatchCustomToolCarriesFormat(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
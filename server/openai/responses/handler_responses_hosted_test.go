@@ -0,0 +1,237 @@
+package responses
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/provider"
+)
+
+const hostedTestModel = "hosted-test-model"
+
+type fakeIndex struct {
+	index.Provider
+
+	results []index.Result
+}
+
+func (i *fakeIndex) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	return i.results, nil
+}
+
+type fakeRenderer struct{}
+
+func (fakeRenderer) Render(ctx context.Context, input string, options *provider.RenderOptions) (*provider.Rendering, error) {
+	return &provider.Rendering{
+		Content:     []byte("png"),
+		ContentType: "image/png",
+	}, nil
+}
+
+// toolCallingCompleter calls a tool once, then answers with a fixed text.
+type toolCallingCompleter struct {
+	name      string
+	arguments string
+
+	answer string
+}
+
+func (c toolCallingCompleter) Complete(_ context.Context, messages []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		content := provider.ToolCallContent(provider.ToolCall{
+			ID:        "call_1",
+			Name:      c.name,
+			Arguments: c.arguments,
+		})
+
+		for _, m := range messages {
+			for _, part := range m.Content {
+				if part.ToolResult != nil {
+					content = provider.TextContent(c.answer)
+				}
+			}
+		}
+
+		yield(&provider.Completion{
+			Status: provider.CompletionStatusCompleted,
+			Message: &provider.Message{
+				Role:    provider.MessageRoleAssistant,
+				Content: []provider.Content{content},
+			},
+		}, nil)
+	}
+}
+
+func newHostedHandler(t *testing.T, completer provider.Completer) *Handler {
+	t.Helper()
+
+	cfg := &config.Config{Policy: noop.New()}
+	cfg.RegisterCompleter(hostedTestModel, completer)
+
+	cfg.RegisterIndex("docs", &fakeIndex{
+		results: []index.Result{
+			{Document: index.Document{ID: "doc-1", Title: "handbook.md", Content: "Vacation is 25 days."}, Score: 0.9},
+			{Document: index.Document{ID: "doc-2", Title: "faq.md", Content: "Ask HR."}, Score: 0.5},
+		},
+	})
+
+	cfg.RegisterHostedTools(hostedTestModel, &config.HostedTools{
+		ImageGeneration: fakeRenderer{},
+	})
+
+	return New(cfg, NewBackground())
+}
+
+func TestHostedFileSearch(t *testing.T) {
+	h := newHostedHandler(t, toolCallingCompleter{
+		name:      "retrieve",
+		arguments: `{"query":"vacation days"}`,
+		answer:    "You get 25 days [1].",
+	})
+
+	rec := postResponses(t, h, `{
+		"model": "`+hostedTestModel+`",
+		"input": "how many vacation days?",
+		"include": ["file_search_call.results"],
+		"tools": [{"type": "file_search", "vector_store_ids": ["docs"]}]
+	}`)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Output []map[string]any `json:"output"`
+	}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Output) != 2 || resp.Output[0]["type"] != "file_search_call" || resp.Output[1]["type"] != "message" {
+		t.Fatalf("output = %v", resp.Output)
+	}
+
+	call := resp.Output[0]
+
+	if call["status"] != "completed" || call["queries"].([]any)[0] != "vacation days" || len(call["results"].([]any)) != 2 {
+		t.Errorf("file_search_call = %v", call)
+	}
+
+	content := resp.Output[1]["content"].([]any)[0].(map[string]any)
+	annotations := content["annotations"].([]any)
+
+	if len(annotations) != 1 {
+		t.Fatalf("annotations = %v", annotations)
+	}
+
+	citation := annotations[0].(map[string]any)
+
+	if citation["type"] != "file_citation" || citation["file_id"] != "doc-1" || citation["filename"] != "handbook.md" || citation["index"] != float64(16) {
+		t.Errorf("citation = %v", citation)
+	}
+}
+
+func TestHostedFileSearchUnknownVectorStore(t *testing.T) {
+	h := newHostedHandler(t, toolCallingCompleter{})
+
+	rec := postResponses(t, h, `{
+		"model": "`+hostedTestModel+`",
+		"input": "hello",
+		"tools": [{"type": "file_search", "vector_store_ids": ["missing"]}]
+	}`)
+
+	if rec.Code != 400 || !strings.Contains(rec.Body.String(), `"param":"tools[0].vector_store_ids"`) {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHostedImageGenerationStream(t *testing.T) {
+	h := newHostedHandler(t, toolCallingCompleter{
+		name:      "generate_image",
+		arguments: `{"prompt":"a gopher"}`,
+		answer:    "Here is your gopher.",
+	})
+
+	rec := postResponses(t, h, `{
+		"model": "`+hostedTestModel+`",
+		"input": "draw a gopher",
+		"tools": [{"type": "image_generation"}],
+		"stream": true
+	}`)
+
+	body := rec.Body.String()
+
+	for _, event := range []string{
+		"event: response.image_generation_call.in_progress",
+		"event: response.image_generation_call.generating",
+		"event: response.image_generation_call.completed",
+	} {
+		if !strings.Contains(body, event) {
+			t.Errorf("missing %q", event)
+		}
+	}
+
+	if !strings.Contains(body, `"result":"cG5n"`) || !strings.Contains(body, `"output_format":"png"`) || !strings.Contains(body, `"revised_prompt":"a gopher"`) {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestHostedCodeInterpreterUnavailable(t *testing.T) {
+	h := newHostedHandler(t, toolCallingCompleter{})
+
+	rec := postResponses(t, h, `{
+		"model": "`+hostedTestModel+`",
+		"input": "hello",
+		"tools": [{"type": "code_interpreter", "container": {"type": "auto"}}]
+	}`)
+
+	if rec.Code != 400 || !strings.Contains(rec.Body.String(), `"param":"tools[0].type"`) {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHostedToolsCheckCapabilities(t *testing.T) {
+	h := newHostedHandler(t, toolCallingCompleter{})
+
+	h.DescribeModel(provider.Model{
+		ID: hostedTestModel,
+
+		Capabilities: provider.ModelCapabilities{
+			Tools: new(false),
+		},
+	})
+
+	rec := postResponses(t, h, `{
+		"model": "`+hostedTestModel+`",
+		"input": "draw a cat",
+		"tools": [{"type": "image_generation"}]
+	}`)
+
+	if rec.Code != 400 || !strings.Contains(rec.Body.String(), "does not support tools") {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHostedToolNameClash(t *testing.T) {
+	h := newHostedHandler(t, toolCallingCompleter{})
+
+	rec := postResponses(t, h, `{
+		"model": "`+hostedTestModel+`",
+		"input": "draw a cat",
+		"tools": [
+			{"type": "image_generation"},
+			{"type": "function", "name": "generate_image", "parameters": {"type": "object"}}
+		]
+	}`)
+
+	if rec.Code != 400 || !strings.Contains(rec.Body.String(), `"param":"tools[1].name"`) {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+}
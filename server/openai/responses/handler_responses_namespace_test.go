@@ -97,7 +97,7 @@ func TestNamespaceToolFlattensToFunctionTools(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -204,7 +204,7 @@ func TestNamespaceToolRoundTripsPriorCall(t *testing.T) {
 	req := httptest.NewRequest(http.MethodPost, "/responses", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
 
-	New(cfg).handleResponses(rec, req)
+	New(cfg, NewBackground()).handleResponses(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
@@ -10,8 +10,12 @@ import (
 	"testing"
 
 	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/auth"
 	"github.com/adrianliechti/wingman/pkg/policy/noop"
 	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/store/memory"
+
+	"github.com/go-chi/chi/v5"
 )
 
 const storeTestModel = "store-test-model"
@@ -34,7 +38,7 @@ func newStoreHandler(t *testing.T) *Handler {
 	t.Helper()
 	cfg := &config.Config{Policy: noop.New()}
 	cfg.RegisterCompleter(storeTestModel, echoCompleter{})
-	return New(cfg)
+	return New(cfg, NewBackground())
 }
 
 func postResponses(t *testing.T, h *Handler, body string) *httptest.ResponseRecorder {
@@ -45,9 +49,9 @@ func postResponses(t *testing.T, h *Handler, body string) *httptest.ResponseReco
 	return rec
 }
 
-// Store and previous_response_id are unknown to wingman — silently accepted
-// and dropped by json.Unmarshal. The response always carries store=false as
-// the "we don't persist" signal.
+// Without a configured store, store and previous_response_id are silently
+// accepted and ignored. The response carries store=false as the "we don't
+// persist" signal.
 
 func TestStoreTrueAcceptedAndResponseEchoesStoreFalse(t *testing.T) {
 	h := newStoreHandler(t)
@@ -111,3 +115,256 @@ func TestPreviousResponseIDAcceptedButIgnored(t *testing.T) {
 		t.Fatalf("expected response.previous_response_id=null, got %v", prev)
 	}
 }
+
+type recordingCompleter struct {
+	messages [][]provider.Message
+}
+
+func (c *recordingCompleter) Complete(_ context.Context, messages []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	c.messages = append(c.messages, messages)
+
+	return echoCompleter{}.Complete(context.Background(), messages, nil)
+}
+
+func newStoringHandler(t *testing.T) (*recordingCompleter, chi.Router) {
+	t.Helper()
+
+	completer := &recordingCompleter{}
+
+	cfg := &config.Config{Policy: noop.New(), Store: memory.New()}
+	cfg.RegisterCompleter(storeTestModel, completer)
+
+	h := New(cfg, NewBackground())
+
+	r := chi.NewRouter()
+	h.Attach(r)
+
+	return completer, r
+}
+
+func serveStore(t *testing.T, r chi.Router, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func serveStoreAs(t *testing.T, r chi.Router, user, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewReader([]byte(body)))
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserContextKey, user))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeStoreResponse(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return resp
+}
+
+func TestStoreDefaultsToTrueWhenConfigured(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","input":"hello"}`))
+
+	if resp["store"] != true {
+		t.Fatalf("expected store=true, got %v", resp["store"])
+	}
+
+	id, _ := resp["id"].(string)
+
+	stored := decodeStoreResponse(t, serveStore(t, r, http.MethodGet, "/responses/"+id, ""))
+
+	if stored["id"] != id {
+		t.Fatalf("expected stored response %s, got %v", id, stored["id"])
+	}
+}
+
+func TestStoreFalseIsNotPersisted(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","store":false,"input":"hello"}`))
+
+	if resp["store"] != false {
+		t.Fatalf("expected store=false, got %v", resp["store"])
+	}
+
+	if rec := serveStore(t, r, http.MethodGet, "/responses/"+resp["id"].(string), ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unstored response, got %d", rec.Code)
+	}
+}
+
+func TestPreviousResponseIDReconstructsConversation(t *testing.T) {
+	completer, r := newStoringHandler(t)
+
+	first := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","instructions":"be brief","input":"one"}`))
+	second := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","previous_response_id":"`+first["id"].(string)+`","input":"two"}`))
+
+	if second["previous_response_id"] != first["id"] {
+		t.Fatalf("expected previous_response_id echo, got %v", second["previous_response_id"])
+	}
+
+	decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","previous_response_id":"`+second["id"].(string)+`","input":"three"}`))
+
+	messages := completer.messages[2]
+
+	var got []string
+	for _, m := range messages {
+		got = append(got, string(m.Role)+":"+m.Text())
+	}
+
+	want := []string{"user:one", "assistant:hi", "user:two", "assistant:hi", "user:three"}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPreviousResponseIDNotFound(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	rec := serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","previous_response_id":"resp_missing","input":"hello"}`)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestStreamedResponseIsStored(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	rec := serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","stream":true,"input":"hello"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var id string
+
+	for _, line := range bytes.Split(rec.Body.Bytes(), []byte("\n")) {
+		data, ok := bytes.CutPrefix(line, []byte("data: "))
+		if !ok {
+			continue
+		}
+
+		var event struct {
+			Type     string `json:"type"`
+			Response struct {
+				ID string `json:"id"`
+			} `json:"response"`
+		}
+
+		if err := json.Unmarshal(data, &event); err == nil && event.Type == "response.completed" {
+			id = event.Response.ID
+		}
+	}
+
+	if id == "" {
+		t.Fatalf("no response.completed event in %s", rec.Body.String())
+	}
+
+	stored := decodeStoreResponse(t, serveStore(t, r, http.MethodGet, "/responses/"+id, ""))
+
+	if stored["status"] != "completed" {
+		t.Fatalf("expected completed stored response, got %v", stored["status"])
+	}
+}
+
+func TestDeleteStoredResponse(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","input":"hello"}`))
+	id := resp["id"].(string)
+
+	deleted := decodeStoreResponse(t, serveStore(t, r, http.MethodDelete, "/responses/"+id, ""))
+
+	if deleted["deleted"] != true || deleted["object"] != "response.deleted" {
+		t.Fatalf("unexpected delete result: %v", deleted)
+	}
+
+	if rec := serveStore(t, r, http.MethodGet, "/responses/"+id, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", rec.Code)
+	}
+}
+
+func TestListInputItems(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","input":[
+		{"role":"user","content":"first"},
+		{"type":"message","role":"user","content":[{"type":"input_text","text":"second"}]}
+	]}`))
+
+	list := decodeStoreResponse(t, serveStore(t, r, http.MethodGet, "/responses/"+resp["id"].(string)+"/input_items?order=asc&limit=1", ""))
+
+	data, _ := list["data"].([]any)
+
+	if len(data) != 1 || list["has_more"] != true {
+		t.Fatalf("expected one item with more available, got %v", list)
+	}
+
+	item := data[0].(map[string]any)
+
+	if item["type"] != "message" || item["content"] != "first" {
+		t.Fatalf("unexpected first item: %v", item)
+	}
+
+	next := decodeStoreResponse(t, serveStore(t, r, http.MethodGet, "/responses/"+resp["id"].(string)+"/input_items?order=asc&after="+item["id"].(string), ""))
+
+	if data, _ := next["data"].([]any); len(data) != 1 || next["has_more"] != false {
+		t.Fatalf("expected remaining item, got %v", next)
+	}
+}
+
+func TestListInputItemsStringInput(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	resp := decodeStoreResponse(t, serveStore(t, r, http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","input":"hello"}`))
+
+	list := decodeStoreResponse(t, serveStore(t, r, http.MethodGet, "/responses/"+resp["id"].(string)+"/input_items", ""))
+
+	data, _ := list["data"].([]any)
+
+	if len(data) != 1 {
+		t.Fatalf("expected one item, got %v", list)
+	}
+
+	if item := data[0].(map[string]any); item["type"] != "message" || item["role"] != "user" {
+		t.Fatalf("unexpected item: %v", item)
+	}
+}
+
+func TestStoredResponsesAreScopedToOwner(t *testing.T) {
+	_, r := newStoringHandler(t)
+
+	resp := decodeStoreResponse(t, serveStoreAs(t, r, "alice", http.MethodPost, "/responses", `{"model":"`+storeTestModel+`","input":"hello"}`))
+	id := resp["id"].(string)
+
+	for _, c := range []struct{ method, path, body string }{
+		{http.MethodGet, "/responses/" + id, ""},
+		{http.MethodGet, "/responses/" + id + "/input_items", ""},
+		{http.MethodDelete, "/responses/" + id, ""},
+		{http.MethodPost, "/responses", `{"model":"` + storeTestModel + `","previous_response_id":"` + id + `","input":"again"}`},
+	} {
+		if rec := serveStoreAs(t, r, "bob", c.method, c.path, c.body); rec.Code != http.StatusNotFound {
+			t.Fatalf("%s %s: expected 404 for another user, got %d: %s", c.method, c.path, rec.Code, rec.Body.String())
+		}
+	}
+
+	decodeStoreResponse(t, serveStoreAs(t, r, "alice", http.MethodGet, "/responses/"+id, ""))
+}
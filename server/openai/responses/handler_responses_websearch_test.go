@@ -0,0 +1,263 @@
+package responses
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/policy/noop"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/searcher"
+)
+
+const webSearchTestModel = "web-search-test-model"
+
+type fakeSearcher struct {
+	options *searcher.SearchOptions
+}
+
+func (s *fakeSearcher) Search(ctx context.Context, query string, options *searcher.SearchOptions) ([]searcher.Result, error) {
+	s.options = options
+
+	return []searcher.Result{
+		{Source: "https://go.dev", Title: "Go", Content: "The Go programming language."},
+		{Source: "https://go.dev/doc", Title: "Documentation", Content: "Go documentation."},
+	}, nil
+}
+
+func (s *fakeSearcher) Categories() []searcher.Category {
+	return nil
+}
+
+// searchingCompleter calls web_search until it sees the result, then answers
+// citing a source.
+type searchingCompleter struct{}
+
+func (searchingCompleter) Complete(_ context.Context, messages []provider.Message, _ *provider.CompleteOptions) iter.Seq2[*provider.Completion, error] {
+	return func(yield func(*provider.Completion, error) bool) {
+		for _, m := range messages {
+			for _, c := range m.Content {
+				if c.ToolResult != nil {
+					yield(&provider.Completion{
+						Status: provider.CompletionStatusCompleted,
+						Message: &provider.Message{
+							Role:    provider.MessageRoleAssistant,
+							Content: []provider.Content{provider.TextContent("See [the docs](https://go.dev/doc).")},
+						},
+					}, nil)
+
+					return
+				}
+			}
+		}
+
+		yield(&provider.Completion{
+			Status: provider.CompletionStatusCompleted,
+			Message: &provider.Message{
+				Role: provider.MessageRoleAssistant,
+				Content: []provider.Content{provider.ToolCallContent(provider.ToolCall{
+					ID:        "call_1",
+					Name:      "web_search",
+					Arguments: `{"query":"go docs"}`,
+				})},
+			},
+		}, nil)
+	}
+}
+
+func newWebSearchHandler(t *testing.T) (*Handler, *fakeSearcher) {
+	t.Helper()
+
+	s := &fakeSearcher{}
+
+	cfg := &config.Config{Policy: noop.New()}
+	cfg.RegisterCompleter(webSearchTestModel, searchingCompleter{})
+	cfg.RegisterHostedTools(webSearchTestModel, &config.HostedTools{
+		WebSearch: &config.HostedWebSearch{Searcher: s},
+	})
+
+	return New(cfg, NewBackground()), s
+}
+
+func TestHostedWebSearch(t *testing.T) {
+	h, s := newWebSearchHandler(t)
+
+	rec := postResponses(t, h, `{
+		"model": "`+webSearchTestModel+`",
+		"input": "where are the go docs?",
+		"tools": [{"type": "web_search", "filters": {"allowed_domains": ["go.dev"]}}]
+	}`)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Output []map[string]any `json:"output"`
+	}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Output) != 2 || resp.Output[0]["type"] != "web_search_call" || resp.Output[1]["type"] != "message" {
+		t.Fatalf("output = %v", resp.Output)
+	}
+
+	action := resp.Output[0]["action"].(map[string]any)
+
+	if resp.Output[0]["status"] != "completed" || action["query"] != "go docs" || len(action["sources"].([]any)) != 2 {
+		t.Errorf("web_search_call = %v", resp.Output[0])
+	}
+
+	if len(s.options.Include) != 1 || s.options.Include[0] != "go.dev" {
+		t.Errorf("include = %v", s.options.Include)
+	}
+
+	content := resp.Output[1]["content"].([]any)[0].(map[string]any)
+	annotations := content["annotations"].([]any)
+
+	if len(annotations) != 1 {
+		t.Fatalf("annotations = %v", annotations)
+	}
+
+	citation := annotations[0].(map[string]any)
+
+	if citation["type"] != "url_citation" || citation["url"] != "https://go.dev/doc" || citation["title"] != "Documentation" {
+		t.Errorf("citation = %v", citation)
+	}
+
+	text := content["text"].(string)
+	start, end := int(citation["start_index"].(float64)), int(citation["end_index"].(float64))
+
+	if text[start:end] != "https://go.dev/doc" {
+		t.Errorf("citation covers %q", text[start:end])
+	}
+}
+
+func TestHostedWebSearchStream(t *testing.T) {
+	h, _ := newWebSearchHandler(t)
+
+	rec := postResponses(t, h, `{
+		"model": "`+webSearchTestModel+`",
+		"input": "where are the go docs?",
+		"tools": [{"type": "web_search"}],
+		"stream": true
+	}`)
+
+	body := rec.Body.String()
+
+	var events []string
+
+	for line := range strings.Lines(body) {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "event: "); ok {
+			events = append(events, name)
+		}
+	}
+
+	want := []string{
+		"response.output_item.added",
+		"response.web_search_call.in_progress",
+		"response.web_search_call.searching",
+		"response.web_search_call.completed",
+		"response.output_item.done",
+	}
+
+	index := 0
+
+	for _, e := range events {
+		if index < len(want) && e == want[index] {
+			index++
+		}
+	}
+
+	if index != len(want) {
+		t.Fatalf("events = %v", events)
+	}
+
+	if !strings.Contains(body, `"type":"web_search_call"`) || !strings.Contains(body, `"type":"url_citation"`) {
+		t.Errorf("body = %s", body)
+	}
+}
+
+// toolReviewer records the tools it reviews and keeps only the listed ones.
+type toolReviewer struct {
+	policy.Provider
+
+	keep []string
+	seen []string
+}
+
+func (r *toolReviewer) Review(ctx context.Context, request policy.Request) (*policy.Decision, error) {
+	r.seen = request.Tools
+
+	return &policy.Decision{Tools: r.keep}, nil
+}
+
+func TestHostedWebSearchRemovedByReview(t *testing.T) {
+	var got provider.CompleteOptions
+
+	r := &toolReviewer{Provider: noop.New(), keep: []string{"lookup"}}
+
+	cfg := &config.Config{Policy: r}
+	cfg.RegisterCompleter(webSearchTestModel, &optionsCapturingCompleter{got: &got})
+	cfg.RegisterHostedTools(webSearchTestModel, &config.HostedTools{
+		WebSearch: &config.HostedWebSearch{Searcher: &fakeSearcher{}},
+	})
+
+	rec := postResponses(t, New(cfg, NewBackground()), `{
+		"model": "`+webSearchTestModel+`",
+		"input": "where are the go docs?",
+		"tools": [
+			{"type": "function", "name": "lookup", "parameters": {"type": "object"}},
+			{"type": "web_search"}
+		]
+	}`)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if strings.Join(r.seen, ",") != "lookup,web_search" {
+		t.Errorf("reviewed tools = %v", r.seen)
+	}
+
+	var names []string
+
+	for _, tool := range got.Tools {
+		names = append(names, tool.Name)
+	}
+
+	if strings.Join(names, ",") != "lookup" {
+		t.Errorf("tools = %v", names)
+	}
+
+	if strings.Contains(rec.Body.String(), `"type":"web_search_call"`) {
+		t.Errorf("unexpected hosted call: %s", rec.Body.String())
+	}
+}
+
+func TestWithoutHostedToolsWebSearchIsOmitted(t *testing.T) {
+	h := newStoreHandler(t)
+
+	rec := postResponses(t, h, `{
+		"model": "`+storeTestModel+`",
+		"input": [
+			{"type": "web_search_call", "id": "ws_1", "status": "completed", "action": {"type": "search", "query": "go"}},
+			{"role": "user", "content": "hello"}
+		],
+		"tools": [{"type": "web_search"}]
+	}`)
+
+	if rec.Code != 200 {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if strings.Contains(rec.Body.String(), `"type":"web_search_call"`) {
+		t.Errorf("unexpected hosted call: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,290 @@
+package responses
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/store"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// storeEnabled reports whether the turn is persisted. Like OpenAI, store
+// defaults to true — but only once a store is configured.
+func (h *Handler) storeEnabled(req ResponsesRequest) bool {
+	if h.Store == nil {
+		return false
+	}
+
+	return req.Store == nil || *req.Store
+}
+
+// historyItems rebuilds the conversation preceding a previous_response_id by
+// walking the chain back to its root and replaying each turn's input and
+// output items in order. Instructions are not carried over, and turns of
+// other users are treated as missing.
+func (h *Handler) historyItems(r *http.Request, id string) ([]InputItem, error) {
+	var chain []*store.Response
+
+	for id != "" {
+		response, err := h.Store.GetResponse(r.Context(), id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !store.IsOwner(r.Context(), response.Owner) {
+			return nil, store.ErrNotFound
+		}
+
+		chain = append(chain, response)
+		id = response.PreviousID
+	}
+
+	slices.Reverse(chain)
+
+	var items []InputItem
+
+	for _, response := range chain {
+		for _, data := range []json.RawMessage{response.Input, response.Output} {
+			if len(data) == 0 {
+				continue
+			}
+
+			var input ResponsesInput
+
+			if err := json.Unmarshal(data, &input); err != nil {
+				return nil, err
+			}
+
+			items = append(items, input.Items...)
+		}
+	}
+
+	return items, nil
+}
+
+// saveResponse persists a finished turn when the request asked for storage.
+func (h *Handler) saveResponse(r *http.Request, req ResponsesRequest, resp *Response) error {
+	if req.Store == nil || !*req.Store {
+		return nil
+	}
+
+	input, err := json.Marshal(req.Input.raw)
+
+	if err != nil {
+		return err
+	}
+
+	output, err := json.Marshal(resp.Output)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(resp)
+
+	if err != nil {
+		return err
+	}
+
+	return h.Store.SaveResponse(r.Context(), &store.Response{
+		ID:         resp.ID,
+		PreviousID: req.PreviousResponseID,
+
+		Owner: store.Owner(r.Context()),
+
+		Model: resp.Model,
+
+		Input:  input,
+		Output: output,
+		Data:   data,
+
+		CreatedAt: time.Unix(resp.CreatedAt, 0),
+	})
+}
+
+// loadResponse reads the stored response of the request path. Responses of
+// other users are reported as not found.
+func (h *Handler) loadResponse(w http.ResponseWriter, r *http.Request) (*store.Response, bool) {
+	id := chi.URLParam(r, "id")
+
+	if h.Store == nil {
+		writeError(w, http.StatusNotFound, errors.New("response not found: "+id))
+		return nil, false
+	}
+
+	response, err := h.Store.GetResponse(r.Context(), id)
+
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errors.New("response not found: "+id))
+			return nil, false
+		}
+
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	if !store.IsOwner(r.Context(), response.Owner) {
+		writeError(w, http.StatusNotFound, errors.New("response not found: "+id))
+		return nil, false
+	}
+
+	return response, true
+}
+
+func (h *Handler) handleResponseGet(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if r.URL.Query().Get("stream") == "true" {
+		h.handleResponseStream(w, r, id)
+		return
+	}
+
+	// background responses in flight are served from their job
+	if job, ok := h.background.job(r, id); ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(job.snapshot())
+		return
+	}
+
+	response, ok := h.loadResponse(w, r)
+
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(response.Data)
+}
+
+func (h *Handler) handleResponseDelete(w http.ResponseWriter, r *http.Request) {
+	response, ok := h.loadResponse(w, r)
+
+	if !ok {
+		return
+	}
+
+	if err := h.Store.DeleteResponse(r.Context(), response.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, ResponseDeleted{
+		ID:      response.ID,
+		Object:  "response.deleted",
+		Deleted: true,
+	})
+}
+
+func (h *Handler) handleResponseInputItems(w http.ResponseWriter, r *http.Request) {
+	response, ok := h.loadResponse(w, r)
+
+	if !ok {
+		return
+	}
+
+	raw, err := inputItems(response.Input)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(raw))
+
+	for i, item := range raw {
+		if _, ok := item["type"]; !ok {
+			item["type"] = string(InputItemTypeMessage)
+		}
+
+		// Input items are addressable by id; derive a stable one when the
+		// client sent none so pagination cursors survive repeated listings.
+		if id, _ := item["id"].(string); id == "" {
+			item["id"] = "item_" + uuid.NewSHA1(uuid.NameSpaceOID, []byte(response.ID+"/"+strconv.Itoa(i))).String()
+		}
+
+		items = append(items, item)
+	}
+
+	query := r.URL.Query()
+
+	// OpenAI lists input items newest first unless order=asc
+	if query.Get("order") != "asc" {
+		slices.Reverse(items)
+	}
+
+	if after := query.Get("after"); after != "" {
+		for i, item := range items {
+			if item["id"] == after {
+				items = items[i+1:]
+				break
+			}
+		}
+	}
+
+	limit := 20
+
+	if val, err := strconv.Atoi(query.Get("limit")); err == nil && val > 0 {
+		limit = min(val, 100)
+	}
+
+	hasMore := len(items) > limit
+
+	if hasMore {
+		items = items[:limit]
+	}
+
+	result := InputItemList{
+		Object: "list",
+		Data:   items,
+
+		HasMore: hasMore,
+	}
+
+	if len(items) > 0 {
+		result.FirstID, _ = items[0]["id"].(string)
+		result.LastID, _ = items[len(items)-1]["id"].(string)
+	}
+
+	writeJson(w, result)
+}
+
+// inputItems decodes the persisted input of a turn. A plain string input is
+// listed as the single user message it stands for.
+func inputItems(data json.RawMessage) ([]map[string]any, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var text string
+
+	if err := json.Unmarshal(data, &text); err == nil {
+		return []map[string]any{
+			{
+				"type": string(InputItemTypeMessage),
+				"role": string(MessageRoleUser),
+				"content": []map[string]any{
+					{
+						"type": string(InputContentText),
+						"text": text,
+					},
+				},
+			},
+		}, nil
+	}
+
+	var items []map[string]any
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -104,7 +104,7 @@ func TestInputTokensVsReference(t *testing.T) {
 	}
 
 	cfg := &config.Config{Policy: noop.New()}
-	h := New(cfg)
+	h := New(cfg, NewBackground())
 
 	for name, body := range scenarios {
 		payload, err := json.Marshal(body)
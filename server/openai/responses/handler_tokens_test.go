@@ -15,7 +15,7 @@ func postInputTokens(t *testing.T, body string) InputTokensResponse {
 	t.Helper()
 
 	cfg := &config.Config{Policy: noop.New()}
-	h := New(cfg)
+	h := New(cfg, NewBackground())
 
 	req := httptest.NewRequest(http.MethodPost, "/responses/input_tokens", bytes.NewBufferString(body))
 	rec := httptest.NewRecorder()
@@ -75,7 +75,7 @@ func TestInputTokensInstructionsAndTools(t *testing.T) {
 
 func TestInputTokensBadBody(t *testing.T) {
 	cfg := &config.Config{Policy: noop.New()}
-	h := New(cfg)
+	h := New(cfg, NewBackground())
 
 	req := httptest.NewRequest(http.MethodPost, "/responses/input_tokens", bytes.NewBufferString("{"))
 	rec := httptest.NewRecorder()
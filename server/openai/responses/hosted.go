@@ -0,0 +1,828 @@
+package responses
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/adrianliechti/wingman/pkg/agent/react"
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/otel"
+	"github.com/adrianliechti/wingman/pkg/policy"
+	"github.com/adrianliechti/wingman/pkg/provider"
+	"github.com/adrianliechti/wingman/pkg/searcher"
+	"github.com/adrianliechti/wingman/pkg/tool"
+	"github.com/adrianliechti/wingman/pkg/tool/interpreter"
+	"github.com/adrianliechti/wingman/pkg/tool/render"
+	"github.com/adrianliechti/wingman/pkg/tool/retrieve"
+	"github.com/adrianliechti/wingman/pkg/tool/scrape"
+	"github.com/adrianliechti/wingman/pkg/tool/search"
+	"github.com/adrianliechti/wingman/server/openai/shared"
+)
+
+// hostedTools runs the hosted tools of a request inside the gateway: the
+// completer is wrapped in a tool loop and every call is reported as its own
+// output item, whichever backend the model runs on.
+type hostedTools struct {
+	tools   []tool.Provider
+	include []string
+
+	// definitions are the tools the model is offered; offered holds them
+	// per entry of tools
+	definitions []provider.Tool
+	offered     [][]provider.Tool
+
+	searcher *recordingSearcher
+	index    *recordingIndex
+
+	mu sync.Mutex
+
+	calls   []*hostedCall
+	running map[string]*hostedCall
+
+	// started and done stream the calls as they happen, when set
+	started func(call *hostedCall)
+	done    func(call *hostedCall)
+}
+
+type hostedCall struct {
+	Item *ResponseOutput
+
+	// OutputIndex is the streamed position of the item, -1 if not streamed
+	OutputIndex int
+
+	Sources []searcher.Result
+	Files   []index.Result
+}
+
+// hostedTools returns the hosted tools the request asks for, or nil if there
+// are none. web_search is omitted when the model has no searcher bound; the
+// other hosted tools fail the request when they cannot be backed, as do
+// client tools named like one of them.
+func (h *Handler) hostedTools(ctx context.Context, req ResponsesRequest) (*hostedTools, error) {
+	cfg := h.HostedTools(req.Model)
+
+	result := &hostedTools{
+		include: req.Include,
+		running: make(map[string]*hostedCall),
+	}
+
+	unavailable := func(i int, t ToolType) error {
+		return &shared.Error{
+			Param:   fmt.Sprintf("tools[%d].type", i),
+			Message: fmt.Sprintf("Tool '%s' is not available for model '%s'.", t, req.Model),
+		}
+	}
+
+	for i, t := range req.Tools {
+		switch t.Type {
+		case ToolTypeWebSearch:
+			if cfg == nil || cfg.WebSearch == nil || result.searcher != nil {
+				continue
+			}
+
+			result.searcher = &recordingSearcher{
+				Provider: cfg.WebSearch.Searcher,
+			}
+
+			if t.Filters != nil {
+				result.searcher.domains = t.Filters.AllowedDomains
+			}
+
+			if t.UserLocation != nil {
+				result.searcher.location = strings.ToUpper(t.UserLocation.Country)
+			}
+
+			s, err := search.New(result.searcher)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result.tools = append(result.tools, otel.NewTool("search", s))
+
+			if cfg.WebSearch.Scraper != nil {
+				s, err := scrape.New(cfg.WebSearch.Scraper, scrape.WithAllowedDomains(result.searcher.domains...))
+
+				if err != nil {
+					return nil, err
+				}
+
+				result.tools = append(result.tools, otel.NewTool("scraper", s))
+			}
+
+		case ToolTypeFileSearch:
+			if result.index != nil {
+				continue
+			}
+
+			if len(t.VectorStoreIDs) == 0 {
+				return nil, &shared.Error{
+					Code:    "missing_required_parameter",
+					Param:   fmt.Sprintf("tools[%d].vector_store_ids", i),
+					Message: fmt.Sprintf("Missing required parameter: 'tools[%d].vector_store_ids'.", i),
+				}
+			}
+
+			result.index = &recordingIndex{}
+
+			for _, id := range t.VectorStoreIDs {
+				idx, err := h.vectorStore(ctx, id)
+
+				if err != nil {
+					return nil, &shared.Error{
+						Param:   fmt.Sprintf("tools[%d].vector_store_ids", i),
+						Message: fmt.Sprintf("Vector store with id '%s' not found.", id),
+					}
+				}
+
+				result.index.indexes = append(result.index.indexes, idx)
+			}
+
+			result.index.Provider = result.index.indexes[0]
+
+			r, err := retrieve.New(result.index, retrieve.WithLimit(t.MaxNumResults))
+
+			if err != nil {
+				return nil, err
+			}
+
+			result.tools = append(result.tools, otel.NewTool("retrieve", r))
+
+		case ToolTypeCodeInterpreter:
+			if cfg == nil || cfg.CodeInterpreter == nil {
+				return nil, unavailable(i, t.Type)
+			}
+
+			result.tools = append(result.tools, otel.NewTool("interpreter", cfg.CodeInterpreter))
+
+		case ToolTypeImageGeneration:
+			if cfg == nil || cfg.ImageGeneration == nil {
+				return nil, unavailable(i, t.Type)
+			}
+
+			r, err := render.New(cfg.ImageGeneration)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result.tools = append(result.tools, otel.NewTool("renderer", r))
+		}
+	}
+
+	if len(result.tools) == 0 {
+		return nil, nil
+	}
+
+	for _, t := range result.tools {
+		tools, err := t.Tools(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.definitions = append(result.definitions, tools...)
+		result.offered = append(result.offered, tools)
+	}
+
+	// calls of a client tool named like a hosted one would run the hosted
+	// tool instead of being returned to the client
+	for i, t := range req.Tools {
+		if t.Type != ToolTypeFunction && t.Type != ToolTypeCustom {
+			continue
+		}
+
+		if slices.ContainsFunc(result.definitions, func(d provider.Tool) bool { return d.Name == t.Name }) {
+			return nil, &shared.Error{
+				Param:   fmt.Sprintf("tools[%d].name", i),
+				Message: fmt.Sprintf("Tool name '%s' is reserved for a hosted tool.", t.Name),
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// vectorStore resolves a vector store the caller may access. Vector stores
+// are the configured indexes.
+func (h *Handler) vectorStore(ctx context.Context, id string) (index.Provider, error) {
+	if err := h.Policy.Verify(ctx, policy.ResourceIndex, id, policy.ActionAccess); err != nil {
+		return nil, err
+	}
+
+	return h.Index(id)
+}
+
+// keep drops the hosted tools a policy review removed: a tool stays only if
+// all of its definitions are still listed in tools. The hosted definitions
+// are then taken out of tools, as the tool loop offers them itself. It
+// reports whether any hosted tool is left.
+func (t *hostedTools) keep(tools []provider.Tool) ([]provider.Tool, bool) {
+	listed := func(d provider.Tool) bool {
+		return slices.ContainsFunc(tools, func(c provider.Tool) bool { return c.Name == d.Name })
+	}
+
+	hosted := t.definitions
+
+	var kept []tool.Provider
+	var offered [][]provider.Tool
+	var definitions []provider.Tool
+
+	for i, p := range t.tools {
+		if !slices.ContainsFunc(t.offered[i], func(d provider.Tool) bool { return !listed(d) }) {
+			kept = append(kept, p)
+			offered = append(offered, t.offered[i])
+			definitions = append(definitions, t.offered[i]...)
+		}
+	}
+
+	t.tools = kept
+	t.offered = offered
+	t.definitions = definitions
+
+	tools = slices.DeleteFunc(tools, func(c provider.Tool) bool {
+		return slices.ContainsFunc(hosted, func(d provider.Tool) bool { return d.Name == c.Name })
+	})
+
+	if len(tools) == 0 {
+		tools = nil
+	}
+
+	return tools, len(kept) > 0
+}
+
+// completer wraps the model's completer in a loop that runs the hosted tools.
+func (t *hostedTools) completer(model string, completer provider.Completer) (provider.Completer, error) {
+	return react.New(model,
+		react.WithCompleter(completer),
+		react.WithTools(t.tools...),
+		react.WithToolObserver(t.observe),
+	)
+}
+
+func (t *hostedTools) observe(ctx context.Context, event react.ToolEvent) {
+	t.mu.Lock()
+
+	switch event.Phase {
+	case react.ToolPhaseStart:
+		if t.searcher != nil {
+			t.searcher.reset()
+		}
+
+		if t.index != nil {
+			t.index.reset()
+		}
+
+		call := &hostedCall{
+			OutputIndex: -1,
+		}
+
+		call.Item = t.item(call, event, "in_progress")
+
+		t.calls = append(t.calls, call)
+		t.running[event.CallID] = call
+
+		t.mu.Unlock()
+
+		if t.started != nil {
+			t.started(call)
+		}
+
+		return
+
+	case react.ToolPhaseResult, react.ToolPhaseError:
+		call, ok := t.running[event.CallID]
+
+		if !ok {
+			break
+		}
+
+		delete(t.running, event.CallID)
+
+		status := "completed"
+
+		if event.Phase == react.ToolPhaseError {
+			status = "failed"
+		}
+
+		if event.Name == search.ToolName && t.searcher != nil {
+			call.Sources = t.searcher.results()
+		}
+
+		if event.Name == retrieve.ToolName && t.index != nil {
+			call.Files = t.index.results()
+		}
+
+		call.Item = t.item(call, event, status)
+
+		t.mu.Unlock()
+
+		if t.done != nil {
+			t.done(call)
+		}
+
+		return
+	}
+
+	t.mu.Unlock()
+}
+
+// item builds the output item of a hosted call from its tool event.
+func (t *hostedTools) item(call *hostedCall, event react.ToolEvent, status string) *ResponseOutput {
+	switch event.Name {
+	case retrieve.ToolName:
+		return t.fileSearchCall(call, event, status)
+
+	case interpreter.ToolName:
+		return t.codeInterpreterCall(event, status)
+
+	case render.ToolName:
+		return imageGenerationCall(event, status)
+	}
+
+	return webSearchCall(event, status, call.Sources)
+}
+
+func webSearchCall(event react.ToolEvent, status string, sources []searcher.Result) *ResponseOutput {
+	item := &WebSearchCallItem{
+		ID:     "ws_" + event.CallID,
+		Type:   "web_search_call",
+		Status: status,
+	}
+
+	switch event.Name {
+	case search.ToolName:
+		query, _ := event.Input["query"].(string)
+
+		item.Action = &WebSearchAction{
+			Type:  "search",
+			Query: query,
+		}
+
+		for _, s := range sources {
+			item.Action.Sources = append(item.Action.Sources, WebSearchSource{
+				Type: "url",
+				URL:  s.Source,
+			})
+		}
+
+	case scrape.ToolName:
+		url, _ := event.Input["url"].(string)
+
+		item.Action = &WebSearchAction{
+			Type: "open_page",
+			URL:  url,
+		}
+	}
+
+	return &ResponseOutput{
+		Type:              ResponseOutputTypeWebSearchCall,
+		WebSearchCallItem: item,
+	}
+}
+
+func (t *hostedTools) fileSearchCall(call *hostedCall, event react.ToolEvent, status string) *ResponseOutput {
+	item := &FileSearchCallItem{
+		ID:      "fs_" + event.CallID,
+		Type:    "file_search_call",
+		Status:  status,
+		Queries: []string{},
+	}
+
+	if query, _ := event.Input["query"].(string); query != "" {
+		item.Queries = append(item.Queries, query)
+	}
+
+	if slices.Contains(t.include, "file_search_call.results") && event.Phase != react.ToolPhaseStart {
+		item.Results = []FileSearchResult{}
+
+		for _, f := range call.Files {
+			item.Results = append(item.Results, FileSearchResult{
+				FileID:   f.ID,
+				Filename: cmp.Or(f.Title, f.Source),
+
+				Score: f.Score,
+				Text:  f.Content,
+
+				Attributes: f.Metadata,
+			})
+		}
+	}
+
+	return &ResponseOutput{
+		Type:               ResponseOutputTypeFileSearchCall,
+		FileSearchCallItem: item,
+	}
+}
+
+func (t *hostedTools) codeInterpreterCall(event react.ToolEvent, status string) *ResponseOutput {
+	item := &CodeInterpreterCallItem{
+		ID:     "ci_" + event.CallID,
+		Type:   "code_interpreter_call",
+		Status: status,
+	}
+
+	item.Code, _ = event.Input["code"].(string)
+
+	if slices.Contains(t.include, "code_interpreter_call.outputs") && event.Result != nil {
+		item.Outputs = []CodeInterpreterOutput{}
+
+		for _, p := range event.Result.Parts {
+			if p.Text != "" {
+				item.Outputs = append(item.Outputs, CodeInterpreterOutput{
+					Type: "logs",
+					Logs: p.Text,
+				})
+			}
+
+			if p.File != nil && strings.HasPrefix(p.File.ContentType, "image/") {
+				item.Outputs = append(item.Outputs, CodeInterpreterOutput{
+					Type: "image",
+					URL:  "data:" + p.File.ContentType + ";base64," + base64.StdEncoding.EncodeToString(p.File.Content),
+				})
+			}
+		}
+	}
+
+	return &ResponseOutput{
+		Type:                    ResponseOutputTypeCodeInterpreterCall,
+		CodeInterpreterCallItem: item,
+	}
+}
+
+func imageGenerationCall(event react.ToolEvent, status string) *ResponseOutput {
+	item := &ImageGenerationCallItem{
+		ID:     "ig_" + event.CallID,
+		Type:   "image_generation_call",
+		Status: status,
+	}
+
+	item.RevisedPrompt, _ = event.Input["prompt"].(string)
+
+	if event.Result != nil {
+		for _, p := range event.Result.Parts {
+			if p.File == nil {
+				continue
+			}
+
+			item.Result = base64.StdEncoding.EncodeToString(p.File.Content)
+			item.OutputFormat = strings.TrimPrefix(p.File.ContentType, "image/")
+
+			break
+		}
+	}
+
+	return &ResponseOutput{
+		Type:                    ResponseOutputTypeImageGenerationCall,
+		ImageGenerationCallItem: item,
+	}
+}
+
+// hostedPhases returns the progress phases of a hosted item when it starts
+// and when it finishes. Failed calls have no finishing phase.
+func hostedPhases(item *ResponseOutput) ([]string, string) {
+	var phase, status string
+
+	switch item.Type {
+	case ResponseOutputTypeWebSearchCall:
+		phase, status = "searching", item.WebSearchCallItem.Status
+
+	case ResponseOutputTypeFileSearchCall:
+		phase, status = "searching", item.FileSearchCallItem.Status
+
+	case ResponseOutputTypeCodeInterpreterCall:
+		phase, status = "interpreting", item.CodeInterpreterCallItem.Status
+
+	case ResponseOutputTypeImageGenerationCall:
+		phase, status = "generating", item.ImageGenerationCallItem.Status
+
+	default:
+		return nil, ""
+	}
+
+	if status == "failed" {
+		return []string{"in_progress", phase}, ""
+	}
+
+	return []string{"in_progress", phase}, "completed"
+}
+
+func hostedItemID(item *ResponseOutput) string {
+	switch item.Type {
+	case ResponseOutputTypeWebSearchCall:
+		return item.WebSearchCallItem.ID
+
+	case ResponseOutputTypeFileSearchCall:
+		return item.FileSearchCallItem.ID
+
+	case ResponseOutputTypeCodeInterpreterCall:
+		return item.CodeInterpreterCallItem.ID
+
+	case ResponseOutputTypeImageGenerationCall:
+		return item.ImageGenerationCallItem.ID
+	}
+
+	return ""
+}
+
+// outputs adds the hosted calls and their citations to the output items of
+// a response. Streamed calls keep their output index; the others precede the
+// message they informed.
+func (t *hostedTools) outputs(output []ResponseOutput) []ResponseOutput {
+	if t == nil {
+		return output
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := range output {
+		if output[i].OutputMessage == nil {
+			continue
+		}
+
+		for j, content := range output[i].OutputMessage.Contents {
+			if content.Type == "output_text" {
+				output[i].OutputMessage.Contents[j].Annotations = t.annotations(content.Text)
+			}
+		}
+	}
+
+	for _, call := range t.calls {
+		index := call.OutputIndex
+
+		if index < 0 {
+			index = slices.IndexFunc(output, func(o ResponseOutput) bool {
+				return o.Type == ResponseOutputTypeMessage
+			})
+		}
+
+		if index < 0 || index > len(output) {
+			index = len(output)
+		}
+
+		output = slices.Insert(output, index, *call.Item)
+	}
+
+	return output
+}
+
+// citations returns the url_citation and file_citation annotations of a text.
+func (t *hostedTools) citations(text string) []any {
+	if t == nil {
+		return []any{}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.annotations(text)
+}
+
+type citation struct {
+	position int
+	value    any
+}
+
+// annotations cites the sources and files found by the hosted calls. Must be
+// called with mu held.
+func (t *hostedTools) annotations(text string) []any {
+	citations := append(t.urlCitations(text), t.fileCitations(text)...)
+
+	slices.SortStableFunc(citations, func(a, b citation) int {
+		return a.position - b.position
+	})
+
+	result := []any{}
+
+	for _, c := range citations {
+		result = append(result, c.value)
+	}
+
+	return result
+}
+
+// urlCitations cites every source whose URL appears in the text. Longer URLs
+// win over URLs that are a prefix of them.
+func (t *hostedTools) urlCitations(text string) []citation {
+	sources := map[string]string{}
+
+	for _, call := range t.calls {
+		for _, s := range call.Sources {
+			if sources[s.Source] == "" {
+				sources[s.Source] = cmp.Or(s.Title, s.Source)
+			}
+		}
+
+		if a := call.Item.WebSearchCallItem; a != nil && a.Action != nil && a.Action.URL != "" {
+			if _, ok := sources[a.Action.URL]; !ok {
+				sources[a.Action.URL] = a.Action.URL
+			}
+		}
+	}
+
+	urls := make([]string, 0, len(sources))
+
+	for url := range sources {
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	slices.SortFunc(urls, func(a, b string) int {
+		return cmp.Or(len(b)-len(a), strings.Compare(a, b))
+	})
+
+	type span struct{ start, end int }
+
+	var taken []span
+	var result []citation
+
+	for _, url := range urls {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], url)
+
+			if i < 0 {
+				break
+			}
+
+			start := offset + i
+			end := start + len(url)
+
+			offset = end
+
+			if slices.ContainsFunc(taken, func(s span) bool { return start < s.end && s.start < end }) {
+				continue
+			}
+
+			taken = append(taken, span{start, end})
+
+			c := URLCitation{
+				Type: "url_citation",
+
+				StartIndex: utf8.RuneCountInString(text[:start]),
+				EndIndex:   utf8.RuneCountInString(text[:end]),
+
+				URL:   url,
+				Title: sources[url],
+			}
+
+			result = append(result, citation{c.StartIndex, c})
+		}
+	}
+
+	return result
+}
+
+var fileMarker = regexp.MustCompile(`\[(\d+)\]`)
+
+// fileCitations cites the files behind the [n] markers the retrieve tool asks
+// the model to use. A marker refers to the latest file_search call that
+// returned that many results.
+func (t *hostedTools) fileCitations(text string) []citation {
+	var result []citation
+
+	for _, m := range fileMarker.FindAllStringSubmatchIndex(text, -1) {
+		n, err := strconv.Atoi(text[m[2]:m[3]])
+
+		if err != nil || n < 1 {
+			continue
+		}
+
+		for _, call := range slices.Backward(t.calls) {
+			if call.Item.FileSearchCallItem == nil || n > len(call.Files) {
+				continue
+			}
+
+			f := call.Files[n-1]
+
+			c := FileCitation{
+				Type: "file_citation",
+
+				Index: utf8.RuneCountInString(text[:m[0]]),
+
+				FileID:   f.ID,
+				Filename: cmp.Or(f.Title, f.Source),
+			}
+
+			result = append(result, citation{c.Index, c})
+
+			break
+		}
+	}
+
+	return result
+}
+
+// recordingSearcher keeps the results of the last search so its call can
+// list the sources, and applies the filters of the request's web_search tool.
+type recordingSearcher struct {
+	searcher.Provider
+
+	domains  []string
+	location string
+
+	mu   sync.Mutex
+	last []searcher.Result
+}
+
+func (s *recordingSearcher) Search(ctx context.Context, query string, options *searcher.SearchOptions) ([]searcher.Result, error) {
+	if options == nil {
+		options = &searcher.SearchOptions{}
+	}
+
+	if len(s.domains) > 0 {
+		options.Include = s.domains
+	}
+
+	if options.Location == "" {
+		options.Location = s.location
+	}
+
+	results, err := s.Provider.Search(ctx, query, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.last = append(s.last, results...)
+	s.mu.Unlock()
+
+	return results, nil
+}
+
+func (s *recordingSearcher) reset() {
+	s.mu.Lock()
+	s.last = nil
+	s.mu.Unlock()
+}
+
+func (s *recordingSearcher) results() []searcher.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.last)
+}
+
+// recordingIndex queries all vector stores of the request's file_search tool
+// as one and keeps the results of the last query so its call can list them.
+type recordingIndex struct {
+	index.Provider
+
+	indexes []index.Provider
+
+	mu   sync.Mutex
+	last []index.Result
+}
+
+func (i *recordingIndex) Query(ctx context.Context, query string, options *index.QueryOptions) ([]index.Result, error) {
+	var results []index.Result
+
+	for _, idx := range i.indexes {
+		r, err := idx.Query(ctx, query, options)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, r...)
+	}
+
+	if len(i.indexes) > 1 {
+		slices.SortStableFunc(results, func(a, b index.Result) int {
+			return cmp.Compare(b.Score, a.Score)
+		})
+	}
+
+	if options != nil && options.Limit != nil && len(results) > *options.Limit {
+		results = results[:*options.Limit]
+	}
+
+	i.mu.Lock()
+	i.last = slices.Clone(results)
+	i.mu.Unlock()
+
+	return results, nil
+}
+
+func (i *recordingIndex) reset() {
+	i.mu.Lock()
+	i.last = nil
+	i.mu.Unlock()
+}
+
+func (i *recordingIndex) results() []index.Result {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return slices.Clone(i.last)
+}
@@ -12,6 +12,9 @@ type ResponsesRequest struct {
 
 	Stream bool `json:"stream,omitempty"`
 
+	// Background runs the turn detached from the request; see handler_background.go
+	Background bool `json:"background,omitempty"`
+
 	Instructions string `json:"instructions,omitempty"`
 
 	Input ResponsesInput `json:"input"`
@@ -24,6 +27,7 @@ type ResponsesRequest struct {
 
 	MaxOutputTokens *int     `json:"max_output_tokens,omitempty"`
 	Temperature     *float32 `json:"temperature,omitempty"`
+	TopP            *float32 `json:"top_p,omitempty"`
 
 	Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
 
@@ -33,6 +37,9 @@ type ResponsesRequest struct {
 	ParallelToolCalls *bool       `json:"parallel_tool_calls,omitempty"`
 
 	Truncation string `json:"truncation,omitempty"`
+
+	Store              *bool  `json:"store,omitempty"`
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
 }
 
 // ContextManagementConfig represents a context management entry
@@ -99,6 +106,10 @@ const (
 	ToolTypeNamespace  ToolType = "namespace"
 	ToolTypeToolSearch ToolType = "tool_search"
 	ToolTypeWebSearch  ToolType = "web_search"
+
+	ToolTypeFileSearch      ToolType = "file_search"
+	ToolTypeCodeInterpreter ToolType = "code_interpreter"
+	ToolTypeImageGeneration ToolType = "image_generation"
 )
 
 // Tool represents a tool in the request
@@ -129,6 +140,32 @@ type Tool struct {
 	DisplayWidth  int    `json:"display_width,omitempty"`
 	DisplayHeight int    `json:"display_height,omitempty"`
 	Environment   string `json:"environment,omitempty"` // "browser", "ubuntu", "windows", "mac"
+
+	// For web_search tool
+	Filters      *WebSearchFilters  `json:"filters,omitempty"`
+	UserLocation *WebSearchLocation `json:"user_location,omitempty"`
+
+	// For file_search tool
+	VectorStoreIDs []string `json:"vector_store_ids,omitempty"`
+	MaxNumResults  int      `json:"max_num_results,omitempty"`
+
+	// For code_interpreter tool
+	Container any `json:"container,omitempty"`
+}
+
+// WebSearchFilters restricts a web_search tool to a list of domains.
+type WebSearchFilters struct {
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+}
+
+// WebSearchLocation is the approximate user location for a web_search tool.
+type WebSearchLocation struct {
+	Type    string `json:"type,omitempty"` // approximate
+	Country string `json:"country,omitempty"`
+	Region  string `json:"region,omitempty"`
+	City    string `json:"city,omitempty"`
+
+	Timezone string `json:"timezone,omitempty"`
 }
 
 type ToolChoice struct {
@@ -299,10 +336,17 @@ const (
 	InputItemTypeToolSearchCall       InputItemType = "tool_search_call"
 	InputItemTypeToolSearchOutput     InputItemType = "tool_search_output"
 	InputItemTypeCompactionTrigger    InputItemType = "compaction_trigger"
+	InputItemTypeWebSearchCall        InputItemType = "web_search_call"
+	InputItemTypeFileSearchCall       InputItemType = "file_search_call"
+	InputItemTypeCodeInterpreterCall  InputItemType = "code_interpreter_call"
+	InputItemTypeImageGenerationCall  InputItemType = "image_generation_call"
 )
 
 type ResponsesInput struct {
 	Items []InputItem `json:"-"`
+
+	// raw keeps the items as sent, for persisting them with the response
+	raw []json.RawMessage
 }
 
 // InputItem represents a single item in the input array
@@ -598,6 +642,18 @@ func (ri *ResponsesInput) UnmarshalJSON(data []byte) error {
 				},
 			},
 		}
+
+		raw, err := json.Marshal(map[string]any{
+			"type":    InputItemTypeMessage,
+			"role":    MessageRoleUser,
+			"content": ri.Items[0].InputMessage.Content,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		ri.raw = []json.RawMessage{raw}
 		return nil
 	}
 
@@ -607,6 +663,8 @@ func (ri *ResponsesInput) UnmarshalJSON(data []byte) error {
 		return errors.New("failed to unmarshal ResponsesInput")
 	}
 
+	ri.raw = rawItems
+
 	ri.Items = make([]InputItem, 0, len(rawItems))
 
 	for _, raw := range rawItems {
@@ -738,6 +796,10 @@ func (ri *ResponsesInput) UnmarshalJSON(data []byte) error {
 		case InputItemTypeCompactionTrigger:
 			// bare marker item (Codex remote compaction); carries no payload
 
+		case InputItemTypeWebSearchCall, InputItemTypeFileSearchCall, InputItemTypeCodeInterpreterCall, InputItemTypeImageGenerationCall:
+			// hosted call replayed from an earlier turn; its results are
+			// already reflected in the assistant message that follows
+
 		default:
 			return fmt.Errorf("unknown input item type: %s", typeWrapper.Type)
 		}
@@ -869,7 +931,7 @@ type Response struct {
 	CompletedAt *int64 `json:"completed_at"`
 
 	Model  string `json:"model"`
-	Status string `json:"status"` // completed, failed, in_progress, incomplete
+	Status string `json:"status"` // completed, failed, in_progress, incomplete, queued, cancelled
 
 	Background bool `json:"background"`
 
@@ -961,6 +1023,10 @@ type ResponseOutput struct {
 	*ComputerCallItem
 	*ShellCallItem
 	*ToolSearchCallItem
+	*WebSearchCallItem
+	*FileSearchCallItem
+	*CodeInterpreterCallItem
+	*ImageGenerationCallItem
 	*ReasoningOutputItem
 	*CompactionOutputItem
 }
@@ -1129,6 +1195,22 @@ func (r ResponseOutput) MarshalJSON() ([]byte, error) {
 				EncryptedContent: r.ReasoningOutputItem.EncryptedContent,
 			})
 		}
+	case ResponseOutputTypeWebSearchCall:
+		if r.WebSearchCallItem != nil {
+			return json.Marshal(r.WebSearchCallItem)
+		}
+	case ResponseOutputTypeFileSearchCall:
+		if r.FileSearchCallItem != nil {
+			return json.Marshal(r.FileSearchCallItem)
+		}
+	case ResponseOutputTypeCodeInterpreterCall:
+		if r.CodeInterpreterCallItem != nil {
+			return json.Marshal(r.CodeInterpreterCallItem)
+		}
+	case ResponseOutputTypeImageGenerationCall:
+		if r.ImageGenerationCallItem != nil {
+			return json.Marshal(r.ImageGenerationCallItem)
+		}
 	case ResponseOutputTypeCompaction:
 		if r.CompactionOutputItem != nil {
 			return json.Marshal(struct {
@@ -1161,10 +1243,110 @@ var (
 	ResponseOutputTypeShellCall      ResponseOutputType = "shell_call"
 	ResponseOutputTypeLocalShellCall ResponseOutputType = "local_shell_call"
 	ResponseOutputTypeToolSearchCall ResponseOutputType = "tool_search_call"
+	ResponseOutputTypeWebSearchCall  ResponseOutputType = "web_search_call"
 	ResponseOutputTypeReasoning      ResponseOutputType = "reasoning"
 	ResponseOutputTypeCompaction     ResponseOutputType = "compaction"
+
+	ResponseOutputTypeFileSearchCall      ResponseOutputType = "file_search_call"
+	ResponseOutputTypeCodeInterpreterCall ResponseOutputType = "code_interpreter_call"
+	ResponseOutputTypeImageGenerationCall ResponseOutputType = "image_generation_call"
 )
 
+// WebSearchCallItem represents a web_search call run by the gateway.
+type WebSearchCallItem struct {
+	ID     string           `json:"id"`
+	Type   string           `json:"type"` // web_search_call
+	Status string           `json:"status"`
+	Action *WebSearchAction `json:"action,omitempty"`
+}
+
+// WebSearchAction is what a web_search call did: a search or opening a page.
+type WebSearchAction struct {
+	Type string `json:"type"` // search, open_page
+
+	Query   string            `json:"query,omitempty"`
+	Sources []WebSearchSource `json:"sources,omitempty"`
+
+	URL string `json:"url,omitempty"`
+}
+
+type WebSearchSource struct {
+	Type string `json:"type"` // url
+	URL  string `json:"url"`
+}
+
+// FileSearchCallItem represents a file_search call run by the gateway.
+// Results are only set when the request includes file_search_call.results.
+type FileSearchCallItem struct {
+	ID      string             `json:"id"`
+	Type    string             `json:"type"` // file_search_call
+	Status  string             `json:"status"`
+	Queries []string           `json:"queries"`
+	Results []FileSearchResult `json:"results"`
+}
+
+type FileSearchResult struct {
+	FileID   string `json:"file_id"`
+	Filename string `json:"filename"`
+
+	Score float32 `json:"score"`
+	Text  string  `json:"text"`
+
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// CodeInterpreterCallItem represents a code_interpreter call run by the
+// gateway. Outputs are only set when the request includes
+// code_interpreter_call.outputs.
+type CodeInterpreterCallItem struct {
+	ID          string                  `json:"id"`
+	Type        string                  `json:"type"` // code_interpreter_call
+	Status      string                  `json:"status"`
+	ContainerID string                  `json:"container_id,omitempty"`
+	Code        string                  `json:"code"`
+	Outputs     []CodeInterpreterOutput `json:"outputs"`
+}
+
+type CodeInterpreterOutput struct {
+	Type string `json:"type"` // logs, image
+
+	Logs string `json:"logs,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ImageGenerationCallItem represents an image_generation call run by the
+// gateway. Result is the base64 encoded image.
+type ImageGenerationCallItem struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"` // image_generation_call
+	Status string `json:"status"`
+	Result string `json:"result,omitempty"`
+
+	OutputFormat  string `json:"output_format,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// FileCitation annotates the position of an output text that cites a file.
+type FileCitation struct {
+	Type string `json:"type"` // file_citation
+
+	Index int `json:"index"`
+
+	FileID   string `json:"file_id"`
+	Filename string `json:"filename"`
+}
+
+// URLCitation annotates the range of an output text that cites a web source.
+type URLCitation struct {
+	Type string `json:"type"` // url_citation
+
+	StartIndex int `json:"start_index"`
+	EndIndex   int `json:"end_index"`
+
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
 // ToolSearchCallItem represents a tool_search call in the output.
 type ToolSearchCallItem struct {
 	ID        string          `json:"id"`
@@ -1701,3 +1883,40 @@ type ToolSearchCallOutputItemDoneEvent struct {
 	OutputIndex    int                 `json:"output_index"`
 	Item           *ToolSearchCallItem `json:"item"`
 }
+
+// HostedCallOutputItemEvent wraps a gateway-hosted call in output_item.added
+// and output_item.done
+type HostedCallOutputItemEvent struct {
+	Type           string          `json:"type"` // response.output_item.added, response.output_item.done
+	SequenceNumber int             `json:"sequence_number"`
+	OutputIndex    int             `json:"output_index"`
+	Item           *ResponseOutput `json:"item"`
+}
+
+// HostedCallProgressEvent reports the progress of a gateway-hosted call,
+// e.g. response.web_search_call.searching
+type HostedCallProgressEvent struct {
+	Type           string `json:"type"`
+	SequenceNumber int    `json:"sequence_number"`
+	OutputIndex    int    `json:"output_index"`
+	ItemID         string `json:"item_id"`
+}
+
+// https://platform.openai.com/docs/api-reference/responses/delete
+type ResponseDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"` // response.deleted
+	Deleted bool   `json:"deleted"`
+}
+
+// https://platform.openai.com/docs/api-reference/responses/input-items
+type InputItemList struct {
+	Object string `json:"object"` // list
+
+	Data []map[string]any `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+
+	HasMore bool `json:"has_more"`
+}
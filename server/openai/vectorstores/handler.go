@@ -0,0 +1,41 @@
+package vectorstores
+
+import (
+	"net/http"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/server/openai/shared"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	*config.Config
+}
+
+func New(cfg *config.Config) *Handler {
+	h := &Handler{
+		Config: cfg,
+	}
+
+	return h
+}
+
+func (h *Handler) Attach(r chi.Router) {
+	r.Get("/vector_stores", h.handleVectorStores)
+	r.Get("/vector_stores/{id}", h.handleVectorStore)
+
+	r.Post("/vector_stores/{id}/search", h.handleSearch)
+
+	r.Get("/vector_stores/{id}/documents", h.handleDocuments)
+	r.Post("/vector_stores/{id}/documents", h.handleDocumentsUpsert)
+	r.Delete("/vector_stores/{id}/documents/{document}", h.handleDocumentDelete)
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	shared.WriteJson(w, v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	shared.WriteError(w, code, err)
+}
@@ -0,0 +1,133 @@
+package vectorstores
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/policy"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) handleDocuments(w http.ResponseWriter, r *http.Request) {
+	_, i, ok := h.vectorStoreIndex(w, r, policy.ActionAccess)
+
+	if !ok {
+		return
+	}
+
+	documents, err := i.List(r.Context(), nil)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := &DocumentList{
+		Object: "list",
+
+		Data: []Document{},
+	}
+
+	for _, d := range documents {
+		result.Data = append(result.Data, toDocument(d))
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleDocumentsUpsert(w http.ResponseWriter, r *http.Request) {
+	_, i, ok := h.vectorStoreIndex(w, r, policy.ActionWrite)
+
+	if !ok {
+		return
+	}
+
+	var req DocumentsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.Documents) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no documents provided"))
+		return
+	}
+
+	documents := make([]index.Document, 0, len(req.Documents))
+
+	for _, d := range req.Documents {
+		if d.Content == "" {
+			writeError(w, http.StatusBadRequest, errors.New("document content is required"))
+			return
+		}
+
+		documents = append(documents, index.Document{
+			ID: d.ID,
+
+			Title:   d.Title,
+			Source:  d.Source,
+			Content: d.Content,
+
+			Metadata: d.Metadata,
+		})
+	}
+
+	ids, err := i.Index(r.Context(), documents...)
+
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	result := &DocumentList{
+		Object: "list",
+
+		Data: []Document{},
+	}
+
+	for n, d := range documents {
+		d.ID = ids[n]
+		result.Data = append(result.Data, toDocument(d))
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleDocumentDelete(w http.ResponseWriter, r *http.Request) {
+	_, i, ok := h.vectorStoreIndex(w, r, policy.ActionWrite)
+
+	if !ok {
+		return
+	}
+
+	id := chi.URLParam(r, "document")
+
+	if err := i.Delete(r.Context(), id); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, &DocumentDeleted{
+		ID:      id,
+		Object:  "vector_store.document.deleted",
+		Deleted: true,
+	})
+}
+
+func toDocument(d index.Document) Document {
+	return Document{
+		Object: "vector_store.document",
+
+		ID: d.ID,
+
+		Title:   d.Title,
+		Source:  d.Source,
+		Content: d.Content,
+
+		Metadata: d.Metadata,
+	}
+}
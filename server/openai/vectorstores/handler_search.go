@@ -0,0 +1,125 @@
+package vectorstores
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/policy"
+)
+
+func (h *Handler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	_, i, ok := h.vectorStoreIndex(w, r, policy.ActionAccess)
+
+	if !ok {
+		return
+	}
+
+	var req SearchRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.Query) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("query is required"))
+		return
+	}
+
+	filters, err := req.Filters.Equalities()
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limit := 10
+
+	if req.MaxNumResults != nil {
+		if *req.MaxNumResults < 1 || *req.MaxNumResults > 50 {
+			writeError(w, http.StatusBadRequest, errors.New("max_num_results must be between 1 and 50"))
+			return
+		}
+
+		limit = *req.MaxNumResults
+	}
+
+	// multiple queries are answered independently and merged, keeping the
+	// best score per document
+	best := map[string]index.Result{}
+
+	for _, query := range req.Query {
+		results, err := i.Query(r.Context(), query, &index.QueryOptions{
+			Limit:   &limit,
+			Filters: filters,
+		})
+
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+
+		for _, result := range results {
+			if existing, ok := best[result.ID]; !ok || result.Score > existing.Score {
+				best[result.ID] = result
+			}
+		}
+	}
+
+	results := make([]index.Result, 0, len(best))
+
+	for _, result := range best {
+		results = append(results, result)
+	}
+
+	slices.SortFunc(results, func(a, b index.Result) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	page := &SearchResultPage{
+		Object: "vector_store.search_results.page",
+
+		SearchQuery: req.Query,
+		Data:        []SearchResult{},
+	}
+
+	for _, result := range results {
+		filename := result.Title
+
+		if filename == "" {
+			filename = result.Source
+		}
+
+		attributes := result.Metadata
+
+		if attributes == nil {
+			attributes = map[string]string{}
+		}
+
+		page.Data = append(page.Data, SearchResult{
+			FileID:   result.ID,
+			Filename: filename,
+
+			Score: result.Score,
+
+			Attributes: attributes,
+
+			Content: []SearchContent{
+				{
+					Type: "text",
+					Text: result.Content,
+				},
+			},
+		})
+	}
+
+	writeJson(w, page)
+}
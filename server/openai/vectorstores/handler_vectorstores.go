@@ -0,0 +1,135 @@
+package vectorstores
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/adrianliechti/wingman/pkg/index"
+	"github.com/adrianliechti/wingman/pkg/policy"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) handleVectorStores(w http.ResponseWriter, r *http.Request) {
+	result := &VectorStoreList{
+		Object: "list",
+
+		Data: []VectorStore{},
+	}
+
+	for _, id := range h.Indexes() {
+		if h.Policy.Verify(r.Context(), policy.ResourceIndex, id, policy.ActionAccess) != nil {
+			continue
+		}
+
+		i, err := h.Index(id)
+
+		if err != nil {
+			continue
+		}
+
+		store, err := vectorStore(r, id, i)
+
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		result.Data = append(result.Data, *store)
+	}
+
+	if len(result.Data) > 0 {
+		result.FirstID = result.Data[0].ID
+		result.LastID = result.Data[len(result.Data)-1].ID
+	}
+
+	writeJson(w, result)
+}
+
+func (h *Handler) handleVectorStore(w http.ResponseWriter, r *http.Request) {
+	id, i, ok := h.vectorStoreIndex(w, r, policy.ActionAccess)
+
+	if !ok {
+		return
+	}
+
+	store, err := vectorStore(r, id, i)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJson(w, store)
+}
+
+// vectorStoreIndex resolves the index behind the {id} route parameter and
+// verifies the action on it, writing the error response itself on failure.
+// Callers allowed to read but not to write get a 403.
+func (h *Handler) vectorStoreIndex(w http.ResponseWriter, r *http.Request, action policy.Action) (string, index.Provider, bool) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.Policy.Verify(r.Context(), policy.ResourceIndex, id, policy.ActionAccess); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return "", nil, false
+	}
+
+	if action != policy.ActionAccess {
+		if err := h.Policy.Verify(r.Context(), policy.ResourceIndex, id, action); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return "", nil, false
+		}
+	}
+
+	i, err := h.Index(id)
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return "", nil, false
+	}
+
+	return id, i, true
+}
+
+func vectorStore(r *http.Request, id string, i index.Provider) (*VectorStore, error) {
+	documents, err := i.List(r.Context(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var size int64
+
+	sources := map[string]bool{}
+
+	for _, d := range documents {
+		size += int64(len(d.Content))
+
+		// chunks of the same source count as one file
+		source := d.Source
+
+		if source == "" {
+			source = d.ID
+		}
+
+		sources[source] = true
+	}
+
+	return &VectorStore{
+		Object: "vector_store",
+
+		ID:        id,
+		Name:      id,
+		CreatedAt: time.Now().Unix(),
+
+		Status:     "completed",
+		UsageBytes: size,
+
+		FileCounts: FileCounts{
+			Completed: len(sources),
+			Total:     len(sources),
+		},
+
+		Metadata: map[string]string{},
+	}, nil
+}
@@ -0,0 +1,183 @@
+package vectorstores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// https://platform.openai.com/docs/api-reference/vector-stores/object
+type VectorStore struct {
+	Object string `json:"object"` // "vector_store"
+
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt int64  `json:"created_at"`
+
+	Status     string     `json:"status"` // "completed"
+	UsageBytes int64      `json:"usage_bytes"`
+	FileCounts FileCounts `json:"file_counts"`
+
+	Metadata map[string]string `json:"metadata"`
+}
+
+type FileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+// https://platform.openai.com/docs/api-reference/vector-stores/list
+type VectorStoreList struct {
+	Object string `json:"object"` // "list"
+
+	Data []VectorStore `json:"data"`
+
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+
+	HasMore bool `json:"has_more"`
+}
+
+// https://platform.openai.com/docs/api-reference/vector-stores/search
+type SearchRequest struct {
+	Query SearchQuery `json:"query"`
+
+	Filters *Filter `json:"filters,omitempty"`
+
+	MaxNumResults *int `json:"max_num_results,omitempty"`
+}
+
+// SearchQuery accepts a single query string or an array of them.
+type SearchQuery []string
+
+func (q *SearchQuery) UnmarshalJSON(data []byte) error {
+	var text string
+
+	if err := json.Unmarshal(data, &text); err == nil {
+		*q = []string{text}
+		return nil
+	}
+
+	var texts []string
+
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return errors.New("query must be a string or an array of strings")
+	}
+
+	*q = texts
+	return nil
+}
+
+// Filter is either a comparison filter (type eq, ne, ...) or a compound
+// filter (type and, or) holding nested filters.
+type Filter struct {
+	Type string `json:"type"`
+
+	Key   string `json:"key,omitempty"`
+	Value any    `json:"value,omitempty"`
+
+	Filters []Filter `json:"filters,omitempty"`
+}
+
+// Equalities flattens the filter into key/value equality constraints; only
+// "eq" comparisons combined with "and" can be expressed by an index.
+func (f *Filter) Equalities() (map[string]string, error) {
+	result := map[string]string{}
+
+	if f == nil {
+		return result, nil
+	}
+
+	switch f.Type {
+	case "eq":
+		if f.Key == "" {
+			return nil, errors.New("filter key is required")
+		}
+
+		result[f.Key] = fmt.Sprint(f.Value)
+
+	case "and":
+		for _, nested := range f.Filters {
+			values, err := nested.Equalities()
+
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := range values {
+				if existing, ok := result[k]; ok && existing != v {
+					return nil, fmt.Errorf("conflicting filters for key %q", k)
+				}
+
+				result[k] = v
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported filter type: %s", f.Type)
+	}
+
+	return result, nil
+}
+
+// https://platform.openai.com/docs/api-reference/vector-stores/search
+type SearchResultPage struct {
+	Object string `json:"object"` // "vector_store.search_results.page"
+
+	SearchQuery []string       `json:"search_query"`
+	Data        []SearchResult `json:"data"`
+
+	HasMore  bool    `json:"has_more"`
+	NextPage *string `json:"next_page"`
+}
+
+type SearchResult struct {
+	FileID   string `json:"file_id"`
+	Filename string `json:"filename"`
+
+	Score float32 `json:"score"`
+
+	Attributes map[string]string `json:"attributes"`
+
+	Content []SearchContent `json:"content"`
+}
+
+type SearchContent struct {
+	Type string `json:"type"` // "text"
+	Text string `json:"text"`
+}
+
+// Document is a wingman extension to manage the chunks of a vector store
+// directly, without going through file uploads.
+type Document struct {
+	Object string `json:"object,omitempty"` // "vector_store.document"
+
+	ID string `json:"id,omitempty"`
+
+	Title   string `json:"title,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Content string `json:"content"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type DocumentsRequest struct {
+	Documents []Document `json:"documents"`
+}
+
+type DocumentList struct {
+	Object string `json:"object"` // "list"
+
+	Data []Document `json:"data"`
+
+	HasMore bool `json:"has_more"`
+}
+
+type DocumentDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"` // "vector_store.document.deleted"
+	Deleted bool   `json:"deleted"`
+}
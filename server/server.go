@@ -10,6 +10,7 @@ import (
 	"github.com/adrianliechti/wingman/server/gemini"
 	"github.com/adrianliechti/wingman/server/mcp"
 	"github.com/adrianliechti/wingman/server/openai"
+	"github.com/adrianliechti/wingman/server/openai/responses"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -30,10 +31,12 @@ type Server struct {
 	gemini    *gemini.Handler
 }
 
-func New(cfg *config.Config) (*Server, error) {
+// New builds the server for a configuration. Background responses run on
+// background, which outlives the server across config reloads.
+func New(cfg *config.Config, background *responses.Background) (*Server, error) {
 	api := api.New(cfg)
 	mcp := mcp.New(cfg)
-	openai := openai.New(cfg)
+	openai := openai.New(cfg, background)
 	anthropic := anthropic.New(cfg)
 	gemini := gemini.New(cfg)
 
@@ -81,6 +84,9 @@ func New(cfg *config.Config) (*Server, error) {
 		s.mcp.Attach(r)
 		s.openai.Attach(r)
 		s.anthropic.Attach(r)
+
+		r.Get("/models", s.handleModels)
+		r.Get("/models/{id}", s.handleModel)
 	})
 
 	mux.Route("/v1beta", func(r chi.Router) {
@@ -89,7 +95,3 @@ func New(cfg *config.Config) (*Server, error) {
 
 	return s, nil
 }
-
-func (s *Server) ListenAndServe() error {
-	return http.ListenAndServe(s.Address, s)
-}
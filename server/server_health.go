@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+type healthStatus struct {
+	Status string `json:"status"`
+
+	Routers map[string]routerStatus `json:"routers,omitempty"`
+}
+
+type routerStatus struct {
+	Available bool `json:"available"`
+
+	// Providers lists the circuit state of each routed provider:
+	// closed, open or half_open.
+	Providers []string `json:"providers"`
+}
+
+// handleHealth reports liveness: the process is up and serving.
+func (r *Reloader) handleHealth(w http.ResponseWriter, req *http.Request) {
+	writeHealth(w, http.StatusOK, healthStatus{Status: "ok"})
+}
+
+// handleReady reports readiness. It fails while the server drains. With
+// ?routers=true it also fails when any router has no provider with a
+// closed or recoverable circuit left.
+func (r *Reloader) handleReady(w http.ResponseWriter, req *http.Request) {
+	if r.draining.Load() {
+		writeHealth(w, http.StatusServiceUnavailable, healthStatus{Status: "draining"})
+		return
+	}
+
+	result := healthStatus{Status: "ok"}
+
+	if check, _ := strconv.ParseBool(req.URL.Query().Get("routers")); check {
+		cfg := r.Server().Config
+
+		for _, id := range cfg.Routers() {
+			router, err := cfg.Router(id)
+
+			if err != nil {
+				continue
+			}
+
+			status := routerStatus{
+				Available: router.Available(),
+			}
+
+			for _, stats := range router.Stats() {
+				status.Providers = append(status.Providers, stats.Metrics().State.String())
+			}
+
+			if !status.Available {
+				result.Status = "unavailable"
+			}
+
+			if result.Routers == nil {
+				result.Routers = make(map[string]routerStatus)
+			}
+
+			result.Routers[id] = status
+		}
+	}
+
+	code := http.StatusOK
+
+	if result.Status != "ok" {
+		code = http.StatusServiceUnavailable
+	}
+
+	writeHealth(w, code, result)
+}
+
+func writeHealth(w http.ResponseWriter, code int, status healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	json.NewEncoder(w).Encode(status)
+}
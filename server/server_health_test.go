@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadinessFailsWhileDraining(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("providers: []\n"), 0644)
+
+	r, err := NewReloader(path, ":0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := probe("/readyz?routers=true"); code != http.StatusOK {
+		t.Fatalf("expected ready, got %d", code)
+	}
+
+	r.draining.Store(true)
+
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected draining server to be unready, got %d", code)
+	}
+
+	if code := probe("/healthz"); code != http.StatusOK {
+		t.Fatalf("expected draining server to stay live, got %d", code)
+	}
+}
+
+func TestEmptyPathWithoutMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("providers: []\n"), 0644)
+
+	r, err := NewReloader(path, ":0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an empty path must not reach the metrics handler, which is unset
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.Path = ""
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/adrianliechti/wingman/pkg/router"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "github.com/adrianliechti/wingman"
+
+// HandleMetrics serves handler (e.g. the Prometheus registry) on path,
+// next to the health probes and outside authentication.
+func (r *Reloader) HandleMetrics(path string, handler http.Handler) {
+	r.metricsPath = path
+	r.metrics = handler
+}
+
+// registerRouterMetrics observes the health of the routers of the current
+// configuration: availability, circuit state (0 closed, 1 half open,
+// 2 open), first token latency (EMA), error rate (EMA) and requests in
+// flight per routed provider.
+func (r *Reloader) registerRouterMetrics() error {
+	meter := otel.Meter(instrumentationName)
+
+	available, err := meter.Int64ObservableGauge("wingman.router.available",
+		metric.WithDescription("Whether the router can serve requests (1) or not (0)"))
+
+	if err != nil {
+		return err
+	}
+
+	state, err := meter.Int64ObservableGauge("wingman.router.circuit_state",
+		metric.WithDescription("Circuit state of a routed provider: 0 closed, 1 half open, 2 open"))
+
+	if err != nil {
+		return err
+	}
+
+	ttft, err := meter.Float64ObservableGauge("wingman.router.time_to_first_token",
+		metric.WithDescription("Moving average of a routed provider's time to first token"),
+		metric.WithUnit("s"))
+
+	if err != nil {
+		return err
+	}
+
+	errorRate, err := meter.Float64ObservableGauge("wingman.router.error_rate",
+		metric.WithDescription("Moving average of a routed provider's failure rate"))
+
+	if err != nil {
+		return err
+	}
+
+	inflight, err := meter.Int64ObservableGauge("wingman.router.inflight",
+		metric.WithDescription("Requests in flight on a routed provider"))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		s := r.Server()
+
+		if s == nil {
+			return nil
+		}
+
+		for _, id := range s.Routers() {
+			c, err := s.Router(id)
+
+			if err != nil {
+				continue
+			}
+
+			routerAttr := attribute.String("router", id)
+
+			o.ObserveInt64(available, boolValue(c.Available()), metric.WithAttributes(routerAttr))
+
+			names := c.Names()
+
+			for i, stats := range c.Stats() {
+				m := stats.Metrics()
+
+				attrs := metric.WithAttributes(routerAttr, attribute.String("provider", names[i]))
+
+				o.ObserveInt64(state, circuitValue(m.State), attrs)
+				o.ObserveFloat64(ttft, m.TTFT.Seconds(), attrs)
+				o.ObserveFloat64(errorRate, m.ErrorRate, attrs)
+				o.ObserveInt64(inflight, m.Inflight, attrs)
+			}
+		}
+
+		return nil
+	}, available, state, ttft, errorRate, inflight)
+
+	return err
+}
+
+func circuitValue(state router.CircuitState) int64 {
+	switch state {
+	case router.CircuitHalfOpen:
+		return 1
+	case router.CircuitOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func boolValue(v bool) int64 {
+	if v {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRouterMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`
+providers:
+  - type: openai
+    url: http://127.0.0.1:1/v1
+    models:
+      - gpt-a
+      - gpt-b
+
+routers:
+  balanced:
+    type: roundrobin
+    models:
+      - gpt-a
+      - gpt-b
+`), 0644)
+
+	if _, err := NewReloader(path, ":0"); err != nil {
+		t.Fatal(err)
+	}
+
+	var data metricdata.ResourceMetrics
+
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	providers := map[string]bool{}
+	var available bool
+
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+
+			if !ok {
+				continue
+			}
+
+			for _, point := range gauge.DataPoints {
+				switch m.Name {
+				case "wingman.router.available":
+					available = point.Value == 1
+
+				case "wingman.router.circuit_state":
+					name, _ := point.Attributes.Value(attribute.Key("provider"))
+					providers[name.AsString()] = point.Value == 0
+				}
+			}
+		}
+	}
+
+	if !available {
+		t.Fatal("expected router to be reported available")
+	}
+
+	if !providers["gpt-a"] || !providers["gpt-b"] {
+		t.Fatalf("expected closed circuits for both providers, got %v", providers)
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/adrianliechti/wingman/server/anthropic"
+)
+
+// handleModels serves the model list shared by the OpenAI and Anthropic APIs
+// in the format of the calling client.
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	if anthropic.IsClient(r) {
+		s.anthropic.HandleModels(w, r)
+		return
+	}
+
+	s.openai.HandleModels(w, r)
+}
+
+func (s *Server) handleModel(w http.ResponseWriter, r *http.Request) {
+	if anthropic.IsClient(r) {
+		s.anthropic.HandleModel(w, r)
+		return
+	}
+
+	s.openai.HandleModel(w, r)
+}
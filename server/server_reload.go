@@ -0,0 +1,224 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/adrianliechti/wingman/config"
+	"github.com/adrianliechti/wingman/server/openai/responses"
+)
+
+// Reloader serves every request with the Server built from the latest valid
+// configuration. A reload parses the file into a fresh Config and swaps the
+// Server atomically; requests already in flight, including long-running
+// streams, finish on the instance they started on. An invalid file is
+// rejected and the previous configuration stays active. Stateful subsystems
+// (store, limiter, memory indexes, response caches) are handed from one
+// Config to the next as long as their configuration is unchanged, see
+// config.State. Background responses run on a single runner for all servers
+// and save to the store of the latest configuration.
+type Reloader struct {
+	path    string
+	address string
+
+	mu   sync.Mutex
+	hash [sha256.Size]byte
+
+	state *config.State
+
+	background *responses.Background
+
+	server atomic.Pointer[Server]
+
+	draining atomic.Bool
+
+	metrics     http.Handler
+	metricsPath string
+}
+
+func NewReloader(path, address string) (*Reloader, error) {
+	r := &Reloader{
+		path:    path,
+		address: address,
+
+		state: config.NewState(),
+
+		background: responses.NewBackground(),
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	if err := r.registerRouterMetrics(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Reloader) Server() *Server {
+	return r.server.Load()
+}
+
+func (r *Reloader) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.URL.Path == "/healthz":
+		r.handleHealth(w, req)
+
+	case req.URL.Path == "/readyz":
+		r.handleReady(w, req)
+
+	case r.metrics != nil && req.URL.Path == r.metricsPath:
+		r.metrics.ServeHTTP(w, req)
+
+	default:
+		r.server.Load().ServeHTTP(w, req)
+	}
+}
+
+// ListenAndServe serves until ctx is done, then shuts down gracefully:
+// readiness turns unavailable, new connections are refused after delay, and
+// in-flight requests (including streams) get up to timeout to finish before
+// their connections are closed. Background responses still running by then
+// are cancelled and saved as such.
+func (r *Reloader) ListenAndServe(ctx context.Context, delay, timeout time.Duration) error {
+	server := &http.Server{
+		Addr:    r.address,
+		Handler: r,
+
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	result := make(chan error, 1)
+
+	go func() {
+		result <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+
+	case <-ctx.Done():
+	}
+
+	r.draining.Store(true)
+
+	slog.Info("shutting down", "delay", delay, "timeout", timeout)
+
+	// Give load balancers time to observe the failing readiness probe
+	// before the listener goes away.
+	time.Sleep(delay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("shutdown deadline exceeded, closing remaining connections", "error", err)
+		server.Close()
+	}
+
+	if err := r.background.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("background responses did not stop in time", "error", err)
+	}
+
+	return nil
+}
+
+// Reload rebuilds the server from the config file.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := os.ReadFile(r.path)
+
+	if err != nil {
+		return err
+	}
+
+	// Remember the content even if it turns out invalid, so a broken file
+	// is reported once rather than on every poll.
+	r.hash = sha256.Sum256(data)
+
+	cfg, err := config.ParseState(r.path, r.state)
+
+	if err != nil {
+		return err
+	}
+
+	cfg.Address = r.address
+
+	s, err := New(cfg, r.background)
+
+	if err != nil {
+		return err
+	}
+
+	r.server.Store(s)
+	r.background.UseStore(cfg.Store)
+	r.state.Retain(cfg)
+
+	return nil
+}
+
+// Watch reloads on SIGHUP and whenever the content of the config file
+// changes, checking every interval. Polling the content (rather than
+// file events) also catches atomic symlink swaps such as mounted
+// Kubernetes ConfigMaps. It blocks until ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-signals:
+			r.reload("signal")
+
+		case <-ticker.C:
+			if r.changed() {
+				r.reload("file change")
+			}
+		}
+	}
+}
+
+func (r *Reloader) changed() bool {
+	data, err := os.ReadFile(r.path)
+
+	if err != nil {
+		return false
+	}
+
+	hash := sha256.Sum256(data)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !bytes.Equal(hash[:], r.hash[:])
+}
+
+func (r *Reloader) reload(reason string) {
+	if err := r.Reload(); err != nil {
+		slog.Error("config reload failed, keeping current configuration", "path", r.path, "reason", reason, "error", err)
+		return
+	}
+
+	slog.Info("config reloaded", "path", r.path, "reason", reason)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReloaderKeepsServerOnInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(path, []byte("providers: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReloader(path, ":0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := r.Server()
+
+	if r.changed() {
+		t.Fatal("expected unchanged config")
+	}
+
+	os.WriteFile(path, []byte("providers:\n  - type: invalid\n    models: [x]\n"), 0644)
+
+	if !r.changed() {
+		t.Fatal("expected change to be detected")
+	}
+
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected invalid config to be rejected")
+	}
+
+	if r.Server() != first {
+		t.Fatal("expected previous server to stay active")
+	}
+
+	os.WriteFile(path, []byte("providers: []\nstore:\n  type: memory\n"), 0644)
+
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Server() == first || r.Server().Store == nil {
+		t.Fatal("expected new server with reloaded config")
+	}
+}
+
+func TestReloaderKeepsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(path, []byte("providers: []\nstore:\n  type: memory\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReloader(path, ":0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := r.Server().Store
+
+	os.WriteFile(path, []byte("providers: []\nstore:\n  type: memory\nauthorizers: []\n"), 0644)
+
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Server().Store != first {
+		t.Fatal("expected the store to be handed over")
+	}
+
+	os.WriteFile(path, []byte("providers: []\nstore:\n  type: memory\n  limit: 10\n"), 0644)
+
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Server().Store == first {
+		t.Fatal("expected a new store for changed settings")
+	}
+}
@@ -112,7 +112,7 @@ func newWingmanServer(completer provider.Completer, modelID string) *httptest.Se
 	}
 	cfg.RegisterCompleter(modelID, completer)
 
-	handler := responses.New(cfg)
+	handler := responses.New(cfg, responses.NewBackground())
 
 	r := chi.NewRouter()
 	r.Route("/v1", func(r chi.Router) {